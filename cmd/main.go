@@ -15,7 +15,10 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/internal/util"
 	"github.com/tbd54566975/ssi-service/pkg/server"
+	"github.com/tbd54566975/ssi-service/pkg/server/middleware"
+	"github.com/tbd54566975/ssi-service/pkg/service/credential"
 
 	"go.opentelemetry.io/otel/exporters/jaeger"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -69,6 +72,10 @@ func run() error {
 			logrus.SetLevel(level)
 		}
 	}
+	if cfg.Server.LogFormat == "text" {
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}
+	logrus.AddHook(util.NewRedactionHook(cfg.Server.RedactedLogFields...))
 	// set log config from config file
 	if cfg.Server.LogLocation != "" {
 		file, err := os.OpenFile(createLogFile(cfg.Server.LogLocation), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
@@ -109,6 +116,24 @@ func run() error {
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
+	// re-read the config file on SIGHUP, applying the settings which can change while running
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			reloadConfig(cfg)
+		}
+	}()
+
+	// purge credentials past their storage TTL until the server stops
+	stopPurge := make(chan struct{})
+	defer close(stopPurge)
+	for _, s := range ssiServer.GetServices() {
+		if credService, ok := s.(*credential.Service); ok {
+			go credService.RunStoragePurge(stopPurge)
+		}
+	}
+
 	serverErrors := make(chan error, 1)
 
 	// Create a new tracer provider with a batch span processor and the given exporter.
@@ -147,6 +172,34 @@ func run() error {
 	return nil
 }
 
+// reloadConfig loads the config file again and applies any changed reloadable settings. Invalid values are
+// rejected, leaving the running settings as they are.
+func reloadConfig(cfg *config.SSIServiceConfig) {
+	next, err := config.LoadConfig(config.DefaultConfigPath)
+	if err != nil {
+		logrus.WithError(err).Error("main: could not reload config, keeping current settings")
+		return
+	}
+	result, err := config.CompareForReload(*cfg, *next)
+	if err != nil {
+		logrus.WithError(err).Error("main: rejected config reload, keeping current settings")
+		return
+	}
+
+	if next.Server.LogLevel != "" {
+		level, _ := logrus.ParseLevel(next.Server.LogLevel)
+		logrus.SetLevel(level)
+	}
+	middleware.SetLogGetSampleRate(next.Server.LogGetSampleRate)
+	cfg.Server.LogLevel = next.Server.LogLevel
+	cfg.Server.LogGetSampleRate = next.Server.LogGetSampleRate
+
+	logrus.Infof("main: config reloaded, applied changes to: %v", result.Applied)
+	if len(result.RestartRequired) > 0 {
+		logrus.Warnf("main: config changes require a restart to take effect: %v", result.RestartRequired)
+	}
+}
+
 // newTracerProvider returns an OpenTelemetry TracerProvider configured to use
 // the Jaeger exporter that will send spans to the provided url. The returned
 // TracerProvider will also use a Resource configured with all the information
@@ -28,15 +28,19 @@ type SSIServiceConfig struct {
 
 // ServerConfig represents configurable properties for the HTTP server
 type ServerConfig struct {
-	APIHost         string        `toml:"api_host" conf:"default:0.0.0.0:3000"`
-	DebugHost       string        `toml:"debug_host" conf:"default:0.0.0.0:4000"`
-	JagerHost       string        `toml:"jager_host" conf:"http://jaeger:14268/api/traces"`
-	JagerEnabled    bool          `toml:"jager_enabled" conf:"default:false"`
-	ReadTimeout     time.Duration `toml:"read_timeout" conf:"default:5s"`
-	WriteTimeout    time.Duration `toml:"write_timeout" conf:"default:5s"`
-	ShutdownTimeout time.Duration `toml:"shutdown_timeout" conf:"default:5s"`
-	LogLocation     string        `toml:"log_location" conf:"default:log"`
-	LogLevel        string        `toml:"log_level" conf:"default:debug"`
+	APIHost           string        `toml:"api_host" conf:"default:0.0.0.0:3000"`
+	DebugHost         string        `toml:"debug_host" conf:"default:0.0.0.0:4000"`
+	JagerHost         string        `toml:"jager_host" conf:"http://jaeger:14268/api/traces"`
+	JagerEnabled      bool          `toml:"jager_enabled" conf:"default:false"`
+	ReadTimeout       time.Duration `toml:"read_timeout" conf:"default:5s"`
+	WriteTimeout      time.Duration `toml:"write_timeout" conf:"default:5s"`
+	ShutdownTimeout   time.Duration `toml:"shutdown_timeout" conf:"default:5s"`
+	LogLocation       string        `toml:"log_location" conf:"default:log"`
+	LogLevel          string        `toml:"log_level" conf:"default:debug"`
+	LogFormat         string        `toml:"log_format" conf:"default:json"`
+	LogGetSampleRate  float64       `toml:"log_get_sample_rate" conf:"default:1"`
+	RedactedLogFields []string      `toml:"redacted_log_fields"`
+	BaseURL           string        `toml:"base_url" conf:"default:http://localhost:3000"`
 }
 
 // ServicesConfig represents configurable properties for the components of the SSI Service
@@ -50,8 +54,8 @@ type ServicesConfig struct {
 
 	DIDConfig        DIDServiceConfig        `toml:"did,omitempty"`
 	SchemaConfig     SchemaServiceConfig     `toml:"schema,omitempty"`
-	CredentialConfig CredentialServiceConfig `toml:"credential,omitempty"`
 	KeyStoreConfig   KeyStoreServiceConfig   `toml:"keystore,omitempty"`
+	CredentialConfig CredentialServiceConfig `toml:"credential,omitempty"`
 }
 
 // BaseServiceConfig represents configurable properties for a specific component of the SSI Service
@@ -86,13 +90,104 @@ func (s *SchemaServiceConfig) IsEmpty() bool {
 type CredentialServiceConfig struct {
 	*BaseServiceConfig
 	// TODO(gabe) supported key and signature types
+
+	// Paths of claims, using '.' to separate nested properties, which must be present and non-empty in the
+	// data of every issued credential
+	RequiredClaims []string `toml:"required_claims,omitempty"`
+
+	// Credential types which may be issued, in addition to the base VerifiableCredential type. Any type may be
+	// issued when empty.
+	AllowedTypes []string `toml:"allowed_types,omitempty"`
+
+	// Claims, by issuer DID, added to the data of every credential from that issuer. Values provided in a
+	// credential request take precedence over these defaults.
+	IssuerDefaults map[string]map[string]interface{} `toml:"issuer_defaults,omitempty"`
+
+	// Limits on the number of active credentials a subject may hold, by schema
+	SubjectLimits map[string]SubjectLimitConfig `toml:"subject_limits,omitempty"`
+
+	// Whether credential data is rejected for containing properties not defined by its schema, even when the
+	// schema allows additional properties. Schemas may override this default.
+	StrictValidation bool `toml:"strict_validation,omitempty"`
+
+	// What issuance does when a credential references a schema the schema service does not know: "fail" rejects
+	// the credential, "warn" issues it and logs a warning, and "info" issues it and logs at the info level. Empty
+	// means fail.
+	SchemaNotFoundPolicy string `toml:"schema_not_found_policy,omitempty"`
+
+	// Optional encryption at rest for stored credentials
+	Encryption *CredentialEncryptionConfig `toml:"encryption,omitempty"`
+
+	// How long an issuer's signing key may be in use before it should be rotated, as a duration such as "2160h".
+	// Empty means keys have no maximum age.
+	IssuerKeyMaxAge string `toml:"issuer_key_max_age,omitempty"`
+
+	// The precision of credential timestamps, either "seconds" or "milliseconds". Empty means seconds, which
+	// verifiers that reject fractional seconds accept.
+	TimestampPrecision string `toml:"timestamp_precision,omitempty"`
+
+	// How often credentials past their storage TTL are purged, as a duration such as "5m". Empty means every minute.
+	StoragePurgeInterval string `toml:"storage_purge_interval,omitempty"`
+
+	// The most credentials of a batch built at once. Zero means one per CPU.
+	BatchParallelism int `toml:"batch_parallelism,omitempty"`
+
+	// How the DIDs of issuers are resolved, such as when bundling credentials for offline verification
+	DIDResolution DIDResolutionConfig `toml:"did_resolution,omitempty"`
+}
+
+// SubjectLimitConfig caps the number of active (unexpired) credentials of a schema held by a single subject. The
+// policy determines what happens to a request for a credential beyond the limit; "reject" is the only policy.
+type SubjectLimitConfig struct {
+	MaxActive int    `toml:"max_active"`
+	Policy    string `toml:"policy"`
+}
+
+// CredentialEncryptionConfig configures the keys used to encrypt credentials at rest. Keys are base58 encoded
+// 32 byte symmetric keys identified by a version; new credentials are encrypted with the active key version, and
+// credentials encrypted with any other configured version remain readable, which allows keys to be rotated.
+type CredentialEncryptionConfig struct {
+	ActiveKeyVersion string            `toml:"active_key_version"`
+	Keys             map[string]string `toml:"keys"`
+}
+
+func (c *CredentialEncryptionConfig) IsEmpty() bool {
+	if c == nil {
+		return true
+	}
+	return reflect.DeepEqual(c, &CredentialEncryptionConfig{})
+}
+
+// DIDResolutionConfig configures a chain of resolvers, tried in order until one resolves a DID. The "local" resolver
+// reads DIDs created by the DID service. The "driver" resolver uses the gateway configured for a DID's method or,
+// without one, the service's own driver for the method. The "universal" resolver resolves any method through a
+// universal resolver, when one is configured.
+type DIDResolutionConfig struct {
+	// Empty means local, then driver, then universal
+	Order []string `toml:"order,omitempty"`
+	// Gateways by DID method, such as "ion" or "web"
+	Methods map[string]DIDResolverConfig `toml:"methods,omitempty"`
+	// Optionally, a universal resolver tried for DIDs of any method
+	Universal *DIDResolverConfig `toml:"universal,omitempty"`
+}
+
+// DIDResolverConfig configures a remote resolver following the universal resolver's HTTP API, where a DID is resolved
+// by appending it to the endpoint, such as https://resolver.example.com/1.0/identifiers/
+type DIDResolverConfig struct {
+	Endpoint string `toml:"endpoint"`
+	// How long to wait for a response, as a duration such as "5s". Empty means five seconds.
+	Timeout string `toml:"timeout,omitempty"`
+	// Headers sent with every request, such as Authorization
+	Headers map[string]string `toml:"headers,omitempty"`
+	// Optionally, the URL of an HTTP proxy requests are sent through
+	Proxy string `toml:"proxy,omitempty"`
 }
 
 type KeyStoreServiceConfig struct {
 	*BaseServiceConfig
 	// Service key password. Used by a KDF whose key is used by a symmetric cypher for key encryption.
 	// The password is salted before usage.
-	ServiceKeyPassword string
+	ServiceKeyPassword string `toml:"password"`
 }
 
 // LoadConfig attempts to load a TOML config file from the given path, and coerce it into our object model.
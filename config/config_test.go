@@ -18,3 +18,42 @@ func TestConfig(t *testing.T) {
 
 	assert.NotEmpty(t, config.Services.StorageProvider)
 }
+
+func TestCompareForReload(t *testing.T) {
+	current, err := LoadConfig(ConfigFileName)
+	assert.NoError(t, err)
+
+	t.Run("No Changes", func(tt *testing.T) {
+		result, err := CompareForReload(*current, *current)
+		assert.NoError(tt, err)
+		assert.Empty(tt, result.Applied)
+		assert.Empty(tt, result.RestartRequired)
+	})
+
+	t.Run("Reloadable And Restart Required Changes", func(tt *testing.T) {
+		next := *current
+		next.Server.LogLevel = "error"
+		next.Server.LogGetSampleRate = 0.25
+		next.Server.APIHost = "0.0.0.0:8080"
+		next.Services.StorageProvider = "other"
+
+		result, err := CompareForReload(*current, next)
+		assert.NoError(tt, err)
+		assert.ElementsMatch(tt, []string{"server.log_level", "server.log_get_sample_rate"}, result.Applied)
+		assert.ElementsMatch(tt, []string{"server.api_host", "services"}, result.RestartRequired)
+	})
+
+	t.Run("Invalid Values", func(tt *testing.T) {
+		next := *current
+		next.Server.LogLevel = "loud"
+		_, err := CompareForReload(*current, next)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid log_level: loud")
+
+		next = *current
+		next.Server.LogGetSampleRate = 2
+		_, err = CompareForReload(*current, next)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid log_get_sample_rate<2>")
+	})
+}
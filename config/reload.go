@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReloadableSettings are the server settings, by TOML key, which take effect without restarting the service
+var ReloadableSettings = map[string]bool{
+	"log_level":           true,
+	"log_get_sample_rate": true,
+}
+
+// ReloadResult describes how a newly loaded config differs from the running one
+type ReloadResult struct {
+	// Reloadable settings which changed, and will be applied
+	Applied []string
+	// Settings which changed, but only take effect after a restart
+	RestartRequired []string
+}
+
+// CompareForReload validates a newly loaded config against the running config, sorting the changed settings by
+// whether they can be applied while running. An invalid value for any reloadable setting rejects the reload as a
+// whole, so that the running settings are never partially updated.
+func CompareForReload(current, next SSIServiceConfig) (*ReloadResult, error) {
+	if level := next.Server.LogLevel; level != "" {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			return nil, fmt.Errorf("invalid log_level: %s", level)
+		}
+	}
+	if rate := next.Server.LogGetSampleRate; rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("invalid log_get_sample_rate<%v>, must be between 0 and 1", rate)
+	}
+
+	var result ReloadResult
+	currentServer, nextServer := reflect.ValueOf(current.Server), reflect.ValueOf(next.Server)
+	for i := 0; i < currentServer.NumField(); i++ {
+		if reflect.DeepEqual(currentServer.Field(i).Interface(), nextServer.Field(i).Interface()) {
+			continue
+		}
+		key := strings.Split(currentServer.Type().Field(i).Tag.Get("toml"), ",")[0]
+		setting := "server." + key
+		if ReloadableSettings[key] {
+			result.Applied = append(result.Applied, setting)
+		} else {
+			result.RestartRequired = append(result.RestartRequired, setting)
+		}
+	}
+	if !reflect.DeepEqual(current.Services, next.Services) {
+		result.RestartRequired = append(result.RestartRequired, "services")
+	}
+	return &result, nil
+}
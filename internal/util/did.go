@@ -0,0 +1,77 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+)
+
+// DIDPrefix begins every DID
+const DIDPrefix = "did:"
+
+// ValidateDID checks a DID follows the DID syntax: did:<method>:<method-specific-id>, where the method is lowercase
+// letters and digits and the method-specific ID is letters, digits, '.', '-', '_', percent-encoded octets and
+// non-trailing ':'. A DID URL, with a path, query or fragment, is not a DID. The method-specific ID of a DID with a
+// method the service supports is also checked, such as that a did:key decodes to a public key.
+func ValidateDID(did string) error {
+	if !strings.HasPrefix(did, DIDPrefix) {
+		return fmt.Errorf("DID must begin with '%s'", DIDPrefix)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(did, DIDPrefix), ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("DID must have a method and a method-specific ID")
+	}
+	method, id := parts[0], parts[1]
+
+	if method == "" {
+		return fmt.Errorf("DID method cannot be empty")
+	}
+	for _, c := range method {
+		if !isLowerAlphaNumeric(c) {
+			return fmt.Errorf("DID method<%s> may only contain lowercase letters and digits", method)
+		}
+	}
+
+	if id == "" || strings.HasSuffix(id, ":") {
+		return fmt.Errorf("DID method-specific ID cannot be empty or end with ':'")
+	}
+	for i := 0; i < len(id); i++ {
+		c := rune(id[i])
+		switch {
+		case c == '%':
+			if i+2 >= len(id) || !isHexDigit(rune(id[i+1])) || !isHexDigit(rune(id[i+2])) {
+				return fmt.Errorf("DID method-specific ID has an invalid percent-encoding at position %d", i)
+			}
+			i += 2
+		case isIDChar(c) || c == ':':
+		default:
+			return fmt.Errorf("DID method-specific ID may not contain %q", c)
+		}
+	}
+
+	switch method {
+	case "key":
+		if _, _, err := didsdk.DIDKey(did).Decode(); err != nil {
+			return fmt.Errorf("did:key method-specific ID is not a multibase encoded public key")
+		}
+	}
+	return nil
+}
+
+// IsValidDID determines whether a string is a DID, see ValidateDID
+func IsValidDID(did string) bool {
+	return ValidateDID(did) == nil
+}
+
+func isLowerAlphaNumeric(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+func isIDChar(c rune) bool {
+	return isLowerAlphaNumeric(c) || (c >= 'A' && c <= 'Z') || c == '.' || c == '-' || c == '_'
+}
+
+func isHexDigit(c rune) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
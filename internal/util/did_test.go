@@ -0,0 +1,48 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDID(t *testing.T) {
+	_, didKey, err := didsdk.GenerateDIDKey(crypto.Ed25519)
+	require.NoError(t, err)
+
+	for _, did := range []string{
+		"did:abc:123",
+		"did:web:example.com",
+		"did:web:example.com:user:alice",
+		"did:example:a_b-c.d",
+		"did:example:a%20b",
+		string(*didKey),
+	} {
+		assert.NoError(t, ValidateDID(did), did)
+		assert.True(t, IsValidDID(did), did)
+	}
+
+	tests := map[string]string{
+		"abc:123":               "DID must begin with 'did:'",
+		"did:abc":               "DID must have a method and a method-specific ID",
+		"did::123":              "DID method cannot be empty",
+		"did:ABC:123":           "DID method<ABC> may only contain lowercase letters and digits",
+		"did:abc:":              "DID method-specific ID cannot be empty or end with ':'",
+		"did:abc:123:":          "DID method-specific ID cannot be empty or end with ':'",
+		"did:abc:12 3":          "DID method-specific ID may not contain ' '",
+		"did:abc:123#key-1":     "DID method-specific ID may not contain '#'",
+		"did:abc:123/path":      "DID method-specific ID may not contain '/'",
+		"did:abc:1%2":           "DID method-specific ID has an invalid percent-encoding at position 1",
+		"did:key:not-multibase": "did:key method-specific ID is not a multibase encoded public key",
+	}
+	for did, wantErr := range tests {
+		err := ValidateDID(did)
+		if assert.Error(t, err, did) {
+			assert.Equal(t, wantErr, err.Error(), did)
+		}
+		assert.False(t, IsValidDID(did), did)
+	}
+}
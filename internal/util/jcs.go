@@ -0,0 +1,145 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/goccy/go-json"
+)
+
+// CanonicalizeJSON serializes a JSON document following the JSON Canonicalization Scheme (JCS, RFC 8785): object
+// properties are sorted, no whitespace is added, and strings and numbers have a single representation. Equal
+// documents always produce the same bytes, which makes the output suitable for hashing.
+func CanonicalizeJSON(data []byte) ([]byte, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("could not parse JSON for canonicalization: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, document, "$"); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeCanonical writes a value in its canonical form, where path locates the value within the document for errors
+func writeCanonical(buf *bytes.Buffer, value interface{}, path string) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case float64:
+		number, err := canonicalNumber(v)
+		if err != nil {
+			return fmt.Errorf("could not canonicalize value at %s: %w", path, err)
+		}
+		buf.WriteString(number)
+	case string:
+		writeCanonicalString(buf, v)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, element, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		// properties are ordered by their UTF-16 code units, which differs from byte order outside the BMP
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[key], path+"."+key); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("could not canonicalize value at %s: unsupported type %T", path, value)
+	}
+	return nil
+}
+
+// canonicalNumber formats a number as ECMAScript does, which is the representation JCS requires
+func canonicalNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("number<%v> cannot be represented in JSON", f)
+	}
+	if f == 0 {
+		return "0", nil
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	number := strconv.FormatFloat(f, format, -1, 64)
+	if format == 'e' {
+		// Go writes exponents with at least two digits and ECMAScript does not, e.g. 1e-07 rather than 1e-7
+		n := len(number)
+		if n >= 4 && number[n-4] == 'e' && number[n-3] == '-' && number[n-2] == '0' {
+			number = number[:n-2] + number[n-1:]
+		}
+	}
+	return number, nil
+}
+
+// writeCanonicalString escapes only the characters JSON requires to be escaped, using the short forms where they
+// exist
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+func lessUTF16(a, b string) bool {
+	aUnits, bUnits := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(aUnits) && i < len(bUnits); i++ {
+		if aUnits[i] != bUnits[i] {
+			return aUnits[i] < bUnits[i]
+		}
+	}
+	return len(aUnits) < len(bUnits)
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	t.Run("Sorted Properties And Minimal Whitespace", func(tt *testing.T) {
+		canonical, err := CanonicalizeJSON([]byte(`{ "b": [3, {"d": true, "c": null}], "a": "x" }`))
+		assert.NoError(tt, err)
+		assert.Equal(tt, `{"a":"x","b":[3,{"c":null,"d":true}]}`, string(canonical))
+	})
+
+	// from RFC 8785 section 3.2.2
+	t.Run("RFC 8785 Example", func(tt *testing.T) {
+		input := `{
+			"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+			"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+			"literals": [null, true, false]
+		}`
+		canonical, err := CanonicalizeJSON([]byte(input))
+		assert.NoError(tt, err)
+		expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`
+		assert.Equal(tt, expected, string(canonical))
+	})
+
+	// from RFC 8785 section 3.2.3, properties are sorted by UTF-16 code units
+	t.Run("RFC 8785 Sorting", func(tt *testing.T) {
+		input := `{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh",
+			"1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control", "\u00f6": "Latin Small Letter O With Diaeresis"}`
+		canonical, err := CanonicalizeJSON([]byte(input))
+		assert.NoError(tt, err)
+		expected := "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\"," +
+			"\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"
+		assert.Equal(tt, expected, string(canonical))
+	})
+
+	t.Run("Invalid JSON", func(tt *testing.T) {
+		_, err := CanonicalizeJSON([]byte(`{"a":`))
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "could not parse JSON for canonicalization")
+	})
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RedactedValue = "[REDACTED]"
+)
+
+// DefaultRedactedFields are log field names whose values are always masked, matched case-insensitively
+var DefaultRedactedFields = []string{"privateKey", "d", "seed", "password", "ssn"}
+
+// RedactionHook is a logrus hook which masks the values of log fields with sensitive names, including within
+// nested maps, before the entry is written.
+type RedactionHook struct {
+	fields map[string]bool
+}
+
+// NewRedactionHook creates a hook masking the given field names in addition to the default set
+func NewRedactionHook(fields ...string) *RedactionHook {
+	redacted := make(map[string]bool)
+	for _, f := range append(DefaultRedactedFields, fields...) {
+		redacted[strings.ToLower(f)] = true
+	}
+	return &RedactionHook{fields: redacted}
+}
+
+func (h *RedactionHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactionHook) Fire(entry *logrus.Entry) error {
+	for k, v := range entry.Data {
+		entry.Data[k] = h.redact(k, v)
+	}
+	return nil
+}
+
+func (h *RedactionHook) redact(key string, value interface{}) interface{} {
+	if h.fields[strings.ToLower(key)] {
+		return RedactedValue
+	}
+	nested, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	// copy, so that the caller's value is left intact
+	redacted := make(map[string]interface{}, len(nested))
+	for k, v := range nested {
+		redacted[k] = h.redact(k, v)
+	}
+	return redacted
+}
+
+// TruncateSubject describes a credential subject for logging by its ID and the names of its claims, omitting the
+// claim values, which may contain personal data
+func TruncateSubject(subject map[string]interface{}) string {
+	var claims []string
+	for k := range subject {
+		if k != "id" {
+			claims = append(claims, k)
+		}
+	}
+	sort.Strings(claims)
+	return fmt.Sprintf("{id: %v, claims: [%s]}", subject["id"], strings.Join(claims, ", "))
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactionHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(NewRedactionHook("accountNumber"))
+
+	nested := map[string]interface{}{"seed": "nested-seed-value", "name": "test"}
+	logger.WithFields(logrus.Fields{
+		"privateKey":    "private-key-value",
+		"SSN":           "123-45-6789",
+		"accountNumber": "account-number-value",
+		"nested":        nested,
+		"id":            "did:test:123",
+	}).Info("storing")
+
+	out := buf.String()
+	for _, sensitive := range []string{"private-key-value", "123-45-6789", "account-number-value", "nested-seed-value"} {
+		assert.NotContains(t, out, sensitive)
+	}
+	assert.Contains(t, out, RedactedValue)
+	assert.Contains(t, out, "did:test:123")
+	assert.Contains(t, out, "test")
+
+	// the logged value is not modified
+	assert.Equal(t, "nested-seed-value", nested["seed"])
+}
+
+func TestTruncateSubject(t *testing.T) {
+	truncated := TruncateSubject(map[string]interface{}{
+		"id":        "did:test:123",
+		"ssn":       "123-45-6789",
+		"firstName": "Satoshi",
+	})
+	assert.Equal(t, "{id: did:test:123, claims: [firstName, ssn]}", truncated)
+}
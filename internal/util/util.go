@@ -36,6 +36,34 @@ func LoggingErrorMsg(err error, msg string) error {
 	return errors.Wrap(err, msg)
 }
 
+// Logger logs through an entry which may carry fields, such as the ID of the request being served. Its
+// LoggingError, LoggingNewError and LoggingErrorMsg methods combine logging an error with returning it, like the
+// functions of the same names.
+type Logger struct {
+	*logrus.Entry
+}
+
+// NewLogger creates a logger which logs through the standard logger, without any fields
+func NewLogger() Logger {
+	return Logger{Entry: logrus.NewEntry(logrus.StandardLogger())}
+}
+
+func (l Logger) LoggingError(err error) error {
+	l.WithError(err).Error()
+	return err
+}
+
+func (l Logger) LoggingNewError(msg string) error {
+	err := errors.New(msg)
+	l.WithError(err).Error()
+	return err
+}
+
+func (l Logger) LoggingErrorMsg(err error, msg string) error {
+	l.WithError(err).Error(SanitizeLog(msg))
+	return errors.Wrap(err, msg)
+}
+
 // SanitizeLog prevents certain classes of injection attacks before logging
 // https://codeql.github.com/codeql-query-help/go/go-log-injection/
 func SanitizeLog(log string) string {
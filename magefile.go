@@ -66,6 +66,14 @@ func CITest() error {
 	return runCITests()
 }
 
+// Perf runs the performance suite, failing if latency or allocations regress beyond the recorded baselines, and
+// writes its results to perf-results.json. It runs without the race detector, which would distort the measurements.
+func Perf() error {
+	args := []string{"test", "-v", "-count=1", "-tags=jwx_es256k,perf", "-run=TestPerformance", "./pkg/server", "-perf.results=../../perf-results.json"}
+	_, err := sh.Exec(map[string]string{"GO111MODULE": "on"}, ColorizeTestStdout(), os.Stderr, Go, args...)
+	return err
+}
+
 // Spec generates an OpenAPI spec yaml based on code annotations.
 func Spec() error {
 	swagCommand := "swag"
@@ -0,0 +1,190 @@
+// Package client is a Go client for the SSI Service's HTTP API. It sends and receives the request and response types
+// of the service's routers, so it cannot drift from the API it calls.
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+)
+
+// Client calls the SSI Service at a base URL. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	headers    http.Header
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are made with, http.DefaultClient otherwise
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader adds a header, such as Authorization, to every request
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// New creates a client for the service at the given base URL, such as https://ssi.example.com
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid base URL: %s", baseURL)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("base URL must be absolute: %s", baseURL)
+	}
+
+	c := Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		headers:    make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c, nil
+}
+
+// Error is returned for every response with an error status, holding the service's structured error
+type Error struct {
+	StatusCode int
+	Message    string
+	Fields     []framework.FieldError
+	// Set for a conflict, the location of the resource which already exists
+	Location string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ssi service responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("ssi service responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsStatus determines whether an error is a response from the service with the given status code
+func IsStatus(err error, statusCode int) bool {
+	var clientErr *Error
+	return errors.As(err, &clientErr) && clientErr.StatusCode == statusCode
+}
+
+// IsNotFound determines whether an error is a 404 from the service
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+// IsConflict determines whether an error is a 409 from the service
+func IsConflict(err error) bool {
+	return IsStatus(err, http.StatusConflict)
+}
+
+// do sends a request with an optional JSON body, decoding a successful response's JSON body into the response if
+// one is given. Any status of 400 or above is returned as an *Error.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, response interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrapf(err, "could not marshal request to %s %s", method, path)
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bodyReader)
+	if err != nil {
+		return errors.Wrapf(err, "could not create request to %s %s", method, path)
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "could not send request to %s %s", method, path)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "could not read response from %s %s", method, path)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		clientErr := Error{StatusCode: resp.StatusCode, Location: resp.Header.Get("Location")}
+		var errResp framework.ErrorResponse
+		if err := json.Unmarshal(respBytes, &errResp); err == nil {
+			clientErr.Message = errResp.Error
+			clientErr.Fields = errResp.Fields
+		}
+		return &clientErr
+	}
+
+	if response == nil || len(respBytes) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBytes, response); err != nil {
+		return errors.Wrapf(err, "could not decode response from %s %s", method, path)
+	}
+	return nil
+}
+
+// Health checks the service is up
+func (c *Client) Health(ctx context.Context) (*router.GetHealthCheckResponse, error) {
+	var resp router.GetHealthCheckResponse
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Readiness reports whether each of the service's components is ready
+func (c *Client) Readiness(ctx context.Context) (*router.GetReadinessResponse, error) {
+	var resp router.GetReadinessResponse
+	if err := c.do(ctx, http.MethodGet, "/readiness", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Info describes the service's version and the capabilities of each of its components
+func (c *Client) Info(ctx context.Context) (*router.GetInfoResponse, error) {
+	var resp router.GetInfoResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/info", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// pathOf joins escaped path segments to a path prefix
+func pathOf(prefix string, segments ...string) string {
+	escaped := make([]string, 0, len(segments)+1)
+	escaped = append(escaped, prefix)
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	return strings.Join(escaped, "/")
+}
@@ -0,0 +1,347 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+)
+
+const credentialsPath = "/v1/credentials"
+
+// CredentialFilter selects the credentials to list. One of issuer, subject or schema is required.
+type CredentialFilter struct {
+	Issuer string
+	// Either the subject's DID, or an alias registered for it
+	Subject string
+	Schema  string
+	// One of active or expired
+	Status string
+	// With a subject, collapses active credentials from the same issuer and schema to the most recent
+	Dedupe bool
+	// One of 'created asc' or 'created desc'
+	Sort string
+	// Optionally, only these top-level fields of each credential are returned, with the rest left empty
+	Fields []string
+}
+
+func (f CredentialFilter) query() url.Values {
+	query := make(url.Values)
+	setQuery(query, router.IssuerParam, f.Issuer)
+	setQuery(query, router.SubjectParam, f.Subject)
+	setQuery(query, router.SchemaParam, f.Schema)
+	setQuery(query, router.StatusParam, f.Status)
+	setQuery(query, router.SortParam, f.Sort)
+	if f.Dedupe {
+		query.Set(router.DedupeParam, "true")
+	}
+	if len(f.Fields) > 0 {
+		query.Set(framework.FieldsParam, strings.Join(f.Fields, ","))
+	}
+	return query
+}
+
+// ExpiringFilter selects the credentials expiring within a window from now
+type ExpiringFilter struct {
+	// A number of days such as 30d, or a duration such as 36h
+	Within string
+	Issuer string
+}
+
+func (f ExpiringFilter) query() url.Values {
+	query := make(url.Values)
+	setQuery(query, router.WithinParam, f.Within)
+	setQuery(query, router.IssuerParam, f.Issuer)
+	return query
+}
+
+// DeleteFilter selects the credentials to delete. At least one of issuer, subject or schema is required.
+type DeleteFilter struct {
+	Issuer  string
+	Subject string
+	Schema  string
+	// Counts and lists the matching credentials without deleting them
+	DryRun bool
+}
+
+// CreateCredential issues a credential
+func (c *Client) CreateCredential(ctx context.Context, request router.CreateCredentialRequest) (*router.CreateCredentialResponse, error) {
+	var resp router.CreateCredentialResponse
+	if err := c.do(ctx, http.MethodPut, credentialsPath, nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BatchCreateCredentials issues a set of credentials, all or none of which are issued
+func (c *Client) BatchCreateCredentials(ctx context.Context, request router.BatchCreateCredentialsRequest) (*router.BatchCreateCredentialsResponse, error) {
+	var resp router.BatchCreateCredentialsResponse
+	if err := c.do(ctx, http.MethodPut, credentialsPath+"/batch", nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateCredentialsFromCSV issues a credential for each row of a CSV document, all or none of which are issued
+func (c *Client) CreateCredentialsFromCSV(ctx context.Context, request router.CreateCredentialsFromCSVRequest) (*router.BatchCreateCredentialsResponse, error) {
+	var resp router.BatchCreateCredentialsResponse
+	if err := c.do(ctx, http.MethodPost, credentialsPath+"/from-csv", nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ValidateCredential validates credential data against a schema without issuing a credential
+func (c *Client) ValidateCredential(ctx context.Context, request router.ValidateCredentialRequest) (*router.ValidateCredentialResponse, error) {
+	var resp router.ValidateCredentialResponse
+	if err := c.do(ctx, http.MethodPost, credentialsPath+"/validate", nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExpandCredential expands a JSON-LD credential
+func (c *Client) ExpandCredential(ctx context.Context, request router.ExpandCredentialRequest) (*router.ExpandCredentialResponse, error) {
+	var resp router.ExpandCredentialResponse
+	if err := c.do(ctx, http.MethodPost, credentialsPath+"/expand", nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// MatchCredentials evaluates credentials against a presentation definition
+func (c *Client) MatchCredentials(ctx context.Context, request router.MatchCredentialsRequest) (*router.MatchCredentialsResponse, error) {
+	var resp router.MatchCredentialsResponse
+	if err := c.do(ctx, http.MethodPost, credentialsPath+"/match", nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCredential gets a credential by ID
+func (c *Client) GetCredential(ctx context.Context, id string) (*router.GetCredentialResponse, error) {
+	var resp router.GetCredentialResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(credentialsPath, id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCanonicalCredential gets a credential by ID in canonical form, with its digest
+func (c *Client) GetCanonicalCredential(ctx context.Context, id string) (*router.GetCanonicalCredentialResponse, error) {
+	query := url.Values{router.CanonicalParam: []string{"true"}}
+	var resp router.GetCanonicalCredentialResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(credentialsPath, id), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BatchGetCredentials gets credentials by their IDs
+func (c *Client) BatchGetCredentials(ctx context.Context, request router.BatchGetCredentialsRequest) (*router.BatchGetCredentialsResponse, error) {
+	var resp router.BatchGetCredentialsResponse
+	if err := c.do(ctx, http.MethodPost, credentialsPath+"/batch-get", nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetExpiringCredentials lists every credential expiring within a window in one response, soonest first
+func (c *Client) GetExpiringCredentials(ctx context.Context, filter ExpiringFilter) (*router.GetExpiringCredentialsResponse, error) {
+	var resp router.GetExpiringCredentialsResponse
+	if err := c.do(ctx, http.MethodGet, credentialsPath+"/expiring", filter.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetExpiringCredentialsPage gets one page of the credentials expiring within a window, soonest first. The page's
+// items are a []credsdk.VerifiableCredential. The first page is requested with an empty page token.
+func (c *Client) GetExpiringCredentialsPage(ctx context.Context, filter ExpiringFilter, pageSize int, pageToken string) (*framework.Page, error) {
+	query := filter.query()
+	setPageQuery(query, pageSize, pageToken)
+	var creds []credsdk.VerifiableCredential
+	resp := framework.Page{Items: &creds}
+	if err := c.do(ctx, http.MethodGet, credentialsPath+"/expiring", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	resp.Items = creds
+	return &resp, nil
+}
+
+// GetCredentials lists every credential matching a filter in one response
+func (c *Client) GetCredentials(ctx context.Context, filter CredentialFilter) (*router.GetCredentialsResponse, error) {
+	var resp router.GetCredentialsResponse
+	if err := c.do(ctx, http.MethodGet, credentialsPath, filter.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCredentialsPage gets one page of the credentials matching a filter. The page's items are a
+// []credsdk.VerifiableCredential. The first page is requested with an empty page token.
+func (c *Client) GetCredentialsPage(ctx context.Context, filter CredentialFilter, pageSize int, pageToken string) (*router.GetCredentialsPage, error) {
+	query := filter.query()
+	setPageQuery(query, pageSize, pageToken)
+	var creds []credsdk.VerifiableCredential
+	resp := router.GetCredentialsPage{Page: framework.Page{Items: &creds}}
+	if err := c.do(ctx, http.MethodGet, credentialsPath, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	resp.Items = creds
+	return &resp, nil
+}
+
+// GetAllCredentials lists every credential matching a filter, following pages of the largest size
+func (c *Client) GetAllCredentials(ctx context.Context, filter CredentialFilter) ([]credsdk.VerifiableCredential, error) {
+	var all []credsdk.VerifiableCredential
+	pageToken := ""
+	for {
+		page, err := c.GetCredentialsPage(ctx, filter, framework.MaxPageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Items.([]credsdk.VerifiableCredential)...)
+		if page.NextPageToken == "" {
+			return all, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// GetCredentialStatus gets whether a credential is active or expired
+func (c *Client) GetCredentialStatus(ctx context.Context, id string) (*router.GetCredentialStatusResponse, error) {
+	var resp router.GetCredentialStatusResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(credentialsPath, id, "status"), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCredentialBundle gets a credential with its schema and its issuer's DID document, to verify it offline
+func (c *Client) GetCredentialBundle(ctx context.Context, id string) (*router.GetCredentialBundleResponse, error) {
+	var resp router.GetCredentialBundleResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(credentialsPath, id, "bundle"), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetIssuerKeyHealth reports on the age of an issuer's signing key
+func (c *Client) GetIssuerKeyHealth(ctx context.Context, issuer string) (*router.GetIssuerKeyHealthResponse, error) {
+	var resp router.GetIssuerKeyHealthResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(credentialsPath+"/issuers", issuer, "key-health"), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetIssuanceFreeze freezes or unfreezes issuance, for one issuer or all of them
+func (c *Client) SetIssuanceFreeze(ctx context.Context, request router.SetIssuanceFreezeRequest) (*router.GetIssuanceFreezeResponse, error) {
+	var resp router.GetIssuanceFreezeResponse
+	if err := c.do(ctx, http.MethodPut, credentialsPath+"/freeze", nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetIssuanceFreeze gets which issuers, if any, issuance is frozen for
+func (c *Client) GetIssuanceFreeze(ctx context.Context) (*router.GetIssuanceFreezeResponse, error) {
+	var resp router.GetIssuanceFreezeResponse
+	if err := c.do(ctx, http.MethodGet, credentialsPath+"/freeze", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetSubjectAlias registers an alias which may be given in place of a subject's DID
+func (c *Client) SetSubjectAlias(ctx context.Context, request router.SetSubjectAliasRequest) (*router.GetSubjectAliasResponse, error) {
+	var resp router.GetSubjectAliasResponse
+	if err := c.do(ctx, http.MethodPut, credentialsPath+"/aliases", nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSubjectAliases gets every registered subject alias
+func (c *Client) GetSubjectAliases(ctx context.Context) (*router.GetSubjectAliasesResponse, error) {
+	var resp router.GetSubjectAliasesResponse
+	if err := c.do(ctx, http.MethodGet, credentialsPath+"/aliases", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSubjectAliasesPage gets one page of the registered subject aliases. The page's items are a
+// []router.GetSubjectAliasResponse. The first page is requested with an empty page token.
+func (c *Client) GetSubjectAliasesPage(ctx context.Context, pageSize int, pageToken string) (*framework.Page, error) {
+	query := make(url.Values)
+	setPageQuery(query, pageSize, pageToken)
+	var aliases []router.GetSubjectAliasResponse
+	resp := framework.Page{Items: &aliases}
+	if err := c.do(ctx, http.MethodGet, credentialsPath+"/aliases", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	resp.Items = aliases
+	return &resp, nil
+}
+
+// GetSubjectAlias gets the DID a subject alias resolves to
+func (c *Client) GetSubjectAlias(ctx context.Context, alias string) (*router.GetSubjectAliasResponse, error) {
+	var resp router.GetSubjectAliasResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(credentialsPath+"/aliases", alias), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteSubjectAlias deletes a subject alias
+func (c *Client) DeleteSubjectAlias(ctx context.Context, alias string) error {
+	return c.do(ctx, http.MethodDelete, pathOf(credentialsPath+"/aliases", alias), nil, nil, nil)
+}
+
+// DeleteCredential deletes a credential by ID
+func (c *Client) DeleteCredential(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, pathOf(credentialsPath, id), nil, nil, nil)
+}
+
+// DeleteCredentials deletes every credential matching a filter
+func (c *Client) DeleteCredentials(ctx context.Context, filter DeleteFilter) (*router.DeleteCredentialsResponse, error) {
+	query := make(url.Values)
+	setQuery(query, router.IssuerParam, filter.Issuer)
+	setQuery(query, router.SubjectParam, filter.Subject)
+	setQuery(query, router.SchemaParam, filter.Schema)
+	if filter.DryRun {
+		query.Set(router.DryRunParam, "true")
+	}
+	var resp router.DeleteCredentialsResponse
+	if err := c.do(ctx, http.MethodDelete, credentialsPath, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// setQuery sets a query parameter if it has a value
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+// setPageQuery requests a page of a list
+func setPageQuery(query url.Values, pageSize int, pageToken string) {
+	if pageSize > 0 {
+		query.Set(framework.PageSizeParam, strconv.Itoa(pageSize))
+	}
+	setQuery(query, framework.PageTokenParam, pageToken)
+	// a page is only returned when asked for, so ask with the default size when neither is given
+	if len(query.Get(framework.PageSizeParam)) == 0 && len(query.Get(framework.PageTokenParam)) == 0 {
+		query.Set(framework.PageSizeParam, strconv.Itoa(framework.DefaultPageSize))
+	}
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+)
+
+const didsPath = "/v1/dids"
+
+// GetDIDMethods gets the DID methods the service supports
+func (c *Client) GetDIDMethods(ctx context.Context) (*router.GetDIDMethodsResponse, error) {
+	var resp router.GetDIDMethodsResponse
+	if err := c.do(ctx, http.MethodGet, didsPath, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateDIDByMethod creates a DID of the given method
+func (c *Client) CreateDIDByMethod(ctx context.Context, method string, request router.CreateDIDByMethodRequest) (*router.CreateDIDByMethodResponse, error) {
+	var resp router.CreateDIDByMethodResponse
+	if err := c.do(ctx, http.MethodPut, pathOf(didsPath, method), nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetDIDByMethod gets a DID of the given method by its ID
+func (c *Client) GetDIDByMethod(ctx context.Context, method, id string) (*router.GetDIDByMethodResponse, error) {
+	var resp router.GetDIDByMethodResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(didsPath, method, id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetDIDDisplay sets how a DID is presented to people, such as its name and logo
+func (c *Client) SetDIDDisplay(ctx context.Context, method, id string, request router.SetDIDDisplayRequest) (*router.GetDIDDisplayResponse, error) {
+	var resp router.GetDIDDisplayResponse
+	if err := c.do(ctx, http.MethodPut, pathOf(didsPath, method, id, "display"), nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetDIDDisplay gets how a DID is presented to people
+func (c *Client) GetDIDDisplay(ctx context.Context, method, id string) (*router.GetDIDDisplayResponse, error) {
+	var resp router.GetDIDDisplayResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(didsPath, method, id, "display"), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
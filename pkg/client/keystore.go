@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+)
+
+const keysPath = "/v1/keys"
+
+// StoreKey stores a private key. Storing a key with an ID already in use is a conflict, whose error's Location is
+// the existing key.
+func (c *Client) StoreKey(ctx context.Context, request router.StoreKeyRequest) error {
+	return c.do(ctx, http.MethodPut, keysPath, nil, request, nil)
+}
+
+// GetKeyDetails gets the details of a stored key, without its private key material
+func (c *Client) GetKeyDetails(ctx context.Context, id string) (*router.GetKeyDetailsResponse, error) {
+	var resp router.GetKeyDetailsResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(keysPath, id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
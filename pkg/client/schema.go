@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	schemalib "github.com/TBD54566975/ssi-sdk/credential/schema"
+
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+)
+
+const schemasPath = "/v1/schemas"
+
+// CreateSchema creates a schema
+func (c *Client) CreateSchema(ctx context.Context, request router.CreateSchemaRequest) (*router.CreateSchemaResponse, error) {
+	var resp router.CreateSchemaResponse
+	if err := c.do(ctx, http.MethodPut, schemasPath, nil, request, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSchemas lists every schema in one response, sorted by one of 'created asc' or 'created desc' if given
+func (c *Client) GetSchemas(ctx context.Context, sort string) (*router.GetSchemasResponse, error) {
+	query := make(url.Values)
+	setQuery(query, router.SortParam, sort)
+	var resp router.GetSchemasResponse
+	if err := c.do(ctx, http.MethodGet, schemasPath, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSchemasPage gets one page of the schemas. The page's items are a []schemalib.VCJSONSchema.
+// The first page is requested with an empty page token.
+func (c *Client) GetSchemasPage(ctx context.Context, sort string, pageSize int, pageToken string) (*framework.Page, error) {
+	query := make(url.Values)
+	setQuery(query, router.SortParam, sort)
+	setPageQuery(query, pageSize, pageToken)
+	var schemas []schemalib.VCJSONSchema
+	resp := framework.Page{Items: &schemas}
+	if err := c.do(ctx, http.MethodGet, schemasPath, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	resp.Items = schemas
+	return &resp, nil
+}
+
+// GetAllSchemas lists every schema, following pages of the largest size
+func (c *Client) GetAllSchemas(ctx context.Context, sort string) ([]schemalib.VCJSONSchema, error) {
+	var all []schemalib.VCJSONSchema
+	pageToken := ""
+	for {
+		page, err := c.GetSchemasPage(ctx, sort, framework.MaxPageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Items.([]schemalib.VCJSONSchema)...)
+		if page.NextPageToken == "" {
+			return all, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// GetSchemaByID gets a schema by its ID
+func (c *Client) GetSchemaByID(ctx context.Context, id string) (*router.GetSchemaResponse, error) {
+	var resp router.GetSchemaResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(schemasPath, id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSchemaForm gets a description of the form fields for filling in a schema's credential data
+func (c *Client) GetSchemaForm(ctx context.Context, id string) (*router.GetSchemaFormResponse, error) {
+	var resp router.GetSchemaFormResponse
+	if err := c.do(ctx, http.MethodGet, pathOf(schemasPath, id, "form"), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
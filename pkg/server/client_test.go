@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/pkg/client"
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+func TestClient(t *testing.T) {
+	// remove the db file after the test
+	t.Cleanup(func() {
+		_ = os.Remove(storage.DBFile)
+	})
+
+	serviceConfig, err := config.LoadConfig("")
+	require.NoError(t, err)
+	ssiServer, err := NewSSIServer(make(chan os.Signal, 1), *serviceConfig)
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(ssiServer.Server)
+	t.Cleanup(httpServer.Close)
+
+	c, err := client.New(httpServer.URL, client.WithHTTPClient(httpServer.Client()))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("Bad Base URL", func(tt *testing.T) {
+		_, err := client.New("ssi-service.com")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "base URL must be absolute")
+	})
+
+	t.Run("Health And Info", func(tt *testing.T) {
+		health, err := c.Health(ctx)
+		require.NoError(tt, err)
+		assert.Equal(tt, router.HealthOK, health.Status)
+
+		info, err := c.Info(ctx)
+		require.NoError(tt, err)
+		assert.Len(tt, info.Services, 4)
+	})
+
+	t.Run("Keys", func(tt *testing.T) {
+		_, privKey, err := crypto.GenerateKeyByKeyType(crypto.Ed25519)
+		require.NoError(tt, err)
+		privKeyBytes, err := crypto.PrivKeyToBytes(privKey)
+		require.NoError(tt, err)
+
+		// the ID needs escaping in the path
+		storeKeyRequest := router.StoreKeyRequest{
+			ID:               "did:test:client#key-1",
+			Type:             crypto.Ed25519,
+			Controller:       "did:test:client",
+			Base58PrivateKey: base58.Encode(privKeyBytes),
+		}
+		err = c.StoreKey(ctx, storeKeyRequest)
+		require.NoError(tt, err)
+
+		details, err := c.GetKeyDetails(ctx, storeKeyRequest.ID)
+		require.NoError(tt, err)
+		assert.Equal(tt, storeKeyRequest.ID, details.ID)
+		assert.Equal(tt, storeKeyRequest.Controller, details.Controller)
+
+		// storing it again conflicts, pointing at the existing key
+		err = c.StoreKey(ctx, storeKeyRequest)
+		assert.True(tt, client.IsConflict(err))
+		var clientErr *client.Error
+		require.ErrorAs(tt, err, &clientErr)
+		assert.Equal(tt, "/v1/keys/did:test:client%23key-1", clientErr.Location)
+
+		_, err = c.GetKeyDetails(ctx, "did:test:client#key-2")
+		assert.Error(tt, err)
+		assert.False(tt, client.IsConflict(err))
+	})
+
+	t.Run("DIDs", func(tt *testing.T) {
+		methods, err := c.GetDIDMethods(ctx)
+		require.NoError(tt, err)
+		assert.NotEmpty(tt, methods.DIDMethods)
+
+		created, err := c.CreateDIDByMethod(ctx, "key", router.CreateDIDByMethodRequest{KeyType: crypto.Ed25519})
+		require.NoError(tt, err)
+
+		got, err := c.GetDIDByMethod(ctx, "key", created.DID.ID)
+		require.NoError(tt, err)
+		assert.Equal(tt, created.DID.ID, got.DID.ID)
+
+		_, err = c.GetDIDByMethod(ctx, "key", "did:key:missing")
+		assert.True(tt, client.IsStatus(err, http.StatusBadRequest))
+	})
+
+	t.Run("Schemas", func(tt *testing.T) {
+		simpleSchema := map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"foo": map[string]interface{}{
+					"type": "string",
+				},
+			},
+			"required": []interface{}{"foo"},
+		}
+		var ids []string
+		for i := 0; i < 3; i++ {
+			created, err := c.CreateSchema(ctx, router.CreateSchemaRequest{Author: "did:test:client", Name: fmt.Sprintf("schema %d", i), Schema: simpleSchema})
+			require.NoError(tt, err)
+			ids = append(ids, created.ID)
+		}
+
+		got, err := c.GetSchemaByID(ctx, ids[0])
+		require.NoError(tt, err)
+		assert.Equal(tt, ids[0], got.Schema.ID)
+
+		page, err := c.GetSchemasPage(ctx, "", 2, "")
+		require.NoError(tt, err)
+		assert.Equal(tt, 3, page.TotalCount)
+		assert.NotEmpty(tt, page.NextPageToken)
+
+		all, err := c.GetAllSchemas(ctx, "")
+		require.NoError(tt, err)
+		assert.Len(tt, all, 3)
+
+		// a missing schema is a structured error
+		_, err = c.GetSchemaByID(ctx, "missing")
+		var clientErr *client.Error
+		require.ErrorAs(tt, err, &clientErr)
+		assert.Equal(tt, http.StatusBadRequest, clientErr.StatusCode)
+		assert.Contains(tt, clientErr.Message, "could not get schema with id: missing")
+	})
+
+	t.Run("Credentials", func(tt *testing.T) {
+		issuer := "did:test:client-issuer"
+		for i := 0; i < 3; i++ {
+			_, err := c.CreateCredential(ctx, router.CreateCredentialRequest{
+				Issuer:  issuer,
+				Subject: fmt.Sprintf("did:test:holder%d", i),
+				Data:    map[string]interface{}{"index": i},
+			})
+			require.NoError(tt, err)
+		}
+
+		page, err := c.GetCredentialsPage(ctx, client.CredentialFilter{Issuer: issuer}, 2, "")
+		require.NoError(tt, err)
+		assert.Equal(tt, 3, page.TotalCount)
+		assert.NotEmpty(tt, page.NextPageToken)
+
+		all, err := c.GetAllCredentials(ctx, client.CredentialFilter{Issuer: issuer})
+		require.NoError(tt, err)
+		require.Len(tt, all, 3)
+
+		// selecting fields leaves the rest of each credential empty
+		selected, err := c.GetCredentials(ctx, client.CredentialFilter{Issuer: issuer, Fields: []string{"id"}})
+		require.NoError(tt, err)
+		require.Len(tt, selected.Credentials, 3)
+		assert.NotEmpty(tt, selected.Credentials[0].ID)
+		assert.Empty(tt, selected.Credentials[0].CredentialSubject)
+
+		got, err := c.GetCredential(ctx, all[0].ID)
+		require.NoError(tt, err)
+		assert.Equal(tt, all[0].ID, got.ID)
+
+		deleted, err := c.DeleteCredentials(ctx, client.DeleteFilter{Issuer: issuer})
+		require.NoError(tt, err)
+		assert.Equal(tt, 3, deleted.Count)
+
+		_, err = c.GetCredential(ctx, all[0].ID)
+		assert.True(tt, client.IsNotFound(err))
+
+		// a filter is required
+		_, err = c.GetCredentials(ctx, client.CredentialFilter{})
+		var clientErr *client.Error
+		require.ErrorAs(tt, err, &clientErr)
+		assert.Equal(tt, http.StatusBadRequest, clientErr.StatusCode)
+		assert.NotEmpty(tt, clientErr.Message)
+
+		// invalid fields are reported individually
+		_, err = c.CreateCredential(ctx, router.CreateCredentialRequest{Issuer: "issuer", Subject: "did:test:holder", Data: map[string]interface{}{}})
+		require.ErrorAs(tt, err, &clientErr)
+		assert.Equal(tt, http.StatusBadRequest, clientErr.StatusCode)
+		require.Len(tt, clientErr.Fields, 1)
+		assert.Equal(tt, "issuer", clientErr.Fields[0].Field)
+	})
+}
@@ -1,6 +1,12 @@
 package framework
 
-import "github.com/pkg/errors"
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/pkg/errors"
+)
 
 // FieldError is used to indicate an error with a field in a request payload.
 type FieldError struct {
@@ -37,9 +43,34 @@ func NewRequestErrorMsg(errMsg string, statusCode int) error {
 }
 
 // NewRequestError wraps a provided error with an HTTP status code. This function should be used
-// when router encounter expected errors.
+// when router encounter expected errors. The field errors of a wrapped SafeError, such as a failure to validate a
+// request, are kept.
 func NewRequestError(err error, statusCode int) error {
-	return &SafeError{err, statusCode, nil}
+	var fields []FieldError
+	var safeErr *SafeError
+	if errors.As(err, &safeErr) {
+		fields = safeErr.Fields
+	}
+	return &SafeError{err, statusCode, fields}
+}
+
+// existingResource is implemented by errors reporting that a resource could not be created because another already
+// has the ID it was given
+type existingResource interface {
+	ExistingID() string
+}
+
+// NewConflictError turns an error reporting that a resource already exists into a 409, with a Location header pointing
+// at the existing resource within the collection the request was made to. It returns nil for any other error, so
+// routers can check for a conflict before handling other errors.
+func NewConflictError(w http.ResponseWriter, r *http.Request, err error) error {
+	var existing existingResource
+	if !errors.As(err, &existing) {
+		return nil
+	}
+	id := existing.ExistingID()
+	w.Header().Set("Location", path.Join(r.URL.Path, url.PathEscape(id)))
+	return &SafeError{Err: err, StatusCode: http.StatusConflict, Fields: []FieldError{{Field: "id", Error: "already exists: " + id}}}
 }
 
 // shutdown is a type used to help with graceful shutdown of a server.
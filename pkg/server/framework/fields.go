@@ -0,0 +1,127 @@
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+// FieldsParam selects, as a comma separated list, the top-level fields of each resource in a response
+const FieldsParam string = "fields"
+
+// GetFieldsParam gets the fields selected by a request, validated against the JSON field names of the resource they
+// select from. It returns nil when no fields are selected, in which case the full resource is returned.
+func GetFieldsParam(r *http.Request, resource interface{}) ([]string, error) {
+	value := GetQueryValue(r, FieldsParam)
+	if value == nil {
+		return nil, nil
+	}
+
+	valid := JSONFieldNames(resource)
+	validSet := make(map[string]bool, len(valid))
+	for _, name := range valid {
+		validSet[name] = true
+	}
+
+	var fields, unknown []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(*value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		if !validSet[field] {
+			unknown = append(unknown, field)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown %s<%s>, must be one or more of: %s", FieldsParam, strings.Join(unknown, ","), strings.Join(valid, ", "))
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s cannot be empty", FieldsParam)
+	}
+	return fields, nil
+}
+
+// JSONFieldNames gets the names of a struct's top-level JSON fields, in sorted order, including those of embedded
+// structs
+func JSONFieldNames(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var names []string
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			names = append(names, JSONFieldNames(reflect.New(field.Type).Elem().Interface())...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectFields narrows the resource found at a key of a response, or each resource if there is an array of them, to
+// the selected fields. With an empty key, the response is itself the resource. A field absent from a resource, such
+// as one omitted when empty, stays absent.
+func SelectFields(response interface{}, key string, fields []string) (interface{}, error) {
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal response to select fields")
+	}
+	var shaped interface{}
+	if err := json.Unmarshal(responseBytes, &shaped); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal response to select fields")
+	}
+
+	if key == "" {
+		return selectObjectFields(shaped, fields), nil
+	}
+	object, ok := shaped.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot select fields at key<%s> of a response which is not an object", key)
+	}
+	object[key] = selectObjectFields(object[key], fields)
+	return object, nil
+}
+
+// selectObjectFields keeps only the selected fields of an object, or of each object in an array
+func selectObjectFields(value interface{}, fields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		selected := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if fieldValue, ok := v[field]; ok {
+				selected[field] = fieldValue
+			}
+		}
+		return selected
+	case []interface{}:
+		for i := range v {
+			v[i] = selectObjectFields(v[i], fields)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,108 @@
+package framework
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	PageSizeParam  string = "pageSize"
+	PageTokenParam string = "pageToken"
+
+	// DefaultPageSize is how many items a page holds when only a page token is given
+	DefaultPageSize = 50
+	// MaxPageSize is the most items a page may hold
+	MaxPageSize = 100
+)
+
+// PageRequest asks for one page of a list
+type PageRequest struct {
+	Size   int
+	Offset int
+}
+
+// Page is the response shared by all paginated list endpoints
+type Page struct {
+	Items interface{} `json:"items"`
+	// Set when there are more items, the token to request the next page with
+	NextPageToken string `json:"nextPageToken,omitempty"`
+	// The number of items across all pages
+	TotalCount int `json:"totalCount"`
+}
+
+// GetPageRequest reads a page request from the pageSize and pageToken query parameters, nil if neither is given.
+// Lists are only paginated when asked to be, so that existing clients keep getting each list's original response.
+func GetPageRequest(r *http.Request) (*PageRequest, error) {
+	size, token := GetQueryValue(r, PageSizeParam), GetQueryValue(r, PageTokenParam)
+	if size == nil && token == nil {
+		return nil, nil
+	}
+
+	request := PageRequest{Size: DefaultPageSize}
+	if size != nil {
+		parsed, err := strconv.Atoi(*size)
+		if err != nil || parsed < 1 || parsed > MaxPageSize {
+			return nil, fmt.Errorf("%s must be between 1 and %d", PageSizeParam, MaxPageSize)
+		}
+		request.Size = parsed
+	}
+	if token != nil {
+		offset, err := decodePageToken(*token)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", PageTokenParam)
+		}
+		request.Offset = offset
+	}
+	return &request, nil
+}
+
+// NewPage takes the requested page from a slice of items, in the order given
+func NewPage(items interface{}, request PageRequest) (*Page, error) {
+	value := reflect.ValueOf(items)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot paginate a %s", value.Kind())
+	}
+
+	total := value.Len()
+	start, end := request.Offset, request.Offset+request.Size
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	// an empty page is listed as such, rather than as null
+	pageItems := reflect.MakeSlice(value.Type(), 0, end-start)
+	pageItems = reflect.AppendSlice(pageItems, value.Slice(start, end))
+	page := Page{Items: pageItems.Interface(), TotalCount: total}
+	if end < total {
+		page.NextPageToken = encodePageToken(end)
+	}
+	return &page, nil
+}
+
+// encodePageToken makes an opaque token for the page starting at an offset
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(string(decoded))
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	}
+	return offset, nil
+}
@@ -2,6 +2,7 @@ package framework
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
@@ -13,6 +14,8 @@ import (
 	ut "github.com/go-playground/universal-translator"
 	"gopkg.in/go-playground/validator.v9"
 	entranslations "gopkg.in/go-playground/validator.v9/translations/en"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
 )
 
 // validate holds the settings and caches for validating request payloads.
@@ -36,6 +39,24 @@ func init() {
 	lang, _ := translator.GetTranslator("en")
 	_ = entranslations.RegisterDefaultTranslations(validate, lang)
 
+	// DIDs are checked against the DID syntax. A subject may also be given by alias, in which case only a value
+	// which looks like a DID is checked.
+	_ = validate.RegisterValidation("did", func(fl validator.FieldLevel) bool {
+		return util.IsValidDID(fl.Field().String())
+	})
+	_ = validate.RegisterValidation("did_or_alias", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		return !strings.HasPrefix(value, util.DIDPrefix) || util.IsValidDID(value)
+	})
+	// A template's DID may instead reference a CSV row's value, as ${column}, and is checked once filled in
+	_ = validate.RegisterValidation("did_or_template", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		return strings.Contains(value, "${") || util.IsValidDID(value)
+	})
+	for _, tag := range []string{"did", "did_or_alias", "did_or_template"} {
+		_ = validate.RegisterTranslation(tag, lang, func(ut.Translator) error { return nil }, translateDIDError)
+	}
+
 	// Use JSON tag names for errors instead of Go struct field names
 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
@@ -47,6 +68,15 @@ func init() {
 	})
 }
 
+// translateDIDError explains why a field's value is not a valid DID
+func translateDIDError(_ ut.Translator, fe validator.FieldError) string {
+	value, _ := fe.Value().(string)
+	if err := util.ValidateDID(value); err != nil {
+		return fmt.Sprintf("%s is not a valid DID: %s", fe.Field(), err.Error())
+	}
+	return fmt.Sprintf("%s is not a valid DID", fe.Field())
+}
+
 // RouteParams returns a map of route params and their respective values.
 // e.g. route: /users/:id  request: /users/1 map: :id -> 1
 func RouteParams(r *http.Request) map[string]string {
@@ -63,7 +93,7 @@ func Decode(r *http.Request, val interface{}) error {
 	decoder.DisallowUnknownFields()
 
 	if err := decoder.Decode(val); err != nil {
-		return NewRequestError(err, http.StatusBadRequest)
+		return NewRequestError(sanitizeDecodeError(err), http.StatusBadRequest)
 	}
 
 	if err := validate.Struct(val); err != nil {
@@ -95,3 +125,17 @@ func Decode(r *http.Request, val interface{}) error {
 
 	return nil
 }
+
+// sanitizeDecodeError describes a failure to decode a request body without including any of the body's content,
+// since errors are returned to the requester and logged
+func sanitizeDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("invalid type for field<%s>, expected: %s", typeErr.Field, typeErr.Type)
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("request body is not valid JSON, at offset: %d", syntaxErr.Offset)
+	}
+	return err
+}
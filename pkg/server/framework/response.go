@@ -31,11 +31,14 @@ func Respond(ctx context.Context, w http.ResponseWriter, data interface{}, statu
 		return err
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	// handlers may set a more specific JSON media type ahead of responding
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
 	w.WriteHeader(statusCode)
 
 	// send response payload to client
-	_, err = w.Write(jsonData)
+	v.ResponseBytes, err = w.Write(jsonData)
 	return err
 }
 
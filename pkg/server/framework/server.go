@@ -17,6 +17,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/internal/util"
 )
 
 type (
@@ -29,9 +30,22 @@ const (
 )
 
 type RequestState struct {
-	TraceID    string
-	Now        time.Time
-	StatusCode int
+	TraceID string
+	// Route is the path template the request matched, such as /v1/credentials/:id
+	Route         string
+	Now           time.Time
+	StatusCode    int
+	ResponseBytes int
+}
+
+// RequestLogger gets a logger whose entries carry the ID of the request in the context, so that services called on
+// behalf of the request log with it
+func RequestLogger(ctx context.Context) util.Logger {
+	logger := util.NewLogger()
+	if v, ok := ctx.Value(KeyRequestState).(*RequestState); ok {
+		logger.Entry = logger.WithField("requestId", v.TraceID)
+	}
+	return logger
 }
 
 // A Handler is a type that handles a http request within our own little mini
@@ -75,6 +89,7 @@ func (s *Server) Handle(method string, path string, handler Handler, mw ...Middl
 	h := func(w http.ResponseWriter, r *http.Request) {
 		requestState := RequestState{
 			TraceID: uuid.New().String(),
+			Route:   path,
 			Now:     time.Now(),
 		}
 		ctx := context.WithValue(r.Context(), KeyRequestState, &requestState)
@@ -5,6 +5,9 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/dimfeld/httptreemux/v5"
 	"github.com/pkg/errors"
@@ -29,6 +32,57 @@ func GetQueryValue(r *http.Request, param string) *string {
 	return &v
 }
 
+// NegotiateContentType picks which of the offered media types best matches the request's Accept header, in order
+// of the client's preference. Requests without an Accept header accept the first offered type. Returns false if
+// none of the offered types are acceptable.
+func NegotiateContentType(r *http.Request, offered ...string) (string, bool) {
+	accept := r.Header.Get("Accept")
+	if accept == "" && len(offered) > 0 {
+		return offered[0], true
+	}
+
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mr := mediaRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), quality: 1}
+		for _, param := range params[1:] {
+			if q := strings.TrimSpace(param); strings.HasPrefix(q, "q=") {
+				if quality, err := strconv.ParseFloat(q[2:], 64); err == nil {
+					mr.quality = quality
+				}
+			}
+		}
+		if mr.mediaType != "" && mr.quality > 0 {
+			ranges = append(ranges, mr)
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, mr := range ranges {
+		for _, o := range offered {
+			if mediaTypeMatches(mr.mediaType, o) {
+				return o, true
+			}
+		}
+	}
+	return "", false
+}
+
+// mediaTypeMatches checks whether a media type falls within a range, such as */* or application/*
+func mediaTypeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+	}
+	return false
+}
+
 // PeekRequestBody reads a request's body without emptying the buffer
 func PeekRequestBody(r *http.Request) (string, error) {
 	bodyBytes, err := io.ReadAll(r.Body)
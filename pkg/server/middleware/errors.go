@@ -4,8 +4,6 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/sirupsen/logrus"
-
 	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/pkg/server/framework"
 
@@ -22,14 +20,13 @@ func Errors() framework.Middleware {
 			ctx, span := tracer.Start(ctx, "service.middleware.errors")
 			defer span.End()
 
-			v, ok := ctx.Value(framework.KeyRequestState).(*framework.RequestState)
-			if !ok {
+			if _, ok := ctx.Value(framework.KeyRequestState).(*framework.RequestState); !ok {
 				return framework.NewShutdownError("request state missing from context.")
 			}
 
 			if err := handler(ctx, w, r); err != nil {
 				// log the error
-				logrus.Printf("%s : ERROR : %v", v.TraceID, err)
+				framework.RequestLogger(ctx).WithError(err).Error("request failed")
 
 				// send an error response back to the requester.
 				if err := framework.RespondError(ctx, w, err); err != nil {
@@ -2,22 +2,35 @@ package middleware
 
 import (
 	"context"
-	"github.com/sirupsen/logrus"
-	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	"math/rand"
 	"net/http"
+	"sync/atomic"
 	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
 )
 
-// Logger logs request info before and after a handler runs.
-// logs to to stdout in the following format:
-// Before:
-// 		TraceID : (StatusCode) HTTPMethod Path -> IPAddr (latency)
-// 		e.g. 12345 : (200) GET /users/1 -> 192.168.1.0 (4ms)
-// After:
-// 		TODO: add after format
-// 		TODO: add after example
-// TODO: make logging output configurable
-func Logger() framework.Middleware {
+// logGetSampleRate holds the current sampling rate for successful GET requests, as a float64
+var logGetSampleRate atomic.Value
+
+// SetLogGetSampleRate changes the sampling rate for successful GET requests while the server is running
+func SetLogGetSampleRate(rate float64) {
+	logGetSampleRate.Store(rate)
+}
+
+// Logger logs a single structured entry for each request after its handler runs, e.g.
+//
+//	{"level":"info","method":"GET","route":"/v1/credentials/:id","path":"/v1/credentials/1234","status":200,
+//	 "latency":"4ms","requestId":"12345","remoteAddr":"192.168.1.0","bytes":512,"msg":"request completed"}
+//
+// Successful GET requests are sampled according to the server's configured rate, so that high volume reads do
+// not flood the logs. All other requests are always logged.
+func Logger(cfg config.ServerConfig) framework.Middleware {
+	SetLogGetSampleRate(cfg.LogGetSampleRate)
+
 	mw := func(handler framework.Handler) framework.Handler {
 
 		wrapped := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -26,18 +39,21 @@ func Logger() framework.Middleware {
 				return framework.NewShutdownError("Request state missing from context")
 			}
 
-			logrus.Infof("%s : started : %s %s -> %s",
-				v.TraceID,
-				r.Method, r.URL.Path, r.RemoteAddr,
-			)
-
 			err := handler(ctx, w, r)
 
-			logrus.Infof("%s : completed : %s %s -> %s (%d) (%s)",
-				v.TraceID,
-				r.Method, r.URL.Path, r.RemoteAddr,
-				v.StatusCode, time.Since(v.Now),
-			)
+			if skipLog(logGetSampleRate.Load().(float64), r.Method, v.StatusCode) {
+				return err
+			}
+			logrus.WithFields(logrus.Fields{
+				"method":     r.Method,
+				"route":      v.Route,
+				"path":       r.URL.Path,
+				"status":     v.StatusCode,
+				"latency":    time.Since(v.Now).String(),
+				"requestId":  v.TraceID,
+				"remoteAddr": r.RemoteAddr,
+				"bytes":      v.ResponseBytes,
+			}).Info("request completed")
 
 			return err
 		}
@@ -47,3 +63,9 @@ func Logger() framework.Middleware {
 
 	return mw
 }
+
+// skipLog determines whether a request's log entry is sampled out
+func skipLog(sampleRate float64, method string, statusCode int) bool {
+	successfulGet := method == http.MethodGet && statusCode >= 200 && statusCode < 300
+	return successfulGet && rand.Float64() >= sampleRate
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logrus.SetOutput(&buf)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	t.Cleanup(func() {
+		logrus.SetOutput(os.Stderr)
+	})
+
+	respondWith := func(status int) framework.Handler {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			return framework.Respond(ctx, w, map[string]string{"status": "ok"}, status)
+		}
+	}
+	serve := func(mw framework.Middleware, method string, status int) {
+		ctx := context.WithValue(context.Background(), framework.KeyRequestState, &framework.RequestState{
+			TraceID: "1234",
+			Route:   "/v1/credentials/:id",
+			Now:     time.Now(),
+		})
+		req := httptest.NewRequest(method, "https://ssi-service.com/v1/credentials/abcd", nil)
+		err := mw(respondWith(status))(ctx, httptest.NewRecorder(), req)
+		assert.NoError(t, err)
+	}
+
+	t.Run("Logs Structured Entry", func(tt *testing.T) {
+		buf.Reset()
+		serve(Logger(config.ServerConfig{LogGetSampleRate: 1}), http.MethodGet, http.StatusOK)
+
+		var entry map[string]interface{}
+		err := json.Unmarshal(buf.Bytes(), &entry)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "GET", entry["method"])
+		assert.Equal(tt, "/v1/credentials/:id", entry["route"])
+		assert.Equal(tt, "/v1/credentials/abcd", entry["path"])
+		assert.Equal(tt, float64(http.StatusOK), entry["status"])
+		assert.Equal(tt, "1234", entry["requestId"])
+		assert.Equal(tt, float64(len(`{"status":"ok"}`)), entry["bytes"])
+		assert.NotEmpty(tt, entry["latency"])
+	})
+
+	t.Run("Samples Successful GETs", func(tt *testing.T) {
+		buf.Reset()
+		mw := Logger(config.ServerConfig{LogGetSampleRate: 0})
+
+		serve(mw, http.MethodGet, http.StatusOK)
+		assert.Empty(tt, buf.String())
+
+		// the rate can be changed while running
+		SetLogGetSampleRate(1)
+		serve(mw, http.MethodGet, http.StatusOK)
+		assert.Contains(tt, buf.String(), `"status":200`)
+		SetLogGetSampleRate(0)
+
+		buf.Reset()
+
+		// failures and other methods are always logged
+		serve(mw, http.MethodGet, http.StatusNotFound)
+		assert.Contains(tt, buf.String(), `"status":404`)
+
+		buf.Reset()
+		serve(mw, http.MethodPut, http.StatusCreated)
+		assert.Contains(tt, buf.String(), `"status":201`)
+	})
+}
@@ -0,0 +1,136 @@
+//go:build perf
+// +build perf
+
+package server
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/goccy/go-json"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+	"github.com/tbd54566975/ssi-service/pkg/testutil/load"
+)
+
+// run with: go test -tags=perf ./pkg/server -run TestPerformance
+var (
+	perfBaselines   = flag.String("perf.baselines", "testdata/perf_baselines.json", "file of recorded baselines to check against")
+	perfRecord      = flag.Bool("perf.record", false, "record this run's results as the new baselines instead of checking them")
+	perfResults     = flag.String("perf.results", "", "file to write this run's results to as JSON")
+	perfThreshold   = flag.Float64("perf.threshold", 0.5, "fraction by which a result may exceed its baseline")
+	perfConcurrency = flag.Int("perf.concurrency", 8, "concurrent clients per workload")
+	perfRequests    = flag.Int("perf.requests", 400, "requests per workload")
+)
+
+func TestPerformance(t *testing.T) {
+	// remove the db file after the test
+	t.Cleanup(func() {
+		_ = os.Remove(storage.DBFile)
+	})
+
+	// request logging would dominate both the output and the measurements
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.WarnLevel)
+	t.Cleanup(func() { logrus.SetLevel(level) })
+
+	serviceConfig, err := config.LoadConfig("")
+	require.NoError(t, err)
+	ssiServer, err := NewSSIServer(make(chan os.Signal, 1), *serviceConfig)
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(ssiServer.Server)
+	t.Cleanup(httpServer.Close)
+	client := httpServer.Client()
+
+	// a DID to resolve
+	var createdDID router.CreateDIDByMethodResponse
+	err = doRequest(client, http.MethodPut, httpServer.URL+"/v1/dids/key", router.CreateDIDByMethodRequest{KeyType: crypto.Ed25519}, http.StatusCreated, &createdDID)
+	require.NoError(t, err)
+	didURL := fmt.Sprintf("%s/v1/dids/key/%s", httpServer.URL, createdDID.DID.ID)
+
+	workloads := []load.Workload{
+		{
+			Name: "create credential",
+			Op: func(i int) error {
+				createRequest := router.CreateCredentialRequest{
+					Issuer:  "did:test:perf",
+					Subject: fmt.Sprintf("did:test:holder%d", i),
+					Data:    map[string]interface{}{"firstName": "Jack", "index": i},
+				}
+				return doRequest(client, http.MethodPut, httpServer.URL+"/v1/credentials", createRequest, http.StatusCreated, nil)
+			},
+		},
+		{
+			Name: "resolve DID",
+			Op: func(int) error {
+				return doRequest(client, http.MethodGet, didURL, nil, http.StatusOK, nil)
+			},
+		},
+	}
+
+	baselines, err := load.LoadBaselines(*perfBaselines)
+	require.NoError(t, err)
+
+	var results []load.Result
+	for _, workload := range workloads {
+		workload.Concurrency = *perfConcurrency
+		workload.Requests = *perfRequests
+		result, err := load.Run(workload)
+		require.NoError(t, err)
+		t.Logf("%s: p50 %s, p95 %s, %d allocs/op, %.0f req/s", result.Name, result.P50, result.P95, result.AllocsPerOp, result.Throughput)
+		assert.Zero(t, result.Errors, result.FirstError)
+		if !*perfRecord {
+			assert.NoError(t, baselines.Check(*result, *perfThreshold))
+		}
+		results = append(results, *result)
+	}
+
+	if *perfResults != "" {
+		require.NoError(t, load.WriteResults(*perfResults, results))
+	}
+	if *perfRecord {
+		baselines.Record(results...)
+		require.NoError(t, baselines.Save(*perfBaselines))
+	}
+}
+
+// doRequest makes a request with an optional JSON body, checking the response status and decoding its body if asked
+func doRequest(client *http.Client, method, url string, body interface{}, wantStatus int, response interface{}) error {
+	var bodyReader *bytes.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, url, bodyReader)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		return fmt.Errorf("%s %s: got status %d, want %d", method, url, resp.StatusCode, wantStatus)
+	}
+	if response == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/TBD54566975/ssi-sdk/credential/schema"
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 
 	"github.com/tbd54566975/ssi-service/internal/util"
 	"github.com/tbd54566975/ssi-service/pkg/server/framework"
@@ -19,6 +24,20 @@ const (
 	IssuerParam  string = "issuer"
 	SubjectParam string = "subject"
 	SchemaParam  string = "schema"
+	DIDParam     string = "did"
+	StatusParam  string = "status"
+	AliasParam   string = "alias"
+
+	CanonicalParam string = "canonical"
+	DryRunParam    string = "dryRun"
+	DedupeParam    string = "dedupe"
+	SortParam      string = "sort"
+	WithinParam    string = "within"
+	TimingsParam   string = "timings"
+
+	JSONMediaType string = "application/json"
+	VCLDMediaType string = "application/vc+ld+json"
+	LDMediaType   string = "application/ld+json"
 )
 
 type CredentialRouter struct {
@@ -38,15 +57,25 @@ func NewCredentialRouter(s svcframework.Service) (*CredentialRouter, error) {
 	}, nil
 }
 
+// serviceFor gets the credential service, logging with the ID of the request in the context
+func (cr CredentialRouter) serviceFor(ctx context.Context) *credential.Service {
+	return cr.service.WithLogger(framework.RequestLogger(ctx))
+}
+
 type CreateCredentialRequest struct {
-	Issuer  string `json:"issuer" validate:"required"`
-	Subject string `json:"subject" validate:"required"`
+	Issuer string `json:"issuer" validate:"required,did"`
+	// Either the subject's DID, or an alias registered for it
+	Subject string `json:"subject" validate:"required,did_or_alias"`
 	// A context is optional. If not present, we'll apply default, required context values.
 	Context string `json:"@context"`
+	// Types of the credential in addition to VerifiableCredential, which is always included
+	Type []string `json:"type"`
 	// A schema is optional. If present, we'll attempt to look it up and validate the data against it.
 	Schema string                 `json:"schema"`
 	Data   map[string]interface{} `json:"data" validate:"required"`
 	Expiry string                 `json:"expiry"`
+	// Optionally, how long the credential is stored before it is purged, as a duration such as "15m"
+	StorageTTL string `json:"storageTTL"`
 	// TODO(gabe) support more capabilities like signature type, format, status, and more.
 }
 
@@ -55,14 +84,26 @@ func (c CreateCredentialRequest) ToServiceRequest() credential.CreateCredentialR
 		Issuer:     c.Issuer,
 		Subject:    c.Subject,
 		Context:    c.Context,
+		Type:       c.Type,
 		JSONSchema: c.Schema,
 		Data:       c.Data,
 		Expiry:     c.Expiry,
+		StorageTTL: c.StorageTTL,
 	}
 }
 
 type CreateCredentialResponse struct {
 	Credential credsdk.VerifiableCredential `json:"credential"`
+	// Set when the subject was given by alias, the alias it was resolved from
+	SubjectAlias string `json:"subjectAlias,omitempty"`
+	// Set when requested, how long each phase of issuance took
+	Timings *IssuanceTimings `json:"timings,omitempty"`
+}
+
+type IssuanceTimings struct {
+	Build    string `json:"build"`
+	Validate string `json:"validate"`
+	Store    string `json:"store"`
 }
 
 // CreateCredential godoc
@@ -71,7 +112,8 @@ type CreateCredentialResponse struct {
 // @Tags         CredentialAPI
 // @Accept       json
 // @Produce      json
-// @Param        request  body      CreateCredentialRequest  true  "request body"
+// @Param        request  body      CreateCredentialRequest  true   "request body"
+// @Param        timings  query     bool                     false  "include how long each phase of issuance took"
 // @Success      201      {object}  CreateCredentialResponse
 // @Failure      400      {string}  string  "Bad request"
 // @Failure      500      {string}  string  "Internal server error"
@@ -80,25 +122,403 @@ func (cr CredentialRouter) CreateCredential(ctx context.Context, w http.Response
 	var request CreateCredentialRequest
 	if err := framework.Decode(r, &request); err != nil {
 		errMsg := "invalid create credential request"
-		logrus.WithError(err).Error(errMsg)
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
 	}
 
 	req := request.ToServiceRequest()
-	createCredentialResponse, err := cr.service.CreateCredential(req)
+	if timings := framework.GetQueryValue(r, TimingsParam); timings != nil && *timings == "true" {
+		req.Timings = true
+	}
+	createCredentialResponse, err := cr.serviceFor(ctx).CreateCredential(req)
 	if err != nil {
 		errMsg := "could not create credential"
-		logrus.WithError(err).Error(errMsg)
+		if errors.As(err, &credential.IssuanceFrozenError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusServiceUnavailable)
+		}
+		var missingClaimsErr credential.MissingClaimsError
+		if errors.As(err, &missingClaimsErr) {
+			return missingClaimsRequestError(missingClaimsErr)
+		}
+		var invalidDataErr credential.InvalidCredentialDataError
+		if errors.As(err, &invalidDataErr) {
+			return invalidDataRequestError(invalidDataErr)
+		}
+		var unknownSchemaErr credential.UnknownSchemaError
+		if errors.As(err, &unknownSchemaErr) {
+			field := framework.FieldError{Field: "schema", Error: "schema not found: " + unknownSchemaErr.Schema}
+			return &framework.SafeError{Err: errors.Wrap(err, errMsg), StatusCode: http.StatusBadRequest, Fields: []framework.FieldError{field}}
+		}
+		if errors.As(err, &credential.SubjectLimitError{}) || errors.As(err, &credential.DisallowedTypeError{}) ||
+			errors.As(err, &credential.InvalidStorageTTLError{}) || errors.As(err, &credential.SubjectAliasNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	resp := CreateCredentialResponse{Credential: createCredentialResponse.Credential, SubjectAlias: createCredentialResponse.SubjectAlias}
+	if timings := createCredentialResponse.Timings; timings != nil {
+		resp.Timings = &IssuanceTimings{
+			Build:    timings.Build.String(),
+			Validate: timings.Validate.String(),
+			Store:    timings.Store.String(),
+		}
+	}
+	return framework.Respond(ctx, w, resp, http.StatusCreated)
+}
+
+type BatchCreateCredentialsRequest struct {
+	Requests []CreateCredentialRequest `json:"requests" validate:"required,min=1,dive"`
+}
+
+func (b BatchCreateCredentialsRequest) ToServiceRequest() credential.BatchCreateCredentialsRequest {
+	requests := make([]credential.CreateCredentialRequest, 0, len(b.Requests))
+	for _, request := range b.Requests {
+		requests = append(requests, request.ToServiceRequest())
+	}
+	return credential.BatchCreateCredentialsRequest{Requests: requests}
+}
+
+type BatchCreateCredentialsResponse struct {
+	Credentials []credsdk.VerifiableCredential `json:"credentials"`
+}
+
+// BatchCreateCredentials godoc
+// @Summary      Batch Create Credentials
+// @Description  Create a set of credentials, up to 100 at once. Either all credentials are created, or none are.
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      BatchCreateCredentialsRequest  true  "request body"
+// @Success      201      {object}  BatchCreateCredentialsResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /v1/credentials/batch [put]
+func (cr CredentialRouter) BatchCreateCredentials(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var request BatchCreateCredentialsRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid batch create credentials request"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	createCredentialsResponse, err := cr.serviceFor(ctx).CreateCredentials(ctx, request.ToServiceRequest())
+	if err != nil {
+		errMsg := "could not create credentials"
+		if errors.As(err, &credential.IssuanceFrozenError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusServiceUnavailable)
+		}
+		// a failure for any one request means no credentials were created, so name the request which failed
+		var itemErr credential.BatchItemError
+		if errors.As(err, &itemErr) {
+			field := framework.FieldError{Field: fmt.Sprintf("requests[%d]", itemErr.Index), Error: itemErr.Err.Error()}
+			return &framework.SafeError{Err: err, StatusCode: http.StatusBadRequest, Fields: []framework.FieldError{field}}
+		}
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
 	}
 
-	resp := CreateCredentialResponse{Credential: createCredentialResponse.Credential}
+	resp := BatchCreateCredentialsResponse{Credentials: createCredentialsResponse.Credentials}
+	return framework.Respond(ctx, w, resp, http.StatusCreated)
+}
+
+// CSVCredentialTemplate describes the credential issued for each row of a CSV document. Its issuer, subject, schema
+// and expiry may reference a row's values by column name, as ${column}.
+type CSVCredentialTemplate struct {
+	Issuer  string   `json:"issuer" validate:"required,did_or_template"`
+	Subject string   `json:"subject" validate:"required,did_or_alias"`
+	Context string   `json:"@context"`
+	Type    []string `json:"type"`
+	Schema  string   `json:"schema"`
+	// Claims common to every row, which mapped columns override
+	Data   map[string]interface{} `json:"data"`
+	Expiry string                 `json:"expiry"`
+}
+
+type CreateCredentialsFromCSVRequest struct {
+	// A CSV document, the first line of which names its columns
+	CSV string `json:"csv" validate:"required"`
+	// Maps column names to the '.' separated claim path each column's value is set at in a credential's data
+	Mapping  map[string]string     `json:"mapping" validate:"required,min=1"`
+	Template CSVCredentialTemplate `json:"template"`
+}
+
+func (c CreateCredentialsFromCSVRequest) ToServiceRequest() credential.CreateCredentialsFromCSVRequest {
+	return credential.CreateCredentialsFromCSVRequest{
+		CSV:     c.CSV,
+		Mapping: c.Mapping,
+		Template: credential.CreateCredentialRequest{
+			Issuer:     c.Template.Issuer,
+			Subject:    c.Template.Subject,
+			Context:    c.Template.Context,
+			Type:       c.Template.Type,
+			JSONSchema: c.Template.Schema,
+			Data:       c.Template.Data,
+			Expiry:     c.Template.Expiry,
+		},
+	}
+}
+
+// CreateCredentialsFromCSV godoc
+// @Summary      Create Credentials From CSV
+// @Description  Create a credential for each row of a CSV document, up to 100 rows at once. Either a credential is created for every row, or none are.
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateCredentialsFromCSVRequest  true  "request body"
+// @Success      201      {object}  BatchCreateCredentialsResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      503      {string}  string  "Issuance frozen"
+// @Router       /v1/credentials/from-csv [post]
+func (cr CredentialRouter) CreateCredentialsFromCSV(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var request CreateCredentialsFromCSVRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid create credentials from CSV request"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	createCredentialsResponse, err := cr.serviceFor(ctx).CreateCredentialsFromCSV(ctx, request.ToServiceRequest())
+	if err != nil {
+		errMsg := "could not create credentials from CSV"
+		if errors.As(err, &credential.IssuanceFrozenError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusServiceUnavailable)
+		}
+		// name each line which failed
+		var rowErrs credential.CSVRowErrors
+		if errors.As(err, &rowErrs) {
+			fields := make([]framework.FieldError, 0, len(rowErrs))
+			for _, rowErr := range rowErrs {
+				fields = append(fields, framework.FieldError{Field: fmt.Sprintf("csv[line %d]", rowErr.Line), Error: rowErr.Err.Error()})
+			}
+			return &framework.SafeError{Err: err, StatusCode: http.StatusBadRequest, Fields: fields}
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := BatchCreateCredentialsResponse{Credentials: createCredentialsResponse.Credentials}
 	return framework.Respond(ctx, w, resp, http.StatusCreated)
 }
 
+type ValidateCredentialRequest struct {
+	// The ID of a schema known to the schema service. One of schema or jsonSchema is required.
+	SchemaID string `json:"schema"`
+	// An inline JSON Schema to validate the data against
+	Schema schema.JSONSchema      `json:"jsonSchema"`
+	Data   map[string]interface{} `json:"data" validate:"required"`
+}
+
+func (v ValidateCredentialRequest) ToServiceRequest() credential.ValidateCredentialRequest {
+	return credential.ValidateCredentialRequest{
+		SchemaID: v.SchemaID,
+		Schema:   v.Schema,
+		Data:     v.Data,
+	}
+}
+
+type ValidateCredentialResponse struct {
+	Valid  bool                    `json:"valid"`
+	Errors []credential.FieldError `json:"errors,omitempty"`
+}
+
+// ValidateCredential godoc
+// @Summary      Validate Credential
+// @Description  Validate credential data against a schema without issuing a credential
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ValidateCredentialRequest  true  "request body"
+// @Success      200      {object}  ValidateCredentialResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Router       /v1/credentials/validate [post]
+func (cr CredentialRouter) ValidateCredential(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var request ValidateCredentialRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid validate credential request"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	validateCredentialResponse, err := cr.serviceFor(ctx).ValidateCredential(request.ToServiceRequest())
+	if err != nil {
+		errMsg := "could not validate credential"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := ValidateCredentialResponse{
+		Valid:  validateCredentialResponse.Valid,
+		Errors: validateCredentialResponse.Errors,
+	}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+type ExpandCredentialRequest struct {
+	Credential map[string]interface{} `json:"credential" validate:"required"`
+}
+
+type ExpandCredentialResponse struct {
+	Expanded []interface{} `json:"expanded"`
+	// Terms used by the credential which its contexts do not define, and so are dropped from the expanded form
+	UndefinedTerms []string `json:"undefinedTerms"`
+}
+
+// ExpandCredential godoc
+// @Summary      Expand Credential
+// @Description  Expand a JSON-LD credential, reporting any terms its contexts do not define
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ExpandCredentialRequest  true  "request body"
+// @Success      200      {object}  ExpandCredentialResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Router       /v1/credentials/expand [post]
+func (cr CredentialRouter) ExpandCredential(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var request ExpandCredentialRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid expand credential request"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	expandResp, err := cr.serviceFor(ctx).ExpandCredential(credential.ExpandCredentialRequest{Credential: request.Credential})
+	if err != nil {
+		errMsg := "could not expand credential"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := ExpandCredentialResponse{Expanded: expandResp.Expanded, UndefinedTerms: expandResp.UndefinedTerms}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+type MatchCredentialsRequest struct {
+	PresentationDefinition exchange.PresentationDefinition `json:"presentationDefinition" validate:"required"`
+	Credentials            []credsdk.VerifiableCredential  `json:"credentials" validate:"required,min=1"`
+
+	// each field's filter as it was sent, by input descriptor and field
+	filters [][]map[string]interface{}
+}
+
+// UnmarshalJSON also keeps each field's filter as it was sent. exchange.Filter holds only some JSON Schema keywords,
+// so a filter read into it loses the others, such as contains or properties, and matches credentials it should not.
+func (m *MatchCredentialsRequest) UnmarshalJSON(data []byte) error {
+	type matchCredentialsRequest MatchCredentialsRequest
+	var request matchCredentialsRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return err
+	}
+	var rawRequest struct {
+		PresentationDefinition struct {
+			InputDescriptors []struct {
+				Constraints *struct {
+					Fields []struct {
+						Filter map[string]interface{} `json:"filter"`
+					} `json:"fields"`
+				} `json:"constraints"`
+			} `json:"input_descriptors"`
+		} `json:"presentationDefinition"`
+	}
+	if err := json.Unmarshal(data, &rawRequest); err != nil {
+		return err
+	}
+	request.filters = make([][]map[string]interface{}, len(rawRequest.PresentationDefinition.InputDescriptors))
+	for i, descriptor := range rawRequest.PresentationDefinition.InputDescriptors {
+		if descriptor.Constraints == nil {
+			continue
+		}
+		for _, field := range descriptor.Constraints.Fields {
+			request.filters[i] = append(request.filters[i], field.Filter)
+		}
+	}
+	*m = MatchCredentialsRequest(request)
+	return nil
+}
+
+type MatchCredentialsResponse struct {
+	// Whether every input descriptor is satisfied by at least one credential
+	Satisfied   bool              `json:"satisfied"`
+	Descriptors []DescriptorMatch `json:"descriptors"`
+}
+
+type DescriptorMatch struct {
+	ID          string            `json:"id"`
+	Satisfied   bool              `json:"satisfied"`
+	Credentials []CredentialMatch `json:"credentials"`
+}
+
+type CredentialMatch struct {
+	// The position of the credential in the request
+	Index int  `json:"index"`
+	Match bool `json:"match"`
+	// When the credential does not match, the first field it does not satisfy, and why
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// MatchCredentials godoc
+// @Summary      Match Credentials
+// @Description  Match credentials against the input descriptors of a presentation definition, without verifying them
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      MatchCredentialsRequest  true  "request body"
+// @Success      200      {object}  MatchCredentialsResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Router       /v1/credentials/match [post]
+func (cr CredentialRouter) MatchCredentials(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var request MatchCredentialsRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid match credentials request"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	req := credential.MatchCredentialsRequest{
+		Definition:  request.PresentationDefinition,
+		Filters:     request.filters,
+		Credentials: request.Credentials,
+	}
+	matchResp, err := cr.serviceFor(ctx).MatchCredentials(req)
+	if err != nil {
+		errMsg := "could not match credentials"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := MatchCredentialsResponse{Satisfied: matchResp.Satisfied}
+	for _, descriptor := range matchResp.Descriptors {
+		descriptorMatch := DescriptorMatch{ID: descriptor.ID, Satisfied: descriptor.Satisfied}
+		for _, credMatch := range descriptor.Credentials {
+			descriptorMatch.Credentials = append(descriptorMatch.Credentials, CredentialMatch{
+				Index:  credMatch.Index,
+				Match:  credMatch.Match,
+				Field:  credMatch.Field,
+				Reason: credMatch.Reason,
+			})
+		}
+		resp.Descriptors = append(resp.Descriptors, descriptorMatch)
+	}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+// missingClaimsRequestError reports each missing required claim as a field error on the request's data
+func missingClaimsRequestError(err credential.MissingClaimsError) error {
+	var fields []framework.FieldError
+	for _, claim := range err.Claims {
+		fields = append(fields, framework.FieldError{Field: "data." + claim, Error: "required claim is missing"})
+	}
+	return &framework.SafeError{Err: err, StatusCode: http.StatusBadRequest, Fields: fields}
+}
+
+// invalidDataRequestError reports each way the request's data does not conform to its schema as a field error
+func invalidDataRequestError(err credential.InvalidCredentialDataError) error {
+	var fields []framework.FieldError
+	for _, fieldErr := range err.Errors {
+		field := "data"
+		if fieldErr.Field != "(root)" {
+			field += "." + fieldErr.Field
+		}
+		fields = append(fields, framework.FieldError{Field: field, Error: fieldErr.Message})
+	}
+	return &framework.SafeError{Err: err, StatusCode: http.StatusBadRequest, Fields: fields}
+}
+
 type GetCredentialResponse struct {
 	ID         string                       `json:"id"`
 	Credential credsdk.VerifiableCredential `json:"credential"`
+	// Set when the subject was given by alias, the alias it was resolved from
+	SubjectAlias string `json:"subjectAlias,omitempty"`
 }
 
 // GetCredential godoc
@@ -107,46 +527,235 @@ type GetCredentialResponse struct {
 // @Tags         CredentialAPI
 // @Accept       json
 // @Produce      json
-// @Param        id   path      string  true  "ID"
-// @Success      200  {object}  GetCredentialResponse
-// @Failure      400  {string}  string  "Bad request"
+// @Param        id         path      string  true   "ID"
+// @Param        canonical  query     bool    false  "return the credential in canonical form with its digest"
+// @Param        fields     query     string  false  "comma separated top-level credential fields to return, such as id,credentialSubject"
+// @Success      200        {object}  GetCredentialResponse
+// @Failure      400        {string}  string  "Bad request"
+// @Failure      404        {string}  string  "Not found"
+// @Failure      406        {string}  string  "Not acceptable"
 // @Router       /v1/credentials/{id} [get]
 func (cr CredentialRouter) GetCredential(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	id := framework.GetParam(ctx, IDParam)
 	if id == nil {
 		errMsg := "cannot get credential without ID parameter"
-		logrus.Error(errMsg)
 		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
 	}
 
-	gotCredential, err := cr.service.GetCredential(credential.GetCredentialRequest{ID: *id})
+	// credentials are only held as JSON-LD, and a VC-JWT cannot be produced without signing a new one
+	mediaType, ok := framework.NegotiateContentType(r, JSONMediaType, VCLDMediaType, LDMediaType)
+	if !ok {
+		errMsg := fmt.Sprintf("credentials can only be represented as %s, %s or %s", JSONMediaType, VCLDMediaType, LDMediaType)
+		return framework.NewRequestErrorMsg(errMsg, http.StatusNotAcceptable)
+	}
+
+	fields, err := framework.GetFieldsParam(r, credsdk.VerifiableCredential{})
+	if err != nil {
+		return framework.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	if canonical := framework.GetQueryValue(r, CanonicalParam); canonical != nil && *canonical == "true" {
+		// the digest is of the whole credential
+		if fields != nil {
+			errMsg := fmt.Sprintf("%s cannot be selected for a canonical credential", framework.FieldsParam)
+			return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+		}
+		return cr.getCanonicalCredential(*id, ctx, w)
+	}
+
+	gotCredential, err := cr.serviceFor(ctx).GetCredential(credential.GetCredentialRequest{ID: *id})
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get credential with id: %s", *id)
-		logrus.WithError(err).Error(errMsg)
+		if errors.As(err, &credential.CredentialNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusNotFound)
+		}
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
 	}
 
+	// a JSON-LD credential is returned on its own, rather than wrapped in a response object
+	if mediaType == VCLDMediaType || mediaType == LDMediaType {
+		w.Header().Set("Content-Type", mediaType)
+		return respondWithFields(ctx, w, gotCredential.Credential, "", fields)
+	}
+
 	resp := GetCredentialResponse{
-		ID:         gotCredential.Credential.ID,
-		Credential: gotCredential.Credential,
+		ID:           gotCredential.Credential.ID,
+		Credential:   gotCredential.Credential,
+		SubjectAlias: gotCredential.SubjectAlias,
+	}
+	return respondWithFields(ctx, w, resp, "credential", fields)
+}
+
+// respondWithFields responds with only the selected fields of the credential or credentials found at a key of the
+// response, or with the full response when no fields are selected
+func respondWithFields(ctx context.Context, w http.ResponseWriter, resp interface{}, key string, fields []string) error {
+	if fields == nil {
+		return framework.Respond(ctx, w, resp, http.StatusOK)
+	}
+	shaped, err := framework.SelectFields(resp, key, fields)
+	if err != nil {
+		errMsg := "could not select credential fields"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+	return framework.Respond(ctx, w, shaped, http.StatusOK)
+}
+
+type GetCanonicalCredentialResponse struct {
+	ID string `json:"id"`
+	// The canonicalization scheme used, JCS (RFC 8785)
+	Canonicalization string `json:"canonicalization"`
+	// The credential serialized in canonical form
+	Credential string `json:"credential"`
+	// Hex encoded SHA-256 digest of the canonical credential
+	Digest string `json:"digest"`
+}
+
+func (cr CredentialRouter) getCanonicalCredential(id string, ctx context.Context, w http.ResponseWriter) error {
+	gotCredential, err := cr.serviceFor(ctx).GetCanonicalCredential(credential.GetCredentialRequest{ID: id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get canonical credential with id: %s", id)
+		if errors.As(err, &credential.CredentialNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusNotFound)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := GetCanonicalCredentialResponse{
+		ID:               gotCredential.ID,
+		Canonicalization: gotCredential.Canonicalization,
+		Credential:       string(gotCredential.Credential),
+		Digest:           gotCredential.Digest,
+	}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+type BatchGetCredentialsRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+type BatchGetCredentialsResponse struct {
+	Credentials []credsdk.VerifiableCredential `json:"credentials"`
+	NotFound    []string                       `json:"notFound,omitempty"`
+}
+
+// BatchGetCredentials godoc
+// @Summary      Batch Get Credentials
+// @Description  Get a list of credentials by their IDs, up to 100 at once
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      BatchGetCredentialsRequest  true  "request body"
+// @Success      200      {object}  BatchGetCredentialsResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Router       /v1/credentials/batch-get [post]
+func (cr CredentialRouter) BatchGetCredentials(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var request BatchGetCredentialsRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid batch get credentials request"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	gotCredentials, err := cr.serviceFor(ctx).GetCredentials(credential.BatchGetCredentialsRequest{IDs: request.IDs})
+	if err != nil {
+		errMsg := "could not get credentials"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := BatchGetCredentialsResponse{
+		Credentials: gotCredentials.Credentials,
+		NotFound:    gotCredentials.NotFound,
 	}
 	return framework.Respond(ctx, w, resp, http.StatusOK)
 }
 
+type GetExpiringCredentialsResponse struct {
+	// Credentials ordered by expiration date, soonest first
+	Credentials []credsdk.VerifiableCredential `json:"credentials"`
+}
+
+// GetExpiringCredentials godoc
+// @Summary      Get Expiring Credentials
+// @Description  Get credentials which have not yet expired, but will within a window, soonest first
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        within     query     string  true   "window from now, as a number of days such as 30d, or a duration such as 36h"
+// @Param        issuer     query     string  false  "string issuer"
+// @Param        pageSize   query     int     false  "most credentials to return, up to 100, returning a page of credentials"
+// @Param        pageToken  query     string  false  "token for the next page, from a previous page"
+// @Success      200        {object}  GetExpiringCredentialsResponse
+// @Success      200        {object}  framework.Page
+// @Failure      400        {string}  string  "Bad request"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /v1/credentials/expiring [get]
+func (cr CredentialRouter) GetExpiringCredentials(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	within := framework.GetQueryValue(r, WithinParam)
+	if within == nil {
+		errMsg := "cannot get expiring credentials without the within query parameter"
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+	window, err := credential.ParseExpiryWindow(*within)
+	if err != nil {
+		return framework.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	request := credential.GetExpiringCredentialsRequest{Within: window}
+	if issuer := framework.GetQueryValue(r, IssuerParam); issuer != nil {
+		request.Issuer = *issuer
+	}
+	page, err := framework.GetPageRequest(r)
+	if err != nil {
+		return framework.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	gotCredentials, err := cr.serviceFor(ctx).GetExpiringCredentials(request)
+	if err != nil {
+		errMsg := "could not get expiring credentials"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	if page != nil {
+		credentialsPage, err := framework.NewPage(gotCredentials.Credentials, *page)
+		if err != nil {
+			errMsg := "could not paginate expiring credentials"
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+		}
+		return framework.Respond(ctx, w, credentialsPage, http.StatusOK)
+	}
+	resp := GetExpiringCredentialsResponse{Credentials: gotCredentials.Credentials}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
 type GetCredentialsResponse struct {
 	Credentials []credsdk.VerifiableCredential `json:"credentials"`
+	// Set when deduplicating, the number of credentials superseded by each returned credential, keyed by its ID
+	SupersededCounts map[string]int `json:"supersededCounts,omitempty"`
+}
+
+// GetCredentialsPage is the response when a page of credentials is requested
+type GetCredentialsPage struct {
+	framework.Page
+	// Set when deduplicating, the number of credentials superseded by each credential in the page, keyed by its ID
+	SupersededCounts map[string]int `json:"supersededCounts,omitempty"`
 }
 
 // GetCredentials godoc
 // @Summary      Get Credentials
-// @Description  Checks for the presence of a query parameter and calls the associated filtered get method
+// @Description  Checks for the presence of a query parameter and calls the associated filtered get method. Credentials are sorted by issuance date, most recent first, then by ID, unless another sort is given.
 // @Tags         CredentialAPI
 // @Accept       json
 // @Produce      json
 // @Param        issuer   query     string  false  "string issuer"
 // @Param        schema   query     string  false  "string schema"
 // @Param        subject  query     string  false  "string subject"
+// @Param        status   query     string  false  "string status, one of active or expired"
+// @Param        dedupe   query     bool    false  "with subject, collapse active credentials from the same issuer and schema to the most recent"
+// @Param        sort       query     string  false  "string sort, one of 'created asc' or 'created desc', where created is the issuance date"
+// @Param        pageSize   query     int     false  "most credentials to return, up to 100, returning a page of credentials"
+// @Param        pageToken  query     string  false  "token for the next page, from a previous page"
+// @Param        fields     query     string  false  "comma separated top-level credential fields to return, such as id,credentialSubject"
 // @Success      200      {object}  GetCredentialsResponse
+// @Success      200      {object}  GetCredentialsPage
 // @Failure      400      {string}  string  "Bad request"
 // @Failure      500      {string}  string  "Internal server error"
 // @Router       /v1/credentials [get]
@@ -162,54 +771,467 @@ func (cr CredentialRouter) GetCredentials(ctx context.Context, w http.ResponseWr
 		return err
 	}
 
+	// status may be combined with any of the other parameters
+	var status string
+	if statusValue := framework.GetQueryValue(r, StatusParam); statusValue != nil {
+		if !credential.IsValidStatus(*statusValue) {
+			errMsg := fmt.Sprintf("invalid status<%s>, must be one of: %s, %s", util.SanitizeLog(*statusValue), credential.StatusActive, credential.StatusExpired)
+			return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+		}
+		status = *statusValue
+	}
+
+	var sort string
+	if sortValue := framework.GetQueryValue(r, SortParam); sortValue != nil {
+		if !svcframework.IsValidSort(*sortValue) {
+			errMsg := fmt.Sprintf("invalid sort<%s>, must be one of: %s, %s", util.SanitizeLog(*sortValue), svcframework.SortCreatedAsc, svcframework.SortCreatedDesc)
+			return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+		}
+		sort = *sortValue
+	}
+
+	dedupe := false
+	if dedupeValue := framework.GetQueryValue(r, DedupeParam); dedupeValue != nil && *dedupeValue == "true" {
+		if subject == nil {
+			return framework.NewRequestErrorMsg("dedupe may only be used with the subject query parameter", http.StatusBadRequest)
+		}
+		dedupe = true
+	}
+
+	page, pageErr := framework.GetPageRequest(r)
+	if pageErr != nil {
+		return framework.NewRequestError(pageErr, http.StatusBadRequest)
+	}
+
+	fields, fieldsErr := framework.GetFieldsParam(r, credsdk.VerifiableCredential{})
+	if fieldsErr != nil {
+		return framework.NewRequestError(fieldsErr, http.StatusBadRequest)
+	}
+
 	if issuer != nil {
-		return cr.getCredentialsByIssuer(*issuer, ctx, w, r)
+		return cr.getCredentialsByIssuer(*issuer, status, sort, page, fields, ctx, w, r)
 	}
 	if subject != nil {
-		return cr.getCredentialsBySubject(*subject, ctx, w, r)
+		return cr.getCredentialsBySubject(*subject, status, sort, dedupe, page, fields, ctx, w, r)
 	}
 	if schema != nil {
-		return cr.getCredentialsBySchema(*schema, ctx, w, r)
+		return cr.getCredentialsBySchema(*schema, status, sort, page, fields, ctx, w, r)
 	}
 	return err
 }
 
-func (cr CredentialRouter) getCredentialsByIssuer(issuer string, ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	gotCredentials, err := cr.service.GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: issuer})
+// respondCredentials responds with the listed credentials, or with the requested page of them, narrowed to the
+// selected fields if any
+func respondCredentials(ctx context.Context, w http.ResponseWriter, gotCredentials *credential.GetCredentialsResponse, page *framework.PageRequest, fields []string) error {
+	if page == nil {
+		resp := GetCredentialsResponse{Credentials: gotCredentials.Credentials, SupersededCounts: gotCredentials.SupersededCounts}
+		return respondWithFields(ctx, w, resp, "credentials", fields)
+	}
+
+	credentialsPage, err := framework.NewPage(gotCredentials.Credentials, *page)
+	if err != nil {
+		errMsg := "could not paginate credentials"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+	resp := GetCredentialsPage{Page: *credentialsPage}
+	if gotCredentials.SupersededCounts != nil {
+		resp.SupersededCounts = make(map[string]int)
+		for _, cred := range credentialsPage.Items.([]credsdk.VerifiableCredential) {
+			if count, ok := gotCredentials.SupersededCounts[cred.ID]; ok {
+				resp.SupersededCounts[cred.ID] = count
+			}
+		}
+	}
+	return respondWithFields(ctx, w, resp, "items", fields)
+}
+
+func (cr CredentialRouter) getCredentialsByIssuer(issuer, status, sort string, page *framework.PageRequest, fields []string, ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gotCredentials, err := cr.serviceFor(ctx).GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: issuer, Status: status, Sort: sort})
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get credentials for issuer: %s", util.SanitizeLog(issuer))
-		logrus.WithError(err).Error(errMsg)
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
 	}
 
-	resp := GetCredentialsResponse{Credentials: gotCredentials.Credentials}
-	return framework.Respond(ctx, w, resp, http.StatusOK)
+	return respondCredentials(ctx, w, gotCredentials, page, fields)
 }
 
-func (cr CredentialRouter) getCredentialsBySubject(subject string, ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	gotCredentials, err := cr.service.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: subject})
+func (cr CredentialRouter) getCredentialsBySubject(subject, status, sort string, dedupe bool, page *framework.PageRequest, fields []string, ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gotCredentials, err := cr.serviceFor(ctx).GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: subject, Status: status, Sort: sort, Dedupe: dedupe})
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get credentials for subject: %s", util.SanitizeLog(subject))
-		logrus.WithError(err).Error(errMsg)
+		if errors.As(err, &credential.SubjectAliasNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+		}
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
 	}
 
-	resp := GetCredentialsResponse{Credentials: gotCredentials.Credentials}
-	return framework.Respond(ctx, w, resp, http.StatusOK)
+	return respondCredentials(ctx, w, gotCredentials, page, fields)
 }
 
-func (cr CredentialRouter) getCredentialsBySchema(schema string, ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	gotCredentials, err := cr.service.GetCredentialsBySchema(credential.GetCredentialBySchemaRequest{Schema: schema})
+func (cr CredentialRouter) getCredentialsBySchema(schema, status, sort string, page *framework.PageRequest, fields []string, ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gotCredentials, err := cr.serviceFor(ctx).GetCredentialsBySchema(credential.GetCredentialBySchemaRequest{Schema: schema, Status: status, Sort: sort})
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get credentials for schema: %s", util.SanitizeLog(schema))
-		logrus.WithError(err).Error(errMsg)
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
 	}
 
-	resp := GetCredentialsResponse{Credentials: gotCredentials.Credentials}
+	return respondCredentials(ctx, w, gotCredentials, page, fields)
+}
+
+type GetCredentialStatusResponse struct {
+	ID string `json:"id"`
+	// One of active or expired
+	Status         string `json:"status"`
+	ExpirationDate string `json:"expirationDate,omitempty"`
+}
+
+// GetCredentialStatus godoc
+// @Summary      Get Credential Status
+// @Description  Get the status of a credential by id, which is expired once the credential is past its expiration date
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  GetCredentialStatusResponse
+// @Failure      400  {string}  string  "Bad request"
+// @Failure      404  {string}  string  "Not found"
+// @Router       /v1/credentials/{id}/status [get]
+func (cr CredentialRouter) GetCredentialStatus(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := framework.GetParam(ctx, IDParam)
+	if id == nil {
+		errMsg := "cannot get credential status without ID parameter"
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	gotStatus, err := cr.serviceFor(ctx).GetCredentialStatus(credential.GetCredentialStatusRequest{ID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get status of credential with id: %s", *id)
+		if errors.As(err, &credential.CredentialNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusNotFound)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := GetCredentialStatusResponse{
+		ID:             gotStatus.ID,
+		Status:         gotStatus.Status,
+		ExpirationDate: gotStatus.ExpirationDate,
+	}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+type GetCredentialBundleResponse struct {
+	Credential credsdk.VerifiableCredential `json:"credential"`
+	// Set when the credential references a schema
+	Schema              *schema.VCJSONSchema `json:"schema,omitempty"`
+	IssuerDID           didsdk.DIDDocument   `json:"issuerDid"`
+	IssuerDIDResolution DIDResolution        `json:"issuerDidResolution"`
+}
+
+// DIDResolution describes which resolver resolved a DID, to help debug resolution failures
+type DIDResolution struct {
+	// One of local, driver or universal
+	Resolver string `json:"resolver"`
+	// Set when the DID was resolved remotely, the endpoint which resolved it
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// GetCredentialBundle godoc
+// @Summary      Get Credential Bundle
+// @Description  Get a credential together with its schema and its issuer's DID document, to verify it offline
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  GetCredentialBundleResponse
+// @Failure      400  {string}  string  "Bad request"
+// @Failure      404  {string}  string  "Not found"
+// @Router       /v1/credentials/{id}/bundle [get]
+func (cr CredentialRouter) GetCredentialBundle(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := framework.GetParam(ctx, IDParam)
+	if id == nil {
+		errMsg := "cannot get credential bundle without ID parameter"
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	gotBundle, err := cr.serviceFor(ctx).GetCredentialBundle(credential.GetCredentialBundleRequest{ID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get bundle of credential with id: %s", *id)
+		if errors.As(err, &credential.CredentialNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusNotFound)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := GetCredentialBundleResponse{
+		Credential: gotBundle.Credential,
+		Schema:     gotBundle.Schema,
+		IssuerDID:  gotBundle.IssuerDID,
+		IssuerDIDResolution: DIDResolution{
+			Resolver: gotBundle.IssuerDIDResolution.Resolver,
+			Endpoint: gotBundle.IssuerDIDResolution.Endpoint,
+		},
+	}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+type GetIssuerKeyHealthResponse struct {
+	KeyID     string         `json:"keyId"`
+	Algorithm crypto.KeyType `json:"algorithm"`
+	CreatedAt string         `json:"createdAt"`
+	// The key's age, and the configured maximum age if there is one, as durations such as "2160h0m0s"
+	Age        string `json:"age"`
+	MaxAge     string `json:"maxAge,omitempty"`
+	PastMaxAge bool   `json:"pastMaxAge"`
+}
+
+// GetIssuerKeyHealth godoc
+// @Summary      Get Issuer Key Health
+// @Description  Get the algorithm and age of an issuer's signing key, and whether it is past the configured maximum age
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        did  path      string  true  "Issuer DID"
+// @Success      200  {object}  GetIssuerKeyHealthResponse
+// @Failure      400  {string}  string  "Bad request"
+// @Failure      404  {string}  string  "Not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /v1/credentials/issuers/{did}/key-health [get]
+func (cr CredentialRouter) GetIssuerKeyHealth(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	did := framework.GetParam(ctx, DIDParam)
+	if did == nil {
+		errMsg := "cannot get issuer key health without DID parameter"
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	keyHealth, err := cr.serviceFor(ctx).GetIssuerKeyHealth(credential.GetIssuerKeyHealthRequest{Issuer: *did})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get key health for issuer: %s", *did)
+		if errors.As(err, &credential.IssuerKeyNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusNotFound)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	resp := GetIssuerKeyHealthResponse{
+		KeyID:      keyHealth.KeyID,
+		Algorithm:  keyHealth.Algorithm,
+		CreatedAt:  keyHealth.CreatedAt,
+		Age:        keyHealth.Age.String(),
+		PastMaxAge: keyHealth.PastMaxAge,
+	}
+	if keyHealth.MaxAge > 0 {
+		resp.MaxAge = keyHealth.MaxAge.String()
+	}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+type SetIssuanceFreezeRequest struct {
+	// The issuer to freeze or unfreeze issuance for. If empty, issuance is frozen or unfrozen for all issuers.
+	Issuer string `json:"issuer" validate:"omitempty,did"`
+	Frozen bool   `json:"frozen"`
+}
+
+type GetIssuanceFreezeResponse struct {
+	Global  bool     `json:"global"`
+	Issuers []string `json:"issuers,omitempty"`
+}
+
+// SetIssuanceFreeze godoc
+// @Summary      Set Issuance Freeze
+// @Description  Freeze or unfreeze credential issuance, globally or for a single issuer, for maintenance. Reads are unaffected.
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      SetIssuanceFreezeRequest  true  "request body"
+// @Success      200      {object}  GetIssuanceFreezeResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /v1/credentials/freeze [put]
+func (cr CredentialRouter) SetIssuanceFreeze(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var request SetIssuanceFreezeRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid set issuance freeze request"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	freeze, err := cr.serviceFor(ctx).SetIssuanceFreeze(credential.SetIssuanceFreezeRequest{Issuer: request.Issuer, Frozen: request.Frozen})
+	if err != nil {
+		errMsg := "could not set issuance freeze"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	resp := GetIssuanceFreezeResponse{Global: freeze.Global, Issuers: freeze.Issuers}
 	return framework.Respond(ctx, w, resp, http.StatusOK)
 }
 
+// GetIssuanceFreeze godoc
+// @Summary      Get Issuance Freeze
+// @Description  Get whether credential issuance is frozen, globally or for individual issuers
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  GetIssuanceFreezeResponse
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /v1/credentials/freeze [get]
+func (cr CredentialRouter) GetIssuanceFreeze(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	freeze, err := cr.serviceFor(ctx).GetIssuanceFreeze()
+	if err != nil {
+		errMsg := "could not get issuance freeze"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	resp := GetIssuanceFreezeResponse{Global: freeze.Global, Issuers: freeze.Issuers}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+type SetSubjectAliasRequest struct {
+	// A human-friendly name for the subject, which cannot itself be a DID
+	Alias string `json:"alias" validate:"required"`
+	DID   string `json:"did" validate:"required,did"`
+}
+
+type GetSubjectAliasResponse struct {
+	Alias string `json:"alias"`
+	DID   string `json:"did"`
+}
+
+type GetSubjectAliasesResponse struct {
+	Aliases []GetSubjectAliasResponse `json:"aliases"`
+}
+
+// SetSubjectAlias godoc
+// @Summary      Set Subject Alias
+// @Description  Register an alias for a subject's DID, which may be given in place of the DID when creating credentials or getting them by subject. Setting an existing alias points it at the new DID.
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      SetSubjectAliasRequest  true  "request body"
+// @Success      200      {object}  GetSubjectAliasResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /v1/credentials/aliases [put]
+func (cr CredentialRouter) SetSubjectAlias(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var request SetSubjectAliasRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid set subject alias request"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	alias, err := cr.serviceFor(ctx).SetSubjectAlias(credential.SetSubjectAliasRequest{Alias: request.Alias, DID: request.DID})
+	if err != nil {
+		errMsg := "could not set subject alias"
+		if errors.As(err, &credential.InvalidSubjectAliasError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	resp := GetSubjectAliasResponse{Alias: alias.Alias, DID: alias.DID}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+// GetSubjectAliases godoc
+// @Summary      Get Subject Aliases
+// @Description  Get every registered subject alias, ordered by name
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        pageSize   query     int     false  "most aliases to return, up to 100, returning a page of aliases"
+// @Param        pageToken  query     string  false  "token for the next page, from a previous page"
+// @Success      200        {object}  GetSubjectAliasesResponse
+// @Success      200        {object}  framework.Page
+// @Failure      400        {string}  string  "Bad request"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /v1/credentials/aliases [get]
+func (cr CredentialRouter) GetSubjectAliases(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := framework.GetPageRequest(r)
+	if err != nil {
+		return framework.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	gotAliases, err := cr.serviceFor(ctx).GetSubjectAliases()
+	if err != nil {
+		errMsg := "could not get subject aliases"
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	aliases := make([]GetSubjectAliasResponse, 0, len(gotAliases.Aliases))
+	for _, alias := range gotAliases.Aliases {
+		aliases = append(aliases, GetSubjectAliasResponse{Alias: alias.Alias, DID: alias.DID})
+	}
+	if page != nil {
+		aliasesPage, err := framework.NewPage(aliases, *page)
+		if err != nil {
+			errMsg := "could not paginate subject aliases"
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+		}
+		return framework.Respond(ctx, w, aliasesPage, http.StatusOK)
+	}
+	return framework.Respond(ctx, w, GetSubjectAliasesResponse{Aliases: aliases}, http.StatusOK)
+}
+
+// GetSubjectAlias godoc
+// @Summary      Get Subject Alias
+// @Description  Get the DID a subject alias resolves to
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        alias  path      string  true  "Alias"
+// @Success      200    {object}  GetSubjectAliasResponse
+// @Failure      400    {string}  string  "Bad request"
+// @Failure      404    {string}  string  "Not found"
+// @Router       /v1/credentials/aliases/{alias} [get]
+func (cr CredentialRouter) GetSubjectAlias(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	alias := framework.GetParam(ctx, AliasParam)
+	if alias == nil {
+		errMsg := "cannot get subject alias without alias parameter"
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	gotAlias, err := cr.serviceFor(ctx).GetSubjectAlias(credential.GetSubjectAliasRequest{Alias: *alias})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get subject alias: %s", util.SanitizeLog(*alias))
+		if errors.As(err, &credential.SubjectAliasNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusNotFound)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	resp := GetSubjectAliasResponse{Alias: gotAlias.Alias, DID: gotAlias.DID}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+// DeleteSubjectAlias godoc
+// @Summary      Delete Subject Alias
+// @Description  Delete a subject alias. Credentials already issued by the alias are unaffected.
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        alias  path      string  true  "Alias"
+// @Success      200    {string}  string  "OK"
+// @Failure      400    {string}  string  "Bad request"
+// @Failure      404    {string}  string  "Not found"
+// @Failure      500    {string}  string  "Internal server error"
+// @Router       /v1/credentials/aliases/{alias} [delete]
+func (cr CredentialRouter) DeleteSubjectAlias(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	alias := framework.GetParam(ctx, AliasParam)
+	if alias == nil {
+		errMsg := "cannot delete subject alias without alias parameter"
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	if err := cr.serviceFor(ctx).DeleteSubjectAlias(credential.DeleteSubjectAliasRequest{Alias: *alias}); err != nil {
+		errMsg := fmt.Sprintf("could not delete subject alias: %s", util.SanitizeLog(*alias))
+		if errors.As(err, &credential.SubjectAliasNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusNotFound)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	return framework.Respond(ctx, w, nil, http.StatusOK)
+}
+
 // DeleteCredential godoc
 // @Summary      Delete Credentials
 // @Description  Delete credential by ID
@@ -225,15 +1247,90 @@ func (cr CredentialRouter) DeleteCredential(ctx context.Context, w http.Response
 	id := framework.GetParam(ctx, IDParam)
 	if id == nil {
 		errMsg := "cannot delete credential without ID parameter"
-		logrus.Error(errMsg)
 		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
 	}
 
-	if err := cr.service.DeleteCredential(credential.DeleteCredentialRequest{ID: *id}); err != nil {
+	if err := cr.serviceFor(ctx).DeleteCredential(credential.DeleteCredentialRequest{ID: *id}); err != nil {
 		errMsg := fmt.Sprintf("could not delete credential with id: %s", *id)
-		logrus.WithError(err).Error(errMsg)
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
 	}
 
 	return framework.Respond(ctx, w, nil, http.StatusOK)
 }
+
+type DeleteCredentialsResponse struct {
+	// The number of credentials deleted, or which would be deleted for a dry run
+	Count int `json:"count"`
+	// The IDs of the credentials deleted, or which would be deleted for a dry run
+	IDs []string `json:"ids,omitempty"`
+	// The subject aliases deleted when deleting by subject alone, or which would be deleted for a dry run
+	Aliases []string `json:"aliases,omitempty"`
+	// The IDs of stored credentials which could not be read, such as those encrypted with a key which is no longer
+	// configured, so could not be checked against the filters and were not deleted
+	Unreadable []string `json:"unreadable,omitempty"`
+	DryRun     bool     `json:"dryRun"`
+}
+
+// DeleteCredentials godoc
+// @Summary      Delete Credentials
+// @Description  Delete all credentials matching an issuer, subject and/or schema, at least one of which is required.
+// @Description  Deleting by subject erases the credentials held about a person. The subject may be given by DID or
+// @Description  by alias, and deleting by subject alone also deletes every alias registered for the subject's DID.
+// @Tags         CredentialAPI
+// @Accept       json
+// @Produce      json
+// @Param        issuer   query     string  false  "string issuer"
+// @Param        subject  query     string  false  "string subject"
+// @Param        schema   query     string  false  "string schema"
+// @Param        dryRun  query     bool    false  "count the matching credentials without deleting them"
+// @Success      200     {object}  DeleteCredentialsResponse
+// @Failure      400     {string}  string  "Bad request"
+// @Failure      500     {string}  string  "Internal server error"
+// @Router       /v1/credentials [delete]
+func (cr CredentialRouter) DeleteCredentials(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	issuer := framework.GetQueryValue(r, IssuerParam)
+	subject := framework.GetQueryValue(r, SubjectParam)
+	schema := framework.GetQueryValue(r, SchemaParam)
+	if issuer == nil && subject == nil && schema == nil {
+		errMsg := "must use at least one of the following query parameters: issuer, subject, schema"
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	request := credential.DeleteCredentialsRequest{}
+	if issuer != nil {
+		request.Issuer = *issuer
+	}
+	if subject != nil {
+		request.Subject = *subject
+	}
+	if schema != nil {
+		request.Schema = *schema
+	}
+	// a value which cannot be read as a boolean is rejected, rather than risk deleting when a dry run was meant
+	if dryRun := framework.GetQueryValue(r, DryRunParam); dryRun != nil {
+		parsed, err := strconv.ParseBool(*dryRun)
+		if err != nil {
+			errMsg := fmt.Sprintf("invalid %s<%s>, must be true or false", DryRunParam, util.SanitizeLog(*dryRun))
+			return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+		}
+		request.DryRun = parsed
+	}
+
+	deleteResp, err := cr.serviceFor(ctx).DeleteCredentials(request)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not delete credentials for issuer<%s>, subject<%s> and schema<%s>", util.SanitizeLog(request.Issuer), util.SanitizeLog(request.Subject), util.SanitizeLog(request.Schema))
+		if errors.As(err, &credential.SubjectAliasNotFoundError{}) {
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+		}
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+	}
+
+	resp := DeleteCredentialsResponse{
+		Count:      deleteResp.Count,
+		IDs:        deleteResp.IDs,
+		Aliases:    deleteResp.Aliases,
+		Unreadable: deleteResp.Unreadable,
+		DryRun:     request.DryRun,
+	}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
@@ -1,15 +1,27 @@
 package router
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	credsdk "github.com/TBD54566975/ssi-sdk/credential"
 	"github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/pkg/service/credential"
 	"github.com/tbd54566975/ssi-service/pkg/service/framework"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+	"github.com/tbd54566975/ssi-service/pkg/service/schema"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -38,9 +50,16 @@ func TestCredentialRouter(t *testing.T) {
 		bolt, err := storage.NewBoltDB()
 		assert.NoError(tt, err)
 		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
 
-		serviceConfig := config.CredentialServiceConfig{BaseServiceConfig: &config.BaseServiceConfig{Name: "credential"}}
-		credService, err := credential.NewCredentialService(serviceConfig, bolt)
+		// credentials reference schemas by external URL, which the schema service does not know
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:    &config.BaseServiceConfig{Name: "credential"},
+			SchemaNotFoundPolicy: credential.SchemaNotFoundWarn,
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
 		assert.NoError(tt, err)
 		assert.NotEmpty(tt, credService)
 
@@ -152,4 +171,1010 @@ func TestCredentialRouter(t *testing.T) {
 		assert.Error(tt, err)
 		assert.Contains(tt, err.Error(), fmt.Sprintf("credential not found with id: %s", createdCred.Credential.ID))
 	})
+
+	t.Run("Credential Service Encryption Test", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		oldKey := base58.Encode([]byte(strings.Repeat("a", 32)))
+		newKey := base58.Encode([]byte(strings.Repeat("b", 32)))
+		encryption := config.CredentialEncryptionConfig{ActiveKeyVersion: "1", Keys: map[string]string{"1": oldKey}}
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:    &config.BaseServiceConfig{Name: "credential"},
+			SchemaNotFoundPolicy: credential.SchemaNotFoundWarn,
+			Encryption:           &encryption,
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, credService)
+
+		// create a credential
+		issuer := "did:test:encrypted-issuer"
+		subject := "did:test:encrypted-subject"
+		createdCred, err := credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:     issuer,
+			Subject:    subject,
+			JSONSchema: "https://encrypted-schema.com",
+			Data: map[string]interface{}{
+				"email": "hal@finney.com",
+			},
+			Expiry:     time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			StorageTTL: "1h",
+		})
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, createdCred)
+
+		// make sure nothing identifying is stored in the clear, nor as a plain hash which could be guessed
+		issuerHash := sha256.Sum256([]byte(issuer))
+		stored, err := bolt.ReadAll("credential")
+		assert.NoError(tt, err)
+		for k, v := range stored {
+			assert.NotContains(tt, k, issuer)
+			assert.NotContains(tt, k, hex.EncodeToString(issuerHash[:]))
+			assert.NotContains(tt, string(v), issuer)
+			assert.NotContains(tt, string(v), subject)
+			assert.NotContains(tt, string(v), "hal@finney.com")
+		}
+
+		// a credential stored before encryption was enabled
+		plainConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:    &config.BaseServiceConfig{Name: "credential"},
+			SchemaNotFoundPolicy: credential.SchemaNotFoundWarn,
+		}
+		plainService, err := credential.NewCredentialService(plainConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+		legacyCred, err := plainService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:     issuer,
+			Subject:    subject,
+			JSONSchema: "https://encrypted-schema.com",
+			Data: map[string]interface{}{
+				"email": "nick@szabo.com",
+			},
+		})
+		assert.NoError(tt, err)
+
+		// rotate the key, keeping the old one for reads
+		encryption = config.CredentialEncryptionConfig{ActiveKeyVersion: "2", Keys: map[string]string{"1": oldKey, "2": newKey}}
+		serviceConfig.Encryption = &encryption
+		credService, err = credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+
+		// get it back
+		gotCred, err := credService.GetCredential(credential.GetCredentialRequest{ID: createdCred.Credential.ID})
+		assert.NoError(tt, err)
+		createdBytes, err := json.Marshal(createdCred.Credential)
+		assert.NoError(tt, err)
+		gotBytes, err := json.Marshal(gotCred.Credential)
+		assert.NoError(tt, err)
+		assert.Equal(tt, createdBytes, gotBytes)
+
+		// query by the indexed values, finding both the encrypted credential and the one stored before encryption
+		credIDs := func(creds []credsdk.VerifiableCredential) []string {
+			var ids []string
+			for _, cred := range creds {
+				ids = append(ids, cred.ID)
+			}
+			return ids
+		}
+		bothIDs := []string{createdCred.Credential.ID, legacyCred.Credential.ID}
+		byIssuer, err := credService.GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: issuer})
+		assert.NoError(tt, err)
+		assert.ElementsMatch(tt, bothIDs, credIDs(byIssuer.Credentials))
+		for _, cred := range byIssuer.Credentials {
+			assert.Equal(tt, issuer, cred.Issuer)
+		}
+
+		bySubject, err := credService.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: subject})
+		assert.NoError(tt, err)
+		assert.ElementsMatch(tt, bothIDs, credIDs(bySubject.Credentials))
+
+		bySchema, err := credService.GetCredentialsBySchema(credential.GetCredentialBySchemaRequest{Schema: "https://encrypted-schema.com"})
+		assert.NoError(tt, err)
+		assert.ElementsMatch(tt, bothIDs, credIDs(bySchema.Credentials))
+
+		// a service without the old key cannot read the credential
+		encryption = config.CredentialEncryptionConfig{ActiveKeyVersion: "2", Keys: map[string]string{"2": newKey}}
+		serviceConfig.Encryption = &encryption
+		credService, err = credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+		_, err = credService.GetCredential(credential.GetCredentialRequest{ID: createdCred.Credential.ID})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "no encryption key configured for key version: 1")
+
+		// it is left out of listings, which still hold the credentials that can be read
+		byIssuer, err = credService.GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: issuer})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{legacyCred.Credential.ID}, credIDs(byIssuer.Credentials))
+
+		// deleting cannot check it, so reports it rather than leave it behind unnoticed
+		deleteResp, err := credService.DeleteCredentials(credential.DeleteCredentialsRequest{Issuer: issuer, DryRun: true})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{legacyCred.Credential.ID}, deleteResp.IDs)
+		assert.Equal(tt, []string{createdCred.Credential.ID}, deleteResp.Unreadable)
+
+		// purging needs only its purge time, which is kept in the clear
+		purged, err := credService.PurgeCredentials()
+		assert.NoError(tt, err)
+		assert.Zero(tt, purged)
+		credService.SetClock(func() time.Time { return time.Now().Add(2 * time.Hour) })
+		purged, err = credService.PurgeCredentials()
+		assert.NoError(tt, err)
+		assert.Equal(tt, 1, purged)
+		credService.SetClock(time.Now)
+
+		// bad configuration
+		encryption = config.CredentialEncryptionConfig{ActiveKeyVersion: "3", Keys: map[string]string{"2": newKey}}
+		serviceConfig.Encryption = &encryption
+		_, err = credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.Error(tt, err)
+
+		// delete it
+		err = credService.DeleteCredential(credential.DeleteCredentialRequest{ID: createdCred.Credential.ID})
+		assert.NoError(tt, err)
+		_, err = credService.GetCredential(credential.GetCredentialRequest{ID: createdCred.Credential.ID})
+		assert.Error(tt, err)
+
+		// leaving nothing which cannot be read
+		deleteResp, err = credService.DeleteCredentials(credential.DeleteCredentialsRequest{Issuer: issuer, DryRun: true})
+		assert.NoError(tt, err)
+		assert.Empty(tt, deleteResp.Unreadable)
+		_, err = credService.PurgeCredentials()
+		assert.NoError(tt, err)
+	})
+
+	t.Run("Credential Service Issuer Defaults Test", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		issuer := "did:test:defaults"
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig: &config.BaseServiceConfig{Name: "credential"},
+			RequiredClaims:    []string{"issuingBranch"},
+			IssuerDefaults: map[string]map[string]interface{}{
+				issuer: {
+					"issuingBranch": "main",
+					"country":       "US",
+				},
+			},
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, credService)
+
+		// defaults are added, and satisfy required claims
+		createdCred, err := credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:  issuer,
+			Subject: "did:test:345",
+			Data: map[string]interface{}{
+				"firstName": "Satoshi",
+			},
+		})
+		assert.NoError(tt, err)
+		assert.Equal(tt, "Satoshi", createdCred.Credential.CredentialSubject["firstName"])
+		assert.Equal(tt, "main", createdCred.Credential.CredentialSubject["issuingBranch"])
+		assert.Equal(tt, "US", createdCred.Credential.CredentialSubject["country"])
+
+		// values in the request take precedence over defaults
+		createdCred, err = credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:  issuer,
+			Subject: "did:test:345",
+			Data: map[string]interface{}{
+				"firstName":     "Satoshi",
+				"issuingBranch": "west",
+			},
+		})
+		assert.NoError(tt, err)
+		assert.Equal(tt, "west", createdCred.Credential.CredentialSubject["issuingBranch"])
+		assert.Equal(tt, "US", createdCred.Credential.CredentialSubject["country"])
+		assert.Equal(tt, "main", serviceConfig.IssuerDefaults[issuer]["issuingBranch"])
+
+		// defaults only apply to their issuer
+		_, err = credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:  "did:test:other",
+			Subject: "did:test:345",
+			Data: map[string]interface{}{
+				"firstName": "Satoshi",
+			},
+		})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "credential data is missing required claim(s): issuingBranch")
+
+		// defaults are validated against the credential's schema along with the rest of the data
+		schemaService, err := schema.NewSchemaService(config.SchemaServiceConfig{}, bolt)
+		require.NoError(tt, err)
+		canadianSchema, err := schemaService.CreateSchema(schema.CreateSchemaRequest{Author: "did:test", Name: "canadian", Schema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"country": map[string]interface{}{
+					"type": "string",
+					"enum": []interface{}{"CA"},
+				},
+			},
+		}})
+		require.NoError(tt, err)
+
+		_, err = credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:     issuer,
+			Subject:    "did:test:345",
+			JSONSchema: canadianSchema.ID,
+			Data: map[string]interface{}{
+				"firstName": "Satoshi",
+			},
+		})
+		var invalidErr credential.InvalidCredentialDataError
+		require.ErrorAs(tt, err, &invalidErr)
+		require.Len(tt, invalidErr.Errors, 1)
+		assert.Equal(tt, "country", invalidErr.Errors[0].Field)
+
+		// unless the request overrides them
+		createdCred, err = credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:     issuer,
+			Subject:    "did:test:345",
+			JSONSchema: canadianSchema.ID,
+			Data: map[string]interface{}{
+				"firstName": "Satoshi",
+				"country":   "CA",
+			},
+		})
+		assert.NoError(tt, err)
+		assert.Equal(tt, "CA", createdCred.Credential.CredentialSubject["country"])
+	})
+
+	t.Run("Credential Service Log Redaction Test", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		// capture all logs
+		var buf bytes.Buffer
+		logrus.SetOutput(&buf)
+		level := logrus.GetLevel()
+		logrus.SetLevel(logrus.DebugLevel)
+		tt.Cleanup(func() {
+			logrus.SetOutput(os.Stderr)
+			logrus.SetLevel(level)
+		})
+
+		serviceConfig := config.CredentialServiceConfig{BaseServiceConfig: &config.BaseServiceConfig{Name: "credential"}}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+
+		ssn := "123-45-6789"
+		_, err = credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:  "did:test:123",
+			Subject: "did:test:345",
+			Data: map[string]interface{}{
+				"ssn": ssn,
+			},
+		})
+		assert.NoError(tt, err)
+
+		// a failure to issue does not leak the data either
+		_, err = credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:  "did:test:123",
+			Subject: "did:test:345",
+			Data: map[string]interface{}{
+				"ssn": ssn,
+				"id":  "did:test:other",
+			},
+		})
+		assert.Error(tt, err)
+		assert.NotContains(tt, err.Error(), ssn)
+
+		assert.Contains(tt, buf.String(), "creating credential")
+		assert.NotContains(tt, buf.String(), ssn)
+	})
+
+	t.Run("Credential Service Subject Limits Test", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		// unsupported policy
+		licenseSchema := "https://license-schema.com"
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:    &config.BaseServiceConfig{Name: "credential"},
+			SchemaNotFoundPolicy: credential.SchemaNotFoundWarn,
+			SubjectLimits: map[string]config.SubjectLimitConfig{
+				licenseSchema: {MaxActive: 1, Policy: "revoke-prior"},
+			},
+		}
+		_, err = credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "unsupported subject limit policy<revoke-prior>")
+
+		serviceConfig.SubjectLimits[licenseSchema] = config.SubjectLimitConfig{MaxActive: 1, Policy: credential.SubjectLimitReject}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+
+		createLicense := func(subject, expiry string) error {
+			_, err := credService.CreateCredential(credential.CreateCredentialRequest{
+				Issuer:     "did:test:dmv",
+				Subject:    subject,
+				JSONSchema: licenseSchema,
+				Data: map[string]interface{}{
+					"class": "C",
+				},
+				Expiry: expiry,
+			})
+			return err
+		}
+
+		// an expired license is not active
+		err = createLicense("did:test:driver", time.Now().Add(-time.Hour).Format(time.RFC3339))
+		assert.NoError(tt, err)
+
+		// first active license
+		err = createLicense("did:test:driver", time.Now().Add(24*time.Hour).Format(time.RFC3339))
+		assert.NoError(tt, err)
+
+		// a second active license is rejected
+		err = createLicense("did:test:driver", "")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "subject<did:test:driver> already holds the maximum of 1 active credential(s)")
+
+		// other subjects, including those whose ID contains the first subject's, are unaffected
+		err = createLicense("did:test:driver2", "")
+		assert.NoError(tt, err)
+
+		// other schemas are unaffected
+		_, err = credService.CreateCredential(credential.CreateCredentialRequest{
+			Issuer:  "did:test:dmv",
+			Subject: "did:test:driver",
+			Data: map[string]interface{}{
+				"class": "C",
+			},
+		})
+		assert.NoError(tt, err)
+
+		// credentials in the same batch count towards the limit, and a batch over it is rejected as a whole
+		batchLicense := func(expiry string) credential.CreateCredentialRequest {
+			return credential.CreateCredentialRequest{
+				Issuer:     "did:test:dmv",
+				Subject:    "did:test:batch-driver",
+				JSONSchema: licenseSchema,
+				Data: map[string]interface{}{
+					"class": "C",
+				},
+				Expiry: expiry,
+			}
+		}
+		_, err = credService.CreateCredentials(context.Background(), credential.BatchCreateCredentialsRequest{
+			Requests: []credential.CreateCredentialRequest{batchLicense(""), batchLicense("")},
+		})
+		assert.Error(tt, err)
+		var itemErr credential.BatchItemError
+		require.ErrorAs(tt, err, &itemErr)
+		assert.Equal(tt, 1, itemErr.Index)
+		assert.ErrorAs(tt, err, &credential.SubjectLimitError{})
+		gotCreds, err := credService.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: "did:test:batch-driver"})
+		require.NoError(tt, err)
+		assert.Empty(tt, gotCreds.Credentials)
+
+		// an expired credential in the batch is not active
+		_, err = credService.CreateCredentials(context.Background(), credential.BatchCreateCredentialsRequest{
+			Requests: []credential.CreateCredentialRequest{batchLicense(time.Now().Add(-time.Hour).Format(time.RFC3339)), batchLicense("")},
+		})
+		assert.NoError(tt, err)
+
+		// nor is the limit exceeded by credentials from CSV rows
+		_, err = credService.CreateCredentialsFromCSV(context.Background(), credential.CreateCredentialsFromCSVRequest{
+			CSV:      "did,class\ndid:test:csv-driver,C\ndid:test:csv-driver,D\n",
+			Mapping:  map[string]string{"class": "class"},
+			Template: credential.CreateCredentialRequest{Issuer: "did:test:dmv", Subject: "${did}", JSONSchema: licenseSchema},
+		})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "line<3>: subject<did:test:csv-driver> already holds the maximum of 1 active credential(s)")
+	})
+
+	t.Run("Credential Service Subject Limits On Empty Storage", func(tt *testing.T) {
+		// no credential has ever been stored
+		_ = os.Remove(storage.DBFile)
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		licenseSchema := "https://license-schema.com"
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:    &config.BaseServiceConfig{Name: "credential"},
+			SchemaNotFoundPolicy: credential.SchemaNotFoundWarn,
+			SubjectLimits: map[string]config.SubjectLimitConfig{
+				licenseSchema: {MaxActive: 1, Policy: credential.SubjectLimitReject},
+			},
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+
+		createLicense := func() error {
+			_, err := credService.CreateCredential(credential.CreateCredentialRequest{
+				Issuer:     "did:test:dmv",
+				Subject:    "did:test:driver",
+				JSONSchema: licenseSchema,
+				Data: map[string]interface{}{
+					"class": "C",
+				},
+			})
+			return err
+		}
+
+		err = createLicense()
+		assert.NoError(tt, err)
+
+		err = createLicense()
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "subject<did:test:driver> already holds the maximum of 1 active credential(s)")
+	})
+
+	t.Run("Credential Service Subject Limits Under Concurrency", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		licenseSchema := "https://license-schema.com"
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:    &config.BaseServiceConfig{Name: "credential"},
+			SchemaNotFoundPolicy: credential.SchemaNotFoundWarn,
+			SubjectLimits: map[string]config.SubjectLimitConfig{
+				licenseSchema: {MaxActive: 2, Policy: credential.SubjectLimitReject},
+			},
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+
+		// a slow clock, read when building each credential after its subject's limit is first checked, gives concurrent
+		// requests time to each count the same credentials
+		credService.SetClock(func() time.Time {
+			time.Sleep(10 * time.Millisecond)
+			return time.Now()
+		})
+
+		// requests for the same subject, singly and as batches, all at once
+		licenseRequest := credential.CreateCredentialRequest{
+			Issuer:     "did:test:dmv",
+			Subject:    "did:test:concurrent-driver",
+			JSONSchema: licenseSchema,
+			Data: map[string]interface{}{
+				"class": "C",
+			},
+		}
+		const requests = 10
+		var wg sync.WaitGroup
+		errs := make([]error, requests)
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if i%2 == 0 {
+					_, errs[i] = credService.CreateCredential(licenseRequest)
+					return
+				}
+				batchRequest := credential.BatchCreateCredentialsRequest{Requests: []credential.CreateCredentialRequest{licenseRequest}}
+				_, errs[i] = credService.CreateCredentials(context.Background(), batchRequest)
+			}(i)
+		}
+		wg.Wait()
+
+		// only as many as the limit allows are issued, and the rest are rejected for the limit
+		issued := 0
+		for _, err := range errs {
+			if err == nil {
+				issued++
+				continue
+			}
+			assert.True(tt, errors.As(err, &credential.SubjectLimitError{}), err.Error())
+		}
+		assert.Equal(tt, 2, issued)
+
+		held, err := credService.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: licenseRequest.Subject})
+		assert.NoError(tt, err)
+		assert.Len(tt, held.Credentials, 2)
+	})
+
+	t.Run("Allowed Types", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig: &config.BaseServiceConfig{Name: "credential"},
+			AllowedTypes:      []string{"DriversLicense"},
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+
+		createCredential := func(types ...string) (*credential.CreateCredentialResponse, error) {
+			return credService.CreateCredential(credential.CreateCredentialRequest{
+				Issuer:  "did:test:dmv",
+				Subject: "did:test:typed",
+				Type:    types,
+				Data: map[string]interface{}{
+					"class": "C",
+				},
+			})
+		}
+
+		// allowed types, including the base type
+		createdCred, err := createCredential("VerifiableCredential", "DriversLicense")
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{"VerifiableCredential", "DriversLicense"}, createdCred.Credential.Type)
+
+		// no additional types
+		_, err = createCredential()
+		assert.NoError(tt, err)
+
+		// a disallowed type, reported by name
+		_, err = createCredential("DriversLicense", "DriverLicense")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "credential type(s) not allowed: DriverLicense")
+	})
+
+	t.Run("Dedupe", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:    &config.BaseServiceConfig{Name: "credential"},
+			SchemaNotFoundPolicy: credential.SchemaNotFoundWarn,
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+
+		now := time.Now()
+		createAt := func(issuedAt time.Time, issuer, expiry string) string {
+			credService.SetClock(func() time.Time { return issuedAt })
+			createdCred, err := credService.CreateCredential(credential.CreateCredentialRequest{
+				Issuer:     issuer,
+				Subject:    "did:test:deduped",
+				JSONSchema: "https://membership-schema.com",
+				Data: map[string]interface{}{
+					"level": "gold",
+				},
+				Expiry: expiry,
+			})
+			require.NoError(tt, err)
+			return createdCred.Credential.ID
+		}
+
+		createAt(now.Add(-3*time.Hour), "did:test:club", "")
+		latest := createAt(now.Add(-time.Hour), "did:test:club", "")
+		createAt(now.Add(-2*time.Hour), "did:test:club", "")
+		expired := createAt(now.Add(-4*time.Hour), "did:test:club", now.Add(-time.Minute).Format(time.RFC3339))
+		otherIssuer := createAt(now.Add(-3*time.Hour), "did:test:gym", "")
+		credService.SetClock(func() time.Time { return now })
+
+		gotCreds, err := credService.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: "did:test:deduped"})
+		assert.NoError(tt, err)
+		assert.Len(tt, gotCreds.Credentials, 5)
+		assert.Empty(tt, gotCreds.SupersededCounts)
+
+		// only the most recent active credential per issuer and schema remains, alongside the expired credential
+		gotCreds, err = credService.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: "did:test:deduped", Dedupe: true})
+		assert.NoError(tt, err)
+		var gotIDs []string
+		for _, cred := range gotCreds.Credentials {
+			gotIDs = append(gotIDs, cred.ID)
+		}
+		assert.ElementsMatch(tt, []string{latest, expired, otherIssuer}, gotIDs)
+		assert.Equal(tt, map[string]int{latest: 2}, gotCreds.SupersededCounts)
+	})
+
+	t.Run("Sorting", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		serviceConfig := config.CredentialServiceConfig{BaseServiceConfig: &config.BaseServiceConfig{Name: "credential"}}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.NoError(tt, err)
+
+		now := time.Now()
+		createAt := func(issuedAt time.Time) string {
+			credService.SetClock(func() time.Time { return issuedAt })
+			createdCred, err := credService.CreateCredential(credential.CreateCredentialRequest{
+				Issuer:  "did:test:sorted",
+				Subject: "did:test:holder",
+				Data:    map[string]interface{}{"rank": "first"},
+			})
+			require.NoError(tt, err)
+			return createdCred.Credential.ID
+		}
+		middle := createAt(now.Add(-2 * time.Hour))
+		newest := createAt(now.Add(-time.Hour))
+		oldest := createAt(now.Add(-3 * time.Hour))
+
+		listIDs := func(sort string) []string {
+			gotCreds, err := credService.GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: "did:test:sorted", Sort: sort})
+			require.NoError(tt, err)
+			var ids []string
+			for _, cred := range gotCreds.Credentials {
+				ids = append(ids, cred.ID)
+			}
+			return ids
+		}
+
+		// most recently issued first by default
+		assert.Equal(tt, []string{newest, middle, oldest}, listIDs(framework.SortDefault))
+		assert.Equal(tt, []string{newest, middle, oldest}, listIDs(framework.SortCreatedDesc))
+		assert.Equal(tt, []string{oldest, middle, newest}, listIDs(framework.SortCreatedAsc))
+	})
+
+	t.Run("Timestamp Precision", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:  &config.BaseServiceConfig{Name: "credential"},
+			TimestampPrecision: "nanoseconds",
+		}
+		_, err = credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid timestamp precision<nanoseconds>")
+
+		issuedAt := time.Date(2022, 8, 1, 12, 30, 15, 123456789, time.UTC)
+		createRequest := credential.CreateCredentialRequest{
+			Issuer:  "did:test:precise",
+			Subject: "did:test:holder",
+			Data:    map[string]interface{}{"rank": "first"},
+			Expiry:  "2023-08-01T12:30:15.987654+02:00",
+		}
+
+		// seconds by default, without any fractional part
+		serviceConfig.TimestampPrecision = ""
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService.SetClock(func() time.Time { return issuedAt })
+		createdCred, err := credService.CreateCredential(createRequest)
+		require.NoError(tt, err)
+		assert.Equal(tt, "2022-08-01T12:30:15Z", createdCred.Credential.IssuanceDate)
+		assert.Equal(tt, "2023-08-01T12:30:15+02:00", createdCred.Credential.ExpirationDate)
+
+		serviceConfig.TimestampPrecision = credential.PrecisionMilliseconds
+		credService, err = credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService.SetClock(func() time.Time { return issuedAt })
+		createdCred, err = credService.CreateCredential(createRequest)
+		require.NoError(tt, err)
+		assert.Equal(tt, "2022-08-01T12:30:15.123Z", createdCred.Credential.IssuanceDate)
+		assert.Equal(tt, "2023-08-01T12:30:15.987+02:00", createdCred.Credential.ExpirationDate)
+
+		// invalid expiry is still rejected
+		createRequest.Expiry = "next year"
+		_, err = credService.CreateCredential(createRequest)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Storage TTL", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig:    &config.BaseServiceConfig{Name: "credential"},
+			StoragePurgeInterval: "10ms",
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+
+		now := time.Now()
+		credService.SetClock(func() time.Time { return now })
+		createRequest := credential.CreateCredentialRequest{
+			Issuer:     "did:test:ephemeral",
+			Subject:    "did:test:holder",
+			Data:       map[string]interface{}{"scope": "read"},
+			StorageTTL: "soon",
+		}
+		_, err = credService.CreateCredential(createRequest)
+		assert.ErrorAs(tt, err, &credential.InvalidStorageTTLError{})
+
+		createRequest.StorageTTL = "1h"
+		ephemeral, err := credService.CreateCredential(createRequest)
+		require.NoError(tt, err)
+		createRequest.StorageTTL = ""
+		kept, err := credService.CreateCredential(createRequest)
+		require.NoError(tt, err)
+		ephemeralID, keptID := ephemeral.Credential.ID, kept.Credential.ID
+
+		_, err = credService.GetCredential(credential.GetCredentialRequest{ID: ephemeralID})
+		assert.NoError(tt, err)
+
+		// once past its TTL, the credential is gone even before it is purged
+		later := now.Add(2 * time.Hour)
+		credService.SetClock(func() time.Time { return later })
+		_, err = credService.GetCredential(credential.GetCredentialRequest{ID: ephemeralID})
+		assert.ErrorAs(tt, err, &credential.CredentialNotFoundError{})
+		gotCreds, err := credService.GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: "did:test:ephemeral"})
+		require.NoError(tt, err)
+		require.Len(tt, gotCreds.Credentials, 1)
+		assert.Equal(tt, keptID, gotCreds.Credentials[0].ID)
+
+		// the purge job removes it from storage, so it stays gone at any time
+		stop := make(chan struct{})
+		tt.Cleanup(func() { close(stop) })
+		go credService.RunStoragePurge(stop)
+		credService.SetClock(func() time.Time { return now })
+		assert.Eventually(tt, func() bool {
+			_, err := credService.GetCredential(credential.GetCredentialRequest{ID: ephemeralID})
+			return errors.As(err, &credential.CredentialNotFoundError{})
+		}, time.Second, 10*time.Millisecond)
+
+		_, err = credService.GetCredential(credential.GetCredentialRequest{ID: keptID})
+		assert.NoError(tt, err)
+
+	})
+
+	t.Run("Storage TTL Purge Index", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		serviceConfig := config.CredentialServiceConfig{BaseServiceConfig: &config.BaseServiceConfig{Name: "credential"}}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+
+		now := time.Now()
+		credService.SetClock(func() time.Time { return now })
+		createRequest := credential.CreateCredentialRequest{
+			Issuer:     "did:test:ephemeral",
+			Subject:    "did:test:holder",
+			Data:       map[string]interface{}{"scope": "read"},
+			StorageTTL: "1h",
+		}
+		due, err := credService.CreateCredential(createRequest)
+		require.NoError(tt, err)
+		createRequest.StorageTTL = "3h"
+		notDue, err := credService.CreateCredential(createRequest)
+		require.NoError(tt, err)
+
+		// a credential deleted before its purge time leaves its index entry, which goes once due
+		createRequest.StorageTTL = "1h"
+		deleted, err := credService.CreateCredential(createRequest)
+		require.NoError(tt, err)
+		err = credService.DeleteCredential(credential.DeleteCredentialRequest{ID: deleted.Credential.ID})
+		require.NoError(tt, err)
+
+		// purges read only the index entries which are due, leaving the rest indexed
+		credService.SetClock(func() time.Time { return now.Add(2 * time.Hour) })
+		_, err = credService.PurgeCredentials()
+		require.NoError(tt, err)
+		indexed, err := bolt.ReadAllKeys("credential-purge")
+		require.NoError(tt, err)
+		require.Len(tt, indexed, 1)
+		assert.True(tt, strings.HasSuffix(indexed[0], "/"+notDue.Credential.ID))
+
+		credService.SetClock(func() time.Time { return now })
+		_, err = credService.GetCredential(credential.GetCredentialRequest{ID: due.Credential.ID})
+		assert.ErrorAs(tt, err, &credential.CredentialNotFoundError{})
+		_, err = credService.GetCredential(credential.GetCredentialRequest{ID: notDue.Credential.ID})
+		assert.NoError(tt, err)
+	})
+
+	t.Run("Batch Parallelism", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		_, err = credential.NewCredentialService(config.CredentialServiceConfig{BatchParallelism: -1}, bolt, testKeyStoreService(tt, bolt))
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "batch parallelism cannot be negative: -1")
+
+		serviceConfig := config.CredentialServiceConfig{
+			BaseServiceConfig: &config.BaseServiceConfig{Name: "credential"},
+			AllowedTypes:      []string{"Membership"},
+			RequiredClaims:    []string{"name"},
+			BatchParallelism:  4,
+		}
+		credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+
+		batchRequest := func(size int) credential.BatchCreateCredentialsRequest {
+			var request credential.BatchCreateCredentialsRequest
+			for i := 0; i < size; i++ {
+				request.Requests = append(request.Requests, credential.CreateCredentialRequest{
+					Issuer:  "did:test:club",
+					Subject: fmt.Sprintf("did:test:member%d", i),
+					Data:    map[string]interface{}{"name": fmt.Sprintf("member %d", i)},
+				})
+			}
+			return request
+		}
+
+		// credentials are returned in request order
+		created, err := credService.CreateCredentials(context.Background(), batchRequest(20))
+		require.NoError(tt, err)
+		require.Len(tt, created.Credentials, 20)
+		for i, cred := range created.Credentials {
+			assert.Equal(tt, fmt.Sprintf("did:test:member%d", i), cred.CredentialSubject["id"])
+		}
+
+		// the first failed request is reported, however the work was divided
+		request := batchRequest(20)
+		request.Requests[7].Type = []string{"Fellowship"}
+		request.Requests[3].Type = []string{"Fellowship"}
+		_, err = credService.CreateCredentials(context.Background(), request)
+		var itemErr credential.BatchItemError
+		require.ErrorAs(tt, err, &itemErr)
+		assert.Equal(tt, 3, itemErr.Index)
+
+		// every failed row is reported, in order
+		csvRequest := credential.CreateCredentialsFromCSVRequest{
+			CSV:      "did,name\ndid:test:a,Ann\ndid:test:b,\ndid:test:c,Cal\ndid:test:d,\ndid:test:e,\n",
+			Mapping:  map[string]string{"name": "name"},
+			Template: credential.CreateCredentialRequest{Issuer: "did:test:club", Subject: "${did}"},
+		}
+		_, err = credService.CreateCredentialsFromCSV(context.Background(), csvRequest)
+		var rowErrs credential.CSVRowErrors
+		require.ErrorAs(tt, err, &rowErrs)
+		var lines []int
+		for _, rowErr := range rowErrs {
+			lines = append(lines, rowErr.Line)
+		}
+		assert.Equal(tt, []int{3, 5, 6}, lines)
+
+		// nothing is issued once the request is abandoned
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		request = batchRequest(20)
+		for i := range request.Requests {
+			request.Requests[i].Issuer = "did:test:abandoned"
+		}
+		_, err = credService.CreateCredentials(ctx, request)
+		assert.ErrorIs(tt, err, context.Canceled)
+		gotCreds, err := credService.GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: "did:test:abandoned"})
+		require.NoError(tt, err)
+		assert.Empty(tt, gotCreds.Credentials)
+	})
+
+	t.Run("Subject Aliases", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, bolt)
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+		})
+
+		credService, err := credential.NewCredentialService(config.CredentialServiceConfig{}, bolt, testKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+
+		// an alias cannot be a DID, and must be for one
+		_, err = credService.SetSubjectAlias(credential.SetSubjectAliasRequest{Alias: "did:test:alice", DID: "did:test:alice"})
+		assert.ErrorAs(tt, err, &credential.InvalidSubjectAliasError{})
+		_, err = credService.SetSubjectAlias(credential.SetSubjectAliasRequest{Alias: "alice", DID: "alice"})
+		assert.ErrorAs(tt, err, &credential.InvalidSubjectAliasError{})
+
+		_, err = credService.SetSubjectAlias(credential.SetSubjectAliasRequest{Alias: "alice", DID: "did:test:alice"})
+		require.NoError(tt, err)
+
+		// the credential is issued to the DID, recording the alias it was resolved from
+		createRequest := credential.CreateCredentialRequest{
+			Issuer:  "did:test:issuer",
+			Subject: "alice",
+			Data:    map[string]interface{}{"name": "Alice"},
+		}
+		created, err := credService.CreateCredential(createRequest)
+		require.NoError(tt, err)
+		assert.Equal(tt, "did:test:alice", created.Credential.CredentialSubject["id"])
+		assert.Equal(tt, "alice", created.SubjectAlias)
+
+		gotCred, err := credService.GetCredential(credential.GetCredentialRequest{ID: created.Credential.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, "alice", gotCred.SubjectAlias)
+
+		// credentials can be listed by either the alias or the DID
+		for _, subject := range []string{"alice", "did:test:alice"} {
+			gotCreds, err := credService.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: subject})
+			require.NoError(tt, err)
+			require.Len(tt, gotCreds.Credentials, 1, subject)
+			assert.Equal(tt, created.Credential.ID, gotCreds.Credentials[0].ID)
+		}
+
+		// repointing the alias leaves issued credentials with the DID they were issued to
+		_, err = credService.SetSubjectAlias(credential.SetSubjectAliasRequest{Alias: "alice", DID: "did:test:alice2"})
+		require.NoError(tt, err)
+		gotCreds, err := credService.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: "alice"})
+		require.NoError(tt, err)
+		assert.Empty(tt, gotCreds.Credentials)
+
+		aliases, err := credService.GetSubjectAliases()
+		require.NoError(tt, err)
+		assert.Equal(tt, []credential.GetSubjectAliasResponse{{Alias: "alice", DID: "did:test:alice2"}}, aliases.Aliases)
+
+		// an alias which cannot be resolved is an error, for issuance and lookup alike
+		require.NoError(tt, credService.DeleteSubjectAlias(credential.DeleteSubjectAliasRequest{Alias: "alice"}))
+		_, err = credService.CreateCredential(createRequest)
+		assert.ErrorAs(tt, err, &credential.SubjectAliasNotFoundError{})
+		_, err = credService.GetCredentialsBySubject(credential.GetCredentialBySubjectRequest{Subject: "alice"})
+		assert.ErrorAs(tt, err, &credential.SubjectAliasNotFoundError{})
+		err = credService.DeleteSubjectAlias(credential.DeleteSubjectAliasRequest{Alias: "alice"})
+		assert.ErrorAs(tt, err, &credential.SubjectAliasNotFoundError{})
+	})
+}
+
+func BenchmarkCreateCredentials(b *testing.B) {
+	for _, parallelism := range []int{1, 4} {
+		b.Run(fmt.Sprintf("parallelism %d", parallelism), func(bb *testing.B) {
+			bolt, err := storage.NewBoltDB()
+			require.NoError(bb, err)
+			bb.Cleanup(func() {
+				_ = bolt.Close()
+				_ = os.Remove(storage.DBFile)
+			})
+
+			serviceConfig := config.CredentialServiceConfig{
+				BaseServiceConfig: &config.BaseServiceConfig{Name: "credential"},
+				BatchParallelism:  parallelism,
+			}
+			credService, err := credential.NewCredentialService(serviceConfig, bolt, testKeyStoreService(bb, bolt))
+			require.NoError(bb, err)
+
+			var request credential.BatchCreateCredentialsRequest
+			for i := 0; i < credential.MaxBatchCreateCredentials; i++ {
+				request.Requests = append(request.Requests, credential.CreateCredentialRequest{
+					Issuer:  "did:test:bench",
+					Subject: fmt.Sprintf("did:test:holder%d", i),
+					Data:    map[string]interface{}{"index": i},
+				})
+			}
+
+			bb.ResetTimer()
+			for i := 0; i < bb.N; i++ {
+				_, err := credService.CreateCredentials(context.Background(), request)
+				require.NoError(bb, err)
+			}
+		})
+	}
+}
+
+func testKeyStoreService(t testing.TB, bolt *storage.BoltDB) *keystore.Service {
+	serviceConfig := config.KeyStoreServiceConfig{
+		BaseServiceConfig:  &config.BaseServiceConfig{Name: "keystore"},
+		ServiceKeyPassword: "test-password",
+	}
+	keyStoreService, err := keystore.NewKeyStoreService(serviceConfig, bolt)
+	assert.NoError(t, err)
+	return keyStoreService
 }
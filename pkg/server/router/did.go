@@ -39,6 +39,11 @@ func NewDIDRouter(s svcframework.Service) (*DIDRouter, error) {
 	}, nil
 }
 
+// serviceFor gets the DID service, logging with the ID of the request in the context
+func (dr DIDRouter) serviceFor(ctx context.Context) *did.Service {
+	return dr.service.WithLogger(framework.RequestLogger(ctx))
+}
+
 type GetDIDMethodsResponse struct {
 	DIDMethods []did.Method `json:"didMethods,omitempty"`
 }
@@ -95,7 +100,7 @@ func (dr DIDRouter) CreateDIDByMethod(ctx context.Context, w http.ResponseWriter
 
 	// TODO(gabe) check if the key type is supported for the method, to tell whether this is a bad req or internal error
 	createDIDRequest := did.CreateDIDRequest{Method: did.Method(*method), KeyType: request.KeyType}
-	createDIDResponse, err := dr.service.CreateDIDByMethod(createDIDRequest)
+	createDIDResponse, err := dr.serviceFor(ctx).CreateDIDByMethod(createDIDRequest)
 	if err != nil {
 		errMsg := fmt.Sprintf("could not create DID for method<%s> with key type: %s", *method, request.KeyType)
 		logrus.WithError(err).Error(errMsg)
@@ -142,7 +147,7 @@ func (dr DIDRouter) GetDIDByMethod(ctx context.Context, w http.ResponseWriter, _
 	// TODO(gabe) check if the method is supported, to tell whether this is a bad req or internal error
 	// TODO(gabe) differentiate between internal errors and not found DIDs
 	getDIDRequest := did.GetDIDRequest{Method: did.Method(*method), ID: *id}
-	gotDID, err := dr.service.GetDIDByMethod(getDIDRequest)
+	gotDID, err := dr.serviceFor(ctx).GetDIDByMethod(getDIDRequest)
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get DID for method<%s> with id: %s", *method, *id)
 		logrus.WithError(err).Error(errMsg)
@@ -152,3 +157,111 @@ func (dr DIDRouter) GetDIDByMethod(ctx context.Context, w http.ResponseWriter, _
 	resp := GetDIDByMethodResponse{DID: gotDID.DID}
 	return framework.Respond(ctx, w, resp, http.StatusOK)
 }
+
+type SetDIDDisplayRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Either an http(s) URL of the logo, or a small image inline as a base64 data URI
+	Logo string `json:"logo,omitempty"`
+	// Colors in the hex form #RRGGBB
+	BackgroundColor string `json:"backgroundColor,omitempty"`
+	TextColor       string `json:"textColor,omitempty"`
+}
+
+func (s SetDIDDisplayRequest) ToServiceRequest(method, id string) did.SetDIDDisplayRequest {
+	return did.SetDIDDisplayRequest{
+		Method: did.Method(method),
+		ID:     id,
+		Display: did.Display{
+			Name:            s.Name,
+			Description:     s.Description,
+			Logo:            s.Logo,
+			BackgroundColor: s.BackgroundColor,
+			TextColor:       s.TextColor,
+		},
+	}
+}
+
+type GetDIDDisplayResponse struct {
+	Display did.Display `json:"display"`
+}
+
+// SetDIDDisplay godoc
+// @Summary      Set DID Display
+// @Description  Set the display metadata, such as a name and logo, wallets use to render a DID's controller as an issuer
+// @Tags         DecentralizedIdentityAPI
+// @Accept       json
+// @Produce      json
+// @Param        request  body      SetDIDDisplayRequest  true  "request body"
+// @Param        method   path      string                true  "Method"
+// @Param        id       path      string                true  "ID"
+// @Success      200      {object}  GetDIDDisplayResponse
+// @Failure      400      {string}  string  "Bad request"
+// @Router       /v1/dids/{method}/{id}/display [put]
+func (dr DIDRouter) SetDIDDisplay(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	method := framework.GetParam(ctx, MethodParam)
+	if method == nil {
+		errMsg := "set DID display request missing method parameter"
+		logrus.Error(errMsg)
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+	id := framework.GetParam(ctx, IDParam)
+	if id == nil {
+		errMsg := fmt.Sprintf("set DID display request missing id parameter for method: %s", *method)
+		logrus.Error(errMsg)
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	var request SetDIDDisplayRequest
+	if err := framework.Decode(r, &request); err != nil {
+		errMsg := "invalid set DID display request"
+		logrus.WithError(err).Error(errMsg)
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	setDisplayResponse, err := dr.serviceFor(ctx).SetDIDDisplay(request.ToServiceRequest(*method, *id))
+	if err != nil {
+		errMsg := fmt.Sprintf("could not set display for DID<%s> with method: %s", *id, *method)
+		logrus.WithError(err).Error(errMsg)
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := GetDIDDisplayResponse{Display: setDisplayResponse.Display}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
+
+// GetDIDDisplay godoc
+// @Summary      Get DID Display
+// @Description  Get the display metadata attached to a DID
+// @Tags         DecentralizedIdentityAPI
+// @Accept       json
+// @Produce      json
+// @Param        method  path      string  true  "Method"
+// @Param        id      path      string  true  "ID"
+// @Success      200     {object}  GetDIDDisplayResponse
+// @Failure      400     {string}  string  "Bad request"
+// @Router       /v1/dids/{method}/{id}/display [get]
+func (dr DIDRouter) GetDIDDisplay(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	method := framework.GetParam(ctx, MethodParam)
+	if method == nil {
+		errMsg := "get DID display request missing method parameter"
+		logrus.Error(errMsg)
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+	id := framework.GetParam(ctx, IDParam)
+	if id == nil {
+		errMsg := fmt.Sprintf("get DID display request missing id parameter for method: %s", *method)
+		logrus.Error(errMsg)
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	gotDisplay, err := dr.serviceFor(ctx).GetDIDDisplay(did.GetDIDDisplayRequest{Method: did.Method(*method), ID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get display for DID<%s> with method: %s", *id, *method)
+		logrus.WithError(err).Error(errMsg)
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := GetDIDDisplayResponse{Display: gotDisplay.Display}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
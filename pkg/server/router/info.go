@@ -0,0 +1,74 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	svcframework "github.com/tbd54566975/ssi-service/pkg/service/framework"
+)
+
+type GetInfoResponse struct {
+	Version  string        `json:"version,omitempty"`
+	Commit   string        `json:"commit,omitempty"`
+	BaseURL  string        `json:"baseUrl,omitempty"`
+	Services []ServiceInfo `json:"services"`
+}
+
+type ServiceInfo struct {
+	Type svcframework.Type `json:"type"`
+	// Whether the service is ready, or why it is unavailable if it failed to initialize
+	Status       svcframework.Status    `json:"status"`
+	Capabilities map[string]interface{} `json:"capabilities,omitempty"`
+}
+
+// Info returns a handler describing the deployment. The response is built once, from the services which were
+// instantiated, so it always reflects what the server is actually running.
+func Info(cfg config.SSIServiceConfig, services []svcframework.Service) framework.Handler {
+	response := GetInfoResponse{
+		Version:  cfg.Version.SVN,
+		Commit:   buildCommit(),
+		BaseURL:  cfg.Server.BaseURL,
+		Services: make([]ServiceInfo, 0, len(services)),
+	}
+	for _, s := range services {
+		serviceInfo := ServiceInfo{Type: s.Type(), Status: s.Status()}
+		if describer, ok := s.(svcframework.Describer); ok {
+			serviceInfo.Capabilities = describer.Describe()
+		}
+		response.Services = append(response.Services, serviceInfo)
+	}
+	return info{response: response}.info
+}
+
+type info struct {
+	response GetInfoResponse
+}
+
+// Info godoc
+// @Summary      Service Info
+// @Description  Describes the service's version and the capabilities of each enabled service
+// @Tags         Info
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  GetInfoResponse
+// @Router       /v1/info [get]
+func (i info) info(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	return framework.Respond(ctx, w, i.response, http.StatusOK)
+}
+
+// buildCommit returns the VCS revision the binary was built from, if known
+func buildCommit() string {
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range buildInfo.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
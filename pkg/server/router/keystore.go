@@ -30,6 +30,11 @@ func NewKeyStoreRouter(s svcframework.Service) (*KeyStoreRouter, error) {
 	return &KeyStoreRouter{service: keyStoreService}, nil
 }
 
+// serviceFor gets the key store service, logging with the ID of the request in the context
+func (ksr KeyStoreRouter) serviceFor(ctx context.Context) *keystore.Service {
+	return ksr.service.WithLogger(framework.RequestLogger(ctx))
+}
+
 type StoreKeyRequest struct {
 	ID               string         `json:"id" validate:"required"`
 	Type             crypto.KeyType `json:"type,omitempty" validate:"required"`
@@ -59,6 +64,7 @@ func (sk StoreKeyRequest) ToServiceRequest() (*keystore.StoreKeyRequest, error)
 // @Param        request  body      StoreKeyRequest  true  "request body"
 // @Success      201
 // @Failure      400      {string}  string  "Bad request"
+// @Failure      409      {string}  string  "A key with the ID already exists"
 // @Failure      500      {string}  string  "Internal server error"
 // @Router       /v1/keys [put]
 func (ksr *KeyStoreRouter) StoreKey(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -76,9 +82,12 @@ func (ksr *KeyStoreRouter) StoreKey(ctx context.Context, w http.ResponseWriter,
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
 	}
 
-	if err := ksr.service.StoreKey(*req); err != nil {
+	if err := ksr.serviceFor(ctx).StoreKey(*req); err != nil {
 		errMsg := fmt.Sprintf("could not store key: %s, %s", request.ID, err.Error())
 		logrus.WithError(err).Error(errMsg)
+		if conflictErr := framework.NewConflictError(w, r, err); conflictErr != nil {
+			return conflictErr
+		}
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
 	}
 
@@ -110,7 +119,7 @@ func (ksr *KeyStoreRouter) GetKeyDetails(ctx context.Context, w http.ResponseWri
 		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
 	}
 
-	gotKeyDetails, err := ksr.service.GetKeyDetails(keystore.GetKeyDetailsRequest{ID: *id})
+	gotKeyDetails, err := ksr.serviceFor(ctx).GetKeyDetails(keystore.GetKeyDetailsRequest{ID: *id})
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get key details for id: %s", *id)
 		logrus.WithError(err).Error(errMsg)
@@ -78,6 +78,15 @@ func TestKeyStoreRouter(t *testing.T) {
 		})
 		assert.NoError(tt, err)
 
+		// keys are not overwritten
+		err = keyStoreService.StoreKey(keystore.StoreKeyRequest{
+			ID:         keyID,
+			Type:       crypto.Ed25519,
+			Controller: "did:test:someone-else",
+			Key:        privKeyBytes,
+		})
+		assert.ErrorAs(tt, err, &framework.AlreadyExistsError{})
+
 		// get a key that doesn't exist
 		gotDetails, err := keyStoreService.GetKeyDetails(keystore.GetKeyDetailsRequest{ID: "bad"})
 		assert.Error(tt, err)
@@ -8,6 +8,7 @@ import (
 	schemalib "github.com/TBD54566975/ssi-sdk/credential/schema"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/tbd54566975/ssi-service/internal/util"
 	"github.com/tbd54566975/ssi-service/pkg/server/framework"
 	svcframework "github.com/tbd54566975/ssi-service/pkg/service/framework"
 	"github.com/tbd54566975/ssi-service/pkg/service/schema"
@@ -30,15 +31,24 @@ func NewSchemaRouter(s svcframework.Service) (*SchemaRouter, error) {
 	}, nil
 }
 
+// serviceFor gets the schema service, logging with the ID of the request in the context
+func (sr SchemaRouter) serviceFor(ctx context.Context) *schema.Service {
+	return sr.service.WithLogger(framework.RequestLogger(ctx))
+}
+
 type CreateSchemaRequest struct {
 	Author string               `json:"author" validate:"required"`
 	Name   string               `json:"name" validate:"required"`
 	Schema schemalib.JSONSchema `json:"schema" validate:"required"`
+	// If set, overrides the service's default for whether credential data may contain properties the schema
+	// does not define, regardless of the schema's own additionalProperties
+	StrictValidation *bool `json:"strictValidation,omitempty"`
 }
 
 type CreateSchemaResponse struct {
-	ID     string                 `json:"id"`
-	Schema schemalib.VCJSONSchema `json:"schema"`
+	ID               string                 `json:"id"`
+	Schema           schemalib.VCJSONSchema `json:"schema"`
+	StrictValidation *bool                  `json:"strictValidation,omitempty"`
 }
 
 // CreateSchema godoc
@@ -60,15 +70,24 @@ func (sr SchemaRouter) CreateSchema(ctx context.Context, w http.ResponseWriter,
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
 	}
 
-	req := schema.CreateSchemaRequest{Author: request.Author, Name: request.Name, Schema: request.Schema}
-	createSchemaResponse, err := sr.service.CreateSchema(req)
+	req := schema.CreateSchemaRequest{
+		Author:           request.Author,
+		Name:             request.Name,
+		Schema:           request.Schema,
+		StrictValidation: request.StrictValidation,
+	}
+	createSchemaResponse, err := sr.serviceFor(ctx).CreateSchema(req)
 	if err != nil {
 		errMsg := fmt.Sprintf("could not create schema with authoring DID: %s", request.Author)
 		logrus.WithError(err).Error(errMsg)
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
 	}
 
-	resp := CreateSchemaResponse{ID: createSchemaResponse.ID, Schema: createSchemaResponse.Schema}
+	resp := CreateSchemaResponse{
+		ID:               createSchemaResponse.ID,
+		Schema:           createSchemaResponse.Schema,
+		StrictValidation: createSchemaResponse.StrictValidation,
+	}
 	return framework.Respond(ctx, w, resp, http.StatusCreated)
 }
 
@@ -78,20 +97,47 @@ type GetSchemasResponse struct {
 
 // GetSchemas godoc
 // @Summary      Get Schemas
-// @Description  Get schemas
+// @Description  Get schemas. Schemas are sorted by name, then by ID, unless another sort is given.
 // @Tags         SchemaAPI
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  GetSchemasResponse
-// @Failure      500  {string}  string  "Internal server error"
+// @Param        sort       query     string  false  "string sort, one of 'created asc' or 'created desc', where created is the authored date"
+// @Param        pageSize   query     int     false  "most schemas to return, up to 100, returning a page of schemas"
+// @Param        pageToken  query     string  false  "token for the next page, from a previous page"
+// @Success      200   {object}  GetSchemasResponse
+// @Success      200   {object}  framework.Page
+// @Failure      400   {string}  string  "Bad request"
+// @Failure      500   {string}  string  "Internal server error"
 // @Router       /v1/schemas [get]
 func (sr SchemaRouter) GetSchemas(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	gotSchemas, err := sr.service.GetSchemas()
+	var sort string
+	if sortValue := framework.GetQueryValue(r, SortParam); sortValue != nil {
+		if !svcframework.IsValidSort(*sortValue) {
+			errMsg := fmt.Sprintf("invalid sort<%s>, must be one of: %s, %s", util.SanitizeLog(*sortValue), svcframework.SortCreatedAsc, svcframework.SortCreatedDesc)
+			return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+		}
+		sort = *sortValue
+	}
+	page, err := framework.GetPageRequest(r)
+	if err != nil {
+		return framework.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	gotSchemas, err := sr.serviceFor(ctx).GetSchemas(schema.GetSchemasRequest{Sort: sort})
 	if err != nil {
 		errMsg := "could not get schemas"
 		logrus.WithError(err).Error(errMsg)
 		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
 	}
+	if page != nil {
+		schemasPage, err := framework.NewPage(gotSchemas.Schemas, *page)
+		if err != nil {
+			errMsg := "could not paginate schemas"
+			logrus.WithError(err).Error(errMsg)
+			return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusInternalServerError)
+		}
+		return framework.Respond(ctx, w, schemasPage, http.StatusOK)
+	}
 	resp := GetSchemasResponse{Schemas: gotSchemas.Schemas}
 	return framework.Respond(ctx, w, resp, http.StatusOK)
 }
@@ -119,7 +165,7 @@ func (sr SchemaRouter) GetSchemaByID(ctx context.Context, w http.ResponseWriter,
 	}
 
 	// TODO(gabe) differentiate between internal errors and not found schemas
-	gotSchema, err := sr.service.GetSchemaByID(schema.GetSchemaByIDRequest{ID: *id})
+	gotSchema, err := sr.serviceFor(ctx).GetSchemaByID(schema.GetSchemaByIDRequest{ID: *id})
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get schema with id: %s", *id)
 		logrus.WithError(err).Error(errMsg)
@@ -129,3 +175,36 @@ func (sr SchemaRouter) GetSchemaByID(ctx context.Context, w http.ResponseWriter,
 	resp := GetSchemaResponse{Schema: gotSchema.Schema}
 	return framework.Respond(ctx, w, resp, http.StatusOK)
 }
+
+type GetSchemaFormResponse struct {
+	Fields []schema.FormField `json:"fields"`
+}
+
+// GetSchemaForm godoc
+// @Summary      Get Schema Form
+// @Description  Get a flat list of form field descriptors for the data of a credential against a schema
+// @Tags         SchemaAPI
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  GetSchemaFormResponse
+// @Failure      400  {string}  string  "Bad request"
+// @Router       /v1/schemas/{id}/form [get]
+func (sr SchemaRouter) GetSchemaForm(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := framework.GetParam(ctx, IDParam)
+	if id == nil {
+		errMsg := "cannot get schema form without ID parameter"
+		logrus.Error(errMsg)
+		return framework.NewRequestErrorMsg(errMsg, http.StatusBadRequest)
+	}
+
+	gotForm, err := sr.serviceFor(ctx).GetSchemaForm(schema.GetSchemaFormRequest{ID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get form for schema with id: %s", *id)
+		logrus.WithError(err).Error(errMsg)
+		return framework.NewRequestError(errors.Wrap(err, errMsg), http.StatusBadRequest)
+	}
+
+	resp := GetSchemaFormResponse{Fields: gotForm.Fields}
+	return framework.Respond(ctx, w, resp, http.StatusOK)
+}
@@ -46,7 +46,7 @@ func TestSchemaRouter(t *testing.T) {
 		assert.Equal(tt, framework.StatusReady, schemaService.Status().Status)
 
 		// get all schemas (none)
-		gotSchemas, err := schemaService.GetSchemas()
+		gotSchemas, err := schemaService.GetSchemas(schema.GetSchemasRequest{})
 		assert.NoError(tt, err)
 		assert.Empty(tt, gotSchemas)
 		assert.Equal(tt, 0, len(gotSchemas.Schemas))
@@ -81,7 +81,7 @@ func TestSchemaRouter(t *testing.T) {
 		assert.EqualValues(tt, createdSchema.Schema, gotSchema.Schema)
 
 		// get all schemas, expect one
-		gotSchemas, err = schemaService.GetSchemas()
+		gotSchemas, err = schemaService.GetSchemas(schema.GetSchemasRequest{})
 		assert.NoError(tt, err)
 		assert.NotEmpty(tt, gotSchemas.Schemas)
 		assert.Len(tt, gotSchemas.Schemas, 1)
@@ -95,7 +95,7 @@ func TestSchemaRouter(t *testing.T) {
 		assert.Equal(tt, "simple schema 2", createdSchema.Schema.Name)
 
 		// get all schemas, expect two
-		gotSchemas, err = schemaService.GetSchemas()
+		gotSchemas, err = schemaService.GetSchemas(schema.GetSchemasRequest{})
 		assert.NoError(tt, err)
 		assert.NotEmpty(tt, gotSchemas.Schemas)
 		assert.Len(tt, gotSchemas.Schemas, 2)
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+)
+
+// Unavailable returns a handler for the routes of a service which failed to initialize, answering every request with
+// a 503 giving the reason
+func Unavailable(reason string) framework.Handler {
+	return func(_ context.Context, _ http.ResponseWriter, _ *http.Request) error {
+		return framework.NewRequestErrorMsg("service unavailable: "+reason, http.StatusServiceUnavailable)
+	}
+}
@@ -27,6 +27,7 @@ const (
 	SchemasPrefix     = "/schemas"
 	CredentialsPrefix = "/credentials"
 	KeyStorePrefix    = "/keys"
+	InfoPrefix        = "/info"
 )
 
 // SSIServer exposes all dependencies needed to run a http server and all its services
@@ -40,7 +41,7 @@ type SSIServer struct {
 func NewSSIServer(shutdown chan os.Signal, config config.SSIServiceConfig) (*SSIServer, error) {
 	// creates an HTTP server from the framework, and wrap it to extend it for the SSIS
 	middlewares := []framework.Middleware{
-		middleware.Logger(),
+		middleware.Logger(config.Server),
 		middleware.Errors(),
 		middleware.Metrics(),
 		middleware.Panics(),
@@ -57,6 +58,7 @@ func NewSSIServer(shutdown chan os.Signal, config config.SSIServiceConfig) (*SSI
 	// service-level routers
 	httpServer.Handle(http.MethodGet, HealthPrefix, router.Health)
 	httpServer.Handle(http.MethodGet, ReadinessPrefix, router.Readiness(services))
+	httpServer.Handle(http.MethodGet, V1Prefix+InfoPrefix, router.Info(config, services))
 
 	// create the server instance to be returned
 	server := SSIServer{
@@ -80,22 +82,50 @@ func NewSSIServer(shutdown chan os.Signal, config config.SSIServiceConfig) (*SSI
 
 // instantiateRouter registers the HTTP router for a service with the HTTP server
 // NOTE: all service API router must be registered here
-func (s *SSIServer) instantiateRouter(service svcframework.Service) error {
-	serviceType := service.Type()
+func (s *SSIServer) instantiateRouter(svc svcframework.Service) error {
+	if unavailable, ok := svc.(service.UnavailableService); ok {
+		return s.UnavailableAPI(unavailable)
+	}
+	serviceType := svc.Type()
 	switch serviceType {
 	case svcframework.DID:
-		return s.DecentralizedIdentityAPI(service)
+		return s.DecentralizedIdentityAPI(svc)
 	case svcframework.Schema:
-		return s.SchemaAPI(service)
+		return s.SchemaAPI(svc)
 	case svcframework.Credential:
-		return s.CredentialAPI(service)
+		return s.CredentialAPI(svc)
 	case svcframework.KeyStore:
-		return s.KeyStoreAPI(service)
+		return s.KeyStoreAPI(svc)
 	default:
 		return fmt.Errorf("could not instantiate API for service: %s", serviceType)
 	}
 }
 
+// servicePrefixes are the paths each service's routes are registered under
+var servicePrefixes = map[svcframework.Type]string{
+	svcframework.DID:        DIDsPrefix,
+	svcframework.Schema:     SchemasPrefix,
+	svcframework.Credential: CredentialsPrefix,
+	svcframework.KeyStore:   KeyStorePrefix,
+}
+
+// UnavailableAPI registers routes for a service which failed to initialize, so that requests to any of its paths
+// get a 503 explaining why, rather than a 404 or a failure on first use
+func (s *SSIServer) UnavailableAPI(unavailable service.UnavailableService) error {
+	prefix, ok := servicePrefixes[unavailable.Type()]
+	if !ok {
+		return fmt.Errorf("could not instantiate API for unavailable service: %s", unavailable.Type())
+	}
+
+	handlerPath := V1Prefix + prefix
+	handler := router.Unavailable(unavailable.Reason())
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete} {
+		s.Handle(method, handlerPath, handler)
+		s.Handle(method, path.Join(handlerPath, "/*path"), handler)
+	}
+	return nil
+}
+
 // DecentralizedIdentityAPI registers all HTTP router for the DID Service
 func (s *SSIServer) DecentralizedIdentityAPI(service svcframework.Service) (err error) {
 	didRouter, err := router.NewDIDRouter(service)
@@ -108,6 +138,8 @@ func (s *SSIServer) DecentralizedIdentityAPI(service svcframework.Service) (err
 	s.Handle(http.MethodGet, handlerPath, didRouter.GetDIDMethods)
 	s.Handle(http.MethodPut, path.Join(handlerPath, "/:method"), didRouter.CreateDIDByMethod)
 	s.Handle(http.MethodGet, path.Join(handlerPath, "/:method/:id"), didRouter.GetDIDByMethod)
+	s.Handle(http.MethodPut, path.Join(handlerPath, "/:method/:id/display"), didRouter.SetDIDDisplay)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/:method/:id/display"), didRouter.GetDIDDisplay)
 	return
 }
 
@@ -123,6 +155,7 @@ func (s *SSIServer) SchemaAPI(service svcframework.Service) (err error) {
 	s.Handle(http.MethodPut, handlerPath, schemaRouter.CreateSchema)
 	s.Handle(http.MethodGet, handlerPath, schemaRouter.GetSchemas)
 	s.Handle(http.MethodGet, path.Join(handlerPath, "/:id"), schemaRouter.GetSchemaByID)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/:id/form"), schemaRouter.GetSchemaForm)
 	return
 }
 
@@ -135,8 +168,25 @@ func (s *SSIServer) CredentialAPI(service svcframework.Service) (err error) {
 	handlerPath := V1Prefix + CredentialsPrefix
 
 	s.Handle(http.MethodPut, handlerPath, credRouter.CreateCredential)
+	s.Handle(http.MethodPut, path.Join(handlerPath, "/batch"), credRouter.BatchCreateCredentials)
+	s.Handle(http.MethodPost, path.Join(handlerPath, "/validate"), credRouter.ValidateCredential)
+	s.Handle(http.MethodPost, path.Join(handlerPath, "/expand"), credRouter.ExpandCredential)
+	s.Handle(http.MethodPost, path.Join(handlerPath, "/match"), credRouter.MatchCredentials)
+	s.Handle(http.MethodPost, path.Join(handlerPath, "/from-csv"), credRouter.CreateCredentialsFromCSV)
+	s.Handle(http.MethodPost, path.Join(handlerPath, "/batch-get"), credRouter.BatchGetCredentials)
+	s.Handle(http.MethodPut, path.Join(handlerPath, "/freeze"), credRouter.SetIssuanceFreeze)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/freeze"), credRouter.GetIssuanceFreeze)
+	s.Handle(http.MethodPut, path.Join(handlerPath, "/aliases"), credRouter.SetSubjectAlias)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/aliases"), credRouter.GetSubjectAliases)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/aliases/:alias"), credRouter.GetSubjectAlias)
+	s.Handle(http.MethodDelete, path.Join(handlerPath, "/aliases/:alias"), credRouter.DeleteSubjectAlias)
 	s.Handle(http.MethodGet, handlerPath, credRouter.GetCredentials)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/expiring"), credRouter.GetExpiringCredentials)
 	s.Handle(http.MethodGet, path.Join(handlerPath, "/:id"), credRouter.GetCredential)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/:id/status"), credRouter.GetCredentialStatus)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/:id/bundle"), credRouter.GetCredentialBundle)
+	s.Handle(http.MethodGet, path.Join(handlerPath, "/issuers/:did/key-health"), credRouter.GetIssuerKeyHealth)
+	s.Handle(http.MethodDelete, handlerPath, credRouter.DeleteCredentials)
 	s.Handle(http.MethodDelete, path.Join(handlerPath, "/:id"), credRouter.DeleteCredential)
 	return
 }
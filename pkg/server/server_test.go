@@ -3,20 +3,28 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	schemalib "github.com/TBD54566975/ssi-sdk/credential/schema"
 	"github.com/TBD54566975/ssi-sdk/crypto"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
 	"github.com/dimfeld/httptreemux/v5"
 	"github.com/goccy/go-json"
 	"github.com/google/uuid"
 	"github.com/mr-tron/base58"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -89,6 +97,106 @@ func TestReadinessAPI(t *testing.T) {
 	assert.Len(t, resp.ServiceStatuses, 0)
 }
 
+func TestInfoAPI(t *testing.T) {
+	// remove the db file after the test
+	t.Cleanup(func() {
+		_ = os.Remove(storage.DBFile)
+	})
+
+	shutdown := make(chan os.Signal, 1)
+	serviceConfig, err := config.LoadConfig("")
+	assert.NoError(t, err)
+	serviceConfig.Version.SVN = "1.2.3"
+	serviceConfig.Server.BaseURL = "https://ssi-service.com"
+
+	server, err := NewSSIServer(shutdown, *serviceConfig)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, server)
+
+	req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/info", nil)
+	w := httptest.NewRecorder()
+
+	handler := router.Info(*serviceConfig, server.GetServices())
+	err = handler(newRequestContext(), w, req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var resp router.GetInfoResponse
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1.2.3", resp.Version)
+	assert.Equal(t, "https://ssi-service.com", resp.BaseURL)
+	assert.Len(t, resp.Services, len(server.GetServices()))
+
+	services := make(map[svcframework.Type]router.ServiceInfo)
+	for _, s := range resp.Services {
+		services[s.Type] = s
+	}
+	assert.Contains(t, services, svcframework.Schema)
+	assert.Equal(t, []interface{}{"key"}, services[svcframework.DID].Capabilities["methods"])
+	assert.NotEmpty(t, services[svcframework.DID].Capabilities["keyTypes"])
+	assert.Equal(t, []interface{}{credential.JSONLDFormat}, services[svcframework.Credential].Capabilities["formats"])
+}
+
+func TestUnavailableServiceAPI(t *testing.T) {
+	// remove the db file after the test
+	t.Cleanup(func() {
+		_ = os.Remove(storage.DBFile)
+	})
+
+	shutdown := make(chan os.Signal, 1)
+	serviceConfig, err := config.LoadConfig("")
+	assert.NoError(t, err)
+	serviceConfig.Services.CredentialConfig.TimestampPrecision = "nanoseconds"
+
+	// the other services are still served
+	server, err := NewSSIServer(shutdown, *serviceConfig)
+	require.NoError(t, err)
+	require.NotEmpty(t, server)
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	// every credential route explains why it is unavailable
+	for _, target := range []string{"/v1/credentials", "/v1/credentials/some-id/status"} {
+		w := serve(http.MethodGet, target)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, target)
+
+		var resp framework.ErrorResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Contains(t, resp.Error, "service unavailable: credential failed to initialize: ")
+		assert.Contains(t, resp.Error, "invalid timestamp precision<nanoseconds>")
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, serve(http.MethodPut, "/v1/credentials").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/v1/schemas").Code)
+
+	// readiness and info both report the degraded service
+	var readiness router.GetReadinessResponse
+	err = json.NewDecoder(serve(http.MethodGet, "/readiness").Body).Decode(&readiness)
+	assert.NoError(t, err)
+	assert.Equal(t, svcframework.StatusNotReady, readiness.Status.Status)
+	assert.Equal(t, svcframework.StatusNotReady, readiness.ServiceStatuses[svcframework.Credential].Status)
+	assert.Contains(t, readiness.ServiceStatuses[svcframework.Credential].Message, "credential failed to initialize")
+	assert.Equal(t, svcframework.StatusReady, readiness.ServiceStatuses[svcframework.Schema].Status)
+
+	var info router.GetInfoResponse
+	err = json.NewDecoder(serve(http.MethodGet, "/v1/info").Body).Decode(&info)
+	assert.NoError(t, err)
+	for _, s := range info.Services {
+		if s.Type == svcframework.Credential {
+			assert.Equal(t, svcframework.StatusNotReady, s.Status.Status)
+			assert.Empty(t, s.Capabilities)
+		} else {
+			assert.Equal(t, svcframework.StatusReady, s.Status.Status, s.Type)
+		}
+	}
+}
+
 func TestDIDAPI(t *testing.T) {
 	t.Run("Test Get DID Methods", func(tt *testing.T) {
 		bolt, err := storage.NewBoltDB()
@@ -231,6 +339,97 @@ func TestDIDAPI(t *testing.T) {
 		assert.NoError(tt, err)
 		assert.Equal(tt, createdID, resp.DID.ID)
 	})
+
+	t.Run("Test DID Display", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		didService := newDIDService(tt, bolt)
+
+		// store a DID
+		createDIDRequest := router.CreateDIDByMethodRequest{KeyType: crypto.Ed25519}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/key", newRequestValue(tt, createDIDRequest))
+		w := httptest.NewRecorder()
+		err = didService.CreateDIDByMethod(newRequestContextWithParams(map[string]string{"method": "key"}), w, req)
+		assert.NoError(tt, err)
+
+		var createdDID router.CreateDIDByMethodResponse
+		err = json.NewDecoder(w.Body).Decode(&createdDID)
+		assert.NoError(tt, err)
+
+		displayPath := fmt.Sprintf("https://ssi-service.com/v1/dids/key/%s/display", createdDID.DID.ID)
+		params := map[string]string{"method": "key", "id": createdDID.DID.ID}
+
+		// no display set
+		req = httptest.NewRequest(http.MethodGet, displayPath, nil)
+		w = httptest.NewRecorder()
+		err = didService.GetDIDDisplay(newRequestContextWithParams(params), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.GetDIDDisplayResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Empty(tt, resp.Display)
+
+		// invalid colors and logos
+		badRequests := map[string]router.SetDIDDisplayRequest{
+			"background color<blue> must be of the form #RRGGBB": {BackgroundColor: "blue"},
+			"logo must be an http(s) URL or a data URI":          {Logo: "ftp://example.com/logo.png"},
+			"unsupported logo media type<image/tiff>":            {Logo: "data:image/tiff;base64,AAAA"},
+			"larger than the maximum of 32768":                   {Logo: "data:image/png;base64," + base64.StdEncoding.EncodeToString(make([]byte, did.MaxInlineLogoBytes+1))},
+		}
+		for expectedErr, badRequest := range badRequests {
+			req = httptest.NewRequest(http.MethodPut, displayPath, newRequestValue(tt, badRequest))
+			err = didService.SetDIDDisplay(newRequestContextWithParams(params), httptest.NewRecorder(), req)
+			assert.Error(tt, err)
+			assert.Contains(tt, err.Error(), expectedErr)
+		}
+
+		// unknown DID
+		req = httptest.NewRequest(http.MethodPut, displayPath, newRequestValue(tt, router.SetDIDDisplayRequest{Name: "Acme"}))
+		err = didService.SetDIDDisplay(newRequestContextWithParams(map[string]string{"method": "key", "id": "did:key:unknown"}), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "could not set display for DID<did:key:unknown>")
+
+		// set it and get it back
+		setRequest := router.SetDIDDisplayRequest{
+			Name:            "Acme Bank",
+			Logo:            "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("not really a png")),
+			BackgroundColor: "#12ABef",
+			TextColor:       "#FFFFFF",
+		}
+		req = httptest.NewRequest(http.MethodPut, displayPath, newRequestValue(tt, setRequest))
+		w = httptest.NewRecorder()
+		err = didService.SetDIDDisplay(newRequestContextWithParams(params), w, req)
+		assert.NoError(tt, err)
+
+		req = httptest.NewRequest(http.MethodGet, displayPath, nil)
+		w = httptest.NewRecorder()
+		err = didService.GetDIDDisplay(newRequestContextWithParams(params), w, req)
+		assert.NoError(tt, err)
+
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "Acme Bank", resp.Display.Name)
+		assert.Equal(tt, setRequest.Logo, resp.Display.Logo)
+		assert.Equal(tt, "#12ABef", resp.Display.BackgroundColor)
+
+		// the DID document itself is unchanged
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/dids/key/%s", createdDID.DID.ID), nil)
+		w = httptest.NewRecorder()
+		err = didService.GetDIDByMethod(newRequestContextWithParams(params), w, req)
+		assert.NoError(tt, err)
+
+		var getDIDResp router.GetDIDByMethodResponse
+		err = json.NewDecoder(w.Body).Decode(&getDIDResp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, createdDID.DID.ID, getDIDResp.DID.ID)
+	})
 }
 
 func newDIDService(t *testing.T, bolt *storage.BoltDB) *router.DIDRouter {
@@ -388,6 +587,190 @@ func TestSchemaAPI(t *testing.T) {
 		assert.NoError(tt, err)
 		assert.Len(tt, getSchemasResp.Schemas, 1)
 	})
+
+	t.Run("Test Get Schemas Sorted", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		schemaService := newSchemaService(tt, bolt)
+
+		for _, name := range []string{"beta", "alpha", "gamma"} {
+			schemaRequest := router.CreateSchemaRequest{Author: "did:test", Name: name, Schema: map[string]interface{}{"type": "object"}}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/schemas", newRequestValue(tt, schemaRequest))
+			err = schemaService.CreateSchema(newRequestContext(), httptest.NewRecorder(), req)
+			require.NoError(tt, err)
+		}
+
+		// schemas are listed by name by default
+		req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/schemas", nil)
+		w := httptest.NewRecorder()
+		err = schemaService.GetSchemas(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.GetSchemasResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		require.Len(tt, resp.Schemas, 3)
+		assert.Equal(tt, "alpha", resp.Schemas[0].Name)
+		assert.Equal(tt, "beta", resp.Schemas[1].Name)
+		assert.Equal(tt, "gamma", resp.Schemas[2].Name)
+
+		// unknown sort
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/schemas?sort=name", nil)
+		err = schemaService.GetSchemas(newRequestContext(), httptest.NewRecorder(), req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid sort<name>, must be one of: created asc, created desc")
+	})
+
+	t.Run("Test Get Schemas Paginated", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		schemaService := newSchemaService(tt, bolt)
+
+		for _, name := range []string{"beta", "alpha", "gamma"} {
+			schemaRequest := router.CreateSchemaRequest{Author: "did:test", Name: name, Schema: map[string]interface{}{"type": "object"}}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/schemas", newRequestValue(tt, schemaRequest))
+			err = schemaService.CreateSchema(newRequestContext(), httptest.NewRecorder(), req)
+			require.NoError(tt, err)
+		}
+
+		getPage := func(query string) (*framework.Page, []schemalib.VCJSONSchema) {
+			req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/schemas?"+query, nil)
+			w := httptest.NewRecorder()
+			err := schemaService.GetSchemas(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var schemas []schemalib.VCJSONSchema
+			page := framework.Page{Items: &schemas}
+			err = json.NewDecoder(w.Body).Decode(&page)
+			require.NoError(tt, err)
+			return &page, schemas
+		}
+
+		page, schemas := getPage("pageSize=2")
+		assert.Equal(tt, 3, page.TotalCount)
+		require.Len(tt, schemas, 2)
+		assert.Equal(tt, "alpha", schemas[0].Name)
+		assert.Equal(tt, "beta", schemas[1].Name)
+		require.NotEmpty(tt, page.NextPageToken)
+
+		page, schemas = getPage("pageSize=2&pageToken=" + page.NextPageToken)
+		assert.Equal(tt, 3, page.TotalCount)
+		require.Len(tt, schemas, 1)
+		assert.Equal(tt, "gamma", schemas[0].Name)
+		assert.Empty(tt, page.NextPageToken)
+
+		// bad page requests
+		for _, query := range []string{"pageSize=0", "pageSize=101", "pageToken=bad"} {
+			req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/schemas?"+query, nil)
+			err = schemaService.GetSchemas(newRequestContext(), httptest.NewRecorder(), req)
+			assert.Error(tt, err, query)
+		}
+	})
+
+	t.Run("Test Get Schema Form", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		schemaService := newSchemaService(tt, bolt)
+
+		createSchema := func(jsonSchema map[string]interface{}) string {
+			schemaRequest := router.CreateSchemaRequest{Author: "did:test", Name: "test schema", Schema: jsonSchema}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/schemas", newRequestValue(tt, schemaRequest))
+			w := httptest.NewRecorder()
+			err := schemaService.CreateSchema(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var resp router.CreateSchemaResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			require.NoError(tt, err)
+			return resp.ID
+		}
+		getForm := func(id string) (*router.GetSchemaFormResponse, error) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/schemas/%s/form", id), nil)
+			w := httptest.NewRecorder()
+			if err := schemaService.GetSchemaForm(newRequestContextWithParams(map[string]string{"id": id}), w, req); err != nil {
+				return nil, err
+			}
+			var resp router.GetSchemaFormResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			return &resp, err
+		}
+
+		// unknown schema
+		_, err = getForm("bad")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "could not get form for schema with id: bad")
+
+		// nested objects through a $ref, and properties composed with allOf
+		membershipSchema := map[string]interface{}{
+			"type": "object",
+			"definitions": map[string]interface{}{
+				"address": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"city":     map[string]interface{}{"type": "string"},
+						"zip/code": map[string]interface{}{"type": "string"},
+					},
+					"required": []interface{}{"city"},
+				},
+			},
+			"allOf": []interface{}{
+				map[string]interface{}{
+					"properties": map[string]interface{}{
+						"email": map[string]interface{}{"type": "string", "format": "email", "description": "Contact email"},
+					},
+					"required": []interface{}{"email"},
+				},
+			},
+			"properties": map[string]interface{}{
+				"address":   map[string]interface{}{"$ref": "#/definitions/address"},
+				"birthDate": map[string]interface{}{"type": []interface{}{"null", "string"}, "format": "date"},
+				"level":     map[string]interface{}{"type": "string", "enum": []interface{}{"gold", "silver"}},
+			},
+			"required": []interface{}{"address", "level"},
+		}
+		form, err := getForm(createSchema(membershipSchema))
+		assert.NoError(tt, err)
+		assert.Equal(tt, []schema.FormField{
+			{Name: "city", Pointer: "/address/city", Type: "string", Required: true},
+			{Name: "zip/code", Pointer: "/address/zip~1code", Type: "string"},
+			{Name: "birthDate", Pointer: "/birthDate", Type: "string", Format: "date"},
+			{Name: "email", Pointer: "/email", Type: "string", Required: true, Description: "Contact email", Format: "email"},
+			{Name: "level", Pointer: "/level", Type: "string", Required: true, Enum: []interface{}{"gold", "silver"}},
+		}, form.Fields)
+
+		// recursive schemas cannot be flattened
+		treeSchema := map[string]interface{}{
+			"type": "object",
+			"definitions": map[string]interface{}{
+				"node": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"child": map[string]interface{}{"$ref": "#/definitions/node"}},
+				},
+			},
+			"properties": map[string]interface{}{"root": map[string]interface{}{"$ref": "#/definitions/node"}},
+		}
+		_, err = getForm(createSchema(treeSchema))
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "circular $ref: #/definitions/node")
+	})
 }
 
 func newSchemaService(t *testing.T, bolt *storage.BoltDB) *router.SchemaRouter {
@@ -453,6 +836,81 @@ func TestCredentialAPI(t *testing.T) {
 
 		assert.NotEmpty(tt, resp.Credential)
 		assert.Equal(tt, resp.Credential.Issuer, "did:abc:123")
+		assert.Nil(tt, resp.Timings)
+
+		// timings are only included when requested
+		requestValue = newRequestValue(tt, createCredRequest)
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials?timings=true", requestValue)
+		w = httptest.NewRecorder()
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var timedResp router.CreateCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&timedResp)
+		assert.NoError(tt, err)
+		require.NotNil(tt, timedResp.Timings)
+		for _, timing := range []string{timedResp.Timings.Build, timedResp.Timings.Validate, timedResp.Timings.Store} {
+			_, err = time.ParseDuration(timing)
+			assert.NoError(tt, err)
+		}
+
+		// malformed requests are rejected without echoing their content
+		for _, body := range []string{`{"issuer": "did:abc:123", "data": "123-45-6789"}`, `{"issuer": "123-45-6789"`} {
+			req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", bytes.NewReader([]byte(body)))
+			err = credService.CreateCredential(newRequestContext(), w, req)
+			assert.Error(tt, err)
+			assert.NotContains(tt, err.Error(), "123-45-6789")
+		}
+	})
+
+	t.Run("Test Create Credential Missing Required Claims", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		serviceConfig := config.CredentialServiceConfig{RequiredClaims: []string{"jurisdiction", "address.country"}}
+		credentialService, err := credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService, err := router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+
+		// missing one required claim, the other is empty
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:  "did:abc:123",
+			Subject: "did:abc:456",
+			Data: map[string]interface{}{
+				"firstName":    "Jack",
+				"jurisdiction": "",
+			},
+		}
+		requestValue := newRequestValue(tt, createCredRequest)
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
+		w := httptest.NewRecorder()
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "credential data is missing required claim(s): jurisdiction, address.country")
+
+		var safeErr *framework.SafeError
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		assert.Len(tt, safeErr.Fields, 2)
+
+		// all required claims present
+		createCredRequest.Data = map[string]interface{}{
+			"firstName":    "Jack",
+			"jurisdiction": "US",
+			"address": map[string]interface{}{
+				"country": "US",
+			},
+		}
+		requestValue = newRequestValue(tt, createCredRequest)
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.NoError(tt, err)
 	})
 
 	t.Run("Test Get Credential By ID", func(tt *testing.T) {
@@ -514,9 +972,16 @@ func TestCredentialAPI(t *testing.T) {
 		assert.NoError(tt, err)
 		assert.NotEmpty(tt, getCredResp)
 		assert.Equal(tt, resp.Credential.ID, getCredResp.ID)
+
+		// a credential which does not exist is not found
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/missing", nil)
+		err = credService.GetCredential(newRequestContextWithParams(map[string]string{"id": "missing"}), w, req)
+		var safeErr *framework.SafeError
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusNotFound, safeErr.StatusCode)
 	})
 
-	t.Run("Test Get Credential By Schema", func(tt *testing.T) {
+	t.Run("Test Get Credential Content Negotiation", func(tt *testing.T) {
 		bolt, err := storage.NewBoltDB()
 
 		// remove the db file after the test
@@ -527,21 +992,15 @@ func TestCredentialAPI(t *testing.T) {
 
 		credService := newCredentialService(tt, bolt)
 
-		w := httptest.NewRecorder()
-
-		schemaID := "https://test-schema.com/name"
 		createCredRequest := router.CreateCredentialRequest{
 			Issuer:  "did:abc:123",
 			Subject: "did:abc:456",
-			Schema:  schemaID,
 			Data: map[string]interface{}{
 				"firstName": "Jack",
-				"lastName":  "Dorsey",
 			},
-			Expiry: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
 		}
-		requestValue := newRequestValue(tt, createCredRequest)
-		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+		w := httptest.NewRecorder()
 		err = credService.CreateCredential(newRequestContext(), w, req)
 		assert.NoError(tt, err)
 
@@ -549,23 +1008,59 @@ func TestCredentialAPI(t *testing.T) {
 		err = json.NewDecoder(w.Body).Decode(&resp)
 		assert.NoError(tt, err)
 
-		w.Flush()
+		getCredential := func(accept string) (*httptest.ResponseRecorder, error) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s", resp.Credential.ID), nil)
+			req.Header.Set("Accept", accept)
+			w := httptest.NewRecorder()
+			err := credService.GetCredential(newRequestContextWithParams(map[string]string{"id": resp.Credential.ID}), w, req)
+			return w, err
+		}
 
-		// get credential by schema
-		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credential?schema=%s", schemaID), nil)
-		err = credService.GetCredentials(newRequestContext(), w, req)
+		// JSON-LD is returned as the bare credential
+		w, err = getCredential("application/vc+ld+json")
 		assert.NoError(tt, err)
+		assert.Equal(tt, "application/vc+ld+json", w.Header().Get("Content-Type"))
 
-		var getCredsResp router.GetCredentialsResponse
-		err = json.NewDecoder(w.Body).Decode(&getCredsResp)
+		var gotCred credsdk.VerifiableCredential
+		err = json.NewDecoder(w.Body).Decode(&gotCred)
 		assert.NoError(tt, err)
-		assert.NotEmpty(tt, getCredsResp)
-		assert.Len(tt, getCredsResp.Credentials, 1)
-		assert.Equal(tt, resp.Credential.ID, getCredsResp.Credentials[0].ID)
-		assert.Equal(tt, resp.Credential.CredentialSchema.ID, getCredsResp.Credentials[0].CredentialSchema.ID)
+		assert.Equal(tt, resp.Credential.ID, gotCred.ID)
+
+		// as is plain JSON-LD
+		w, err = getCredential("application/ld+json")
+		assert.NoError(tt, err)
+		assert.Equal(tt, "application/ld+json", w.Header().Get("Content-Type"))
+
+		err = json.NewDecoder(w.Body).Decode(&gotCred)
+		assert.NoError(tt, err)
+		assert.Equal(tt, resp.Credential.ID, gotCred.ID)
+
+		// the client's preference is respected
+		w, err = getCredential("application/vc+ld+json;q=0.5, application/json")
+		assert.NoError(tt, err)
+		assert.Equal(tt, "application/json", w.Header().Get("Content-Type"))
+
+		var getCredResp router.GetCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&getCredResp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, resp.Credential.ID, getCredResp.ID)
+
+		// wildcards accept the default representation
+		w, err = getCredential("*/*")
+		assert.NoError(tt, err)
+		assert.Equal(tt, "application/json", w.Header().Get("Content-Type"))
+
+		// JWTs are not available
+		_, err = getCredential("application/jwt")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "credentials can only be represented as application/json, application/vc+ld+json or application/ld+json")
+
+		var safeErr *framework.SafeError
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusNotAcceptable, safeErr.StatusCode)
 	})
 
-	t.Run("Test Get Credential By Issuer", func(tt *testing.T) {
+	t.Run("Test Get Canonical Credential", func(tt *testing.T) {
 		bolt, err := storage.NewBoltDB()
 
 		// remove the db file after the test
@@ -576,20 +1071,16 @@ func TestCredentialAPI(t *testing.T) {
 
 		credService := newCredentialService(tt, bolt)
 
-		w := httptest.NewRecorder()
-
-		issuerID := "did:abc:123"
 		createCredRequest := router.CreateCredentialRequest{
-			Issuer:  issuerID,
+			Issuer:  "did:abc:123",
 			Subject: "did:abc:456",
 			Data: map[string]interface{}{
-				"firstName": "Jack",
 				"lastName":  "Dorsey",
+				"firstName": "Jack",
 			},
-			Expiry: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
 		}
-		requestValue := newRequestValue(tt, createCredRequest)
-		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+		w := httptest.NewRecorder()
 		err = credService.CreateCredential(newRequestContext(), w, req)
 		assert.NoError(tt, err)
 
@@ -597,23 +1088,1569 @@ func TestCredentialAPI(t *testing.T) {
 		err = json.NewDecoder(w.Body).Decode(&resp)
 		assert.NoError(tt, err)
 
-		w.Flush()
+		getCanonical := func() router.GetCanonicalCredentialResponse {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s?canonical=true", resp.Credential.ID), nil)
+			w := httptest.NewRecorder()
+			err := credService.GetCredential(newRequestContextWithParams(map[string]string{"id": resp.Credential.ID}), w, req)
+			assert.NoError(tt, err)
 
-		// get credential by issuer id
-		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credential?issuer=%s", issuerID), nil)
-		err = credService.GetCredentials(newRequestContext(), w, req)
-		assert.NoError(tt, err)
+			var canonicalResp router.GetCanonicalCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&canonicalResp)
+			assert.NoError(tt, err)
+			return canonicalResp
+		}
 
-		var getCredsResp router.GetCredentialsResponse
-		err = json.NewDecoder(w.Body).Decode(&getCredsResp)
-		assert.NoError(tt, err)
-		assert.NotEmpty(tt, getCredsResp)
-		assert.Len(tt, getCredsResp.Credentials, 1)
+		canonicalResp := getCanonical()
+		assert.Equal(tt, resp.Credential.ID, canonicalResp.ID)
+		assert.Equal(tt, credential.JCSCanonicalization, canonicalResp.Canonicalization)
+		assert.Contains(tt, canonicalResp.Credential, `"credentialSubject":{"firstName":"Jack","id":"did:abc:456","lastName":"Dorsey"}`)
+
+		digest := sha256.Sum256([]byte(canonicalResp.Credential))
+		assert.Equal(tt, hex.EncodeToString(digest[:]), canonicalResp.Digest)
+
+		// the digest is stable across calls
+		assert.Equal(tt, canonicalResp.Digest, getCanonical().Digest)
+	})
+
+	t.Run("Test Batch Create Credentials", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		serviceConfig := config.CredentialServiceConfig{RequiredClaims: []string{"jurisdiction"}}
+		credentialService, err := credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService, err := router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+
+		// an existing credential from another issuer
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:  "did:abc:000",
+			Subject: "did:abc:456",
+			Data: map[string]interface{}{
+				"firstName":    "Jack",
+				"jurisdiction": "US",
+			},
+		}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+		w := httptest.NewRecorder()
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		// the second request is missing a required claim, so neither credential is created
+		batchRequest := router.BatchCreateCredentialsRequest{
+			Requests: []router.CreateCredentialRequest{
+				{
+					Issuer:  "did:abc:123",
+					Subject: "did:abc:456",
+					Data: map[string]interface{}{
+						"firstName":    "Jack",
+						"jurisdiction": "US",
+					},
+				},
+				{
+					Issuer:  "did:abc:123",
+					Subject: "did:abc:789",
+					Data: map[string]interface{}{
+						"firstName": "Jill",
+					},
+				},
+			},
+		}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/batch", newRequestValue(tt, batchRequest))
+		w = httptest.NewRecorder()
+		err = credService.BatchCreateCredentials(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "request<1> in batch failed")
+
+		var safeErr *framework.SafeError
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		assert.Len(tt, safeErr.Fields, 1)
+		assert.Equal(tt, "requests[1]", safeErr.Fields[0].Field)
+
+		gotCreds, err := credentialService.GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: "did:abc:123"})
+		assert.NoError(tt, err)
+		assert.Empty(tt, gotCreds.Credentials)
+
+		// with every required claim present both credentials are created
+		batchRequest.Requests[1].Data["jurisdiction"] = "CA"
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/batch", newRequestValue(tt, batchRequest))
+		w = httptest.NewRecorder()
+		err = credService.BatchCreateCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var batchResp router.BatchCreateCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&batchResp)
+		assert.NoError(tt, err)
+		assert.Len(tt, batchResp.Credentials, 2)
+
+		gotCreds, err = credentialService.GetCredentialsByIssuer(credential.GetCredentialByIssuerRequest{Issuer: "did:abc:123"})
+		assert.NoError(tt, err)
+		assert.Len(tt, gotCreds.Credentials, 2)
+	})
+
+	t.Run("Test Create Credentials From CSV", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		createFromCSVAs := func(issuer, csv string) (*httptest.ResponseRecorder, error) {
+			request := router.CreateCredentialsFromCSVRequest{
+				CSV:     csv,
+				Mapping: map[string]string{"first": "firstName", "city": "address.city"},
+				Template: router.CSVCredentialTemplate{
+					Issuer:  issuer,
+					Subject: "${did}",
+					Data:    map[string]interface{}{"address": map[string]interface{}{"country": "US"}},
+					Expiry:  "${expires}",
+				},
+			}
+			req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/from-csv", newRequestValue(tt, request))
+			w := httptest.NewRecorder()
+			return w, credService.CreateCredentialsFromCSV(newRequestContext(), w, req)
+		}
+		createFromCSV := func(csv string) (*httptest.ResponseRecorder, error) {
+			return createFromCSVAs("did:abc:123", csv)
+		}
+
+		// a template's issuer must be a DID, or reference a column
+		_, err = createFromCSVAs("did:ABC:123", "did,first,city,expires\ndid:abc:1,Jack,Austin,2030-01-01T00:00:00Z\n")
+		var templateErr *framework.SafeError
+		require.ErrorAs(tt, err, &templateErr)
+		assert.Equal(tt, http.StatusBadRequest, templateErr.StatusCode)
+		require.Len(tt, templateErr.Fields, 1)
+		assert.Contains(tt, templateErr.Fields[0].Error, "issuer is not a valid DID")
+
+		// a referenced issuer is checked once each row is filled in
+		_, err = createFromCSVAs("${issuer}", "did,first,city,expires,issuer\n"+
+			"did:abc:1,Jack,Austin,2030-01-01T00:00:00Z,not-a-did\n")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "line<2>: issuer<not-a-did> is not a valid DID")
+
+		// a mapped column missing from the header
+		_, err = createFromCSV("did,first,expires\ndid:abc:1,Jack,2030-01-01T00:00:00Z\n")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "column(s) not in CSV header: city")
+
+		// failed rows are reported by line, counting lines within quoted values
+		_, err = createFromCSV("did,first,city,expires\n" +
+			"did:abc:1,Jack,\"San\nFrancisco\",soon\n" +
+			"did:abc:2,Jill,Austin,2030-01-01T00:00:00Z\n" +
+			"did:abc:3,Bob,Boston,never\n" +
+			"did:Abc:4,Ann,Denver,2030-01-01T00:00:00Z\n")
+		assert.Error(tt, err)
+
+		var safeErr *framework.SafeError
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		require.Len(tt, safeErr.Fields, 3)
+		assert.Equal(tt, "csv[line 2]", safeErr.Fields[0].Field)
+		assert.Equal(tt, "csv[line 5]", safeErr.Fields[1].Field)
+		assert.Equal(tt, "csv[line 6]", safeErr.Fields[2].Field)
+		assert.Contains(tt, safeErr.Fields[2].Error, "subject<did:Abc:4> is not a valid DID")
+
+		// nothing was issued
+		req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials?subject=did:abc:2", nil)
+		getW := httptest.NewRecorder()
+		err = credService.GetCredentials(newRequestContext(), getW, req)
+		assert.NoError(tt, err)
+		var getCredsResp router.GetCredentialsResponse
+		err = json.NewDecoder(getW.Body).Decode(&getCredsResp)
+		assert.NoError(tt, err)
+		assert.Empty(tt, getCredsResp.Credentials)
+
+		// every row is issued
+		w, err := createFromCSV("did,first,city,expires\n" +
+			"did:abc:1,Jack,San Francisco,2030-01-01T00:00:00Z\n" +
+			"did:abc:2,Jill,,2030-01-01T00:00:00Z\n")
+		assert.NoError(tt, err)
+
+		var resp router.BatchCreateCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		require.Len(tt, resp.Credentials, 2)
+
+		first := resp.Credentials[0]
+		assert.Equal(tt, "2030-01-01T00:00:00Z", first.ExpirationDate)
+		assert.Equal(tt, "did:abc:1", first.CredentialSubject["id"])
+		assert.Equal(tt, "Jack", first.CredentialSubject["firstName"])
+		assert.Equal(tt, map[string]interface{}{"country": "US", "city": "San Francisco"}, first.CredentialSubject["address"])
+
+		// empty values are left out, and the template's data is not modified by other rows
+		second := resp.Credentials[1]
+		assert.Equal(tt, "did:abc:2", second.CredentialSubject["id"])
+		assert.Equal(tt, map[string]interface{}{"country": "US"}, second.CredentialSubject["address"])
+	})
+
+	t.Run("Test Get Issuer Key Health", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		keyStoreService := newTestKeyStoreService(tt, bolt)
+
+		// invalid max age
+		_, err = credential.NewCredentialService(config.CredentialServiceConfig{IssuerKeyMaxAge: "soon"}, bolt, keyStoreService)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid issuer key max age: soon")
+
+		serviceConfig := config.CredentialServiceConfig{IssuerKeyMaxAge: "1ns"}
+		credentialService, err := credential.NewCredentialService(serviceConfig, bolt, keyStoreService)
+		require.NoError(tt, err)
+		credService, err := router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+
+		// no key for the issuer
+		issuer := "did:test:issuer"
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/issuers/%s/key-health", issuer), nil)
+		w := httptest.NewRecorder()
+		err = credService.GetIssuerKeyHealth(newRequestContextWithParams(map[string]string{"did": issuer}), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "no signing key found for issuer: did:test:issuer")
+
+		var safeErr *framework.SafeError
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusNotFound, safeErr.StatusCode)
+
+		// store a key for the issuer, which is immediately past the max age
+		err = keyStoreService.StoreKey(keystore.StoreKeyRequest{
+			ID:         issuer + "#key-1",
+			Type:       crypto.Ed25519,
+			Controller: issuer,
+			Key:        []byte("key"),
+		})
+		assert.NoError(tt, err)
+
+		w = httptest.NewRecorder()
+		err = credService.GetIssuerKeyHealth(newRequestContextWithParams(map[string]string{"did": issuer}), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.GetIssuerKeyHealthResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, issuer+"#key-1", resp.KeyID)
+		assert.Equal(tt, crypto.Ed25519, resp.Algorithm)
+		assert.NotEmpty(tt, resp.CreatedAt)
+		assert.NotEmpty(tt, resp.Age)
+		assert.Equal(tt, "1ns", resp.MaxAge)
+		assert.True(tt, resp.PastMaxAge)
+
+		// the key's age is measured by the service's clock
+		createdAt, err := time.Parse(time.RFC3339, resp.CreatedAt)
+		require.NoError(tt, err)
+		serviceConfig = config.CredentialServiceConfig{IssuerKeyMaxAge: "24h"}
+		credentialService, err = credential.NewCredentialService(serviceConfig, bolt, keyStoreService)
+		require.NoError(tt, err)
+		credService, err = router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+		getKeyHealthAt := func(now time.Time) router.GetIssuerKeyHealthResponse {
+			credentialService.SetClock(func() time.Time { return now })
+			w := httptest.NewRecorder()
+			err := credService.GetIssuerKeyHealth(newRequestContextWithParams(map[string]string{"did": issuer}), w, req)
+			require.NoError(tt, err)
+
+			var resp router.GetIssuerKeyHealthResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			require.NoError(tt, err)
+			return resp
+		}
+
+		resp = getKeyHealthAt(createdAt.Add(time.Hour))
+		assert.Equal(tt, "1h0m0s", resp.Age)
+		assert.False(tt, resp.PastMaxAge)
+
+		resp = getKeyHealthAt(createdAt.Add(25 * time.Hour))
+		assert.Equal(tt, "25h0m0s", resp.Age)
+		assert.True(tt, resp.PastMaxAge)
+	})
+
+	t.Run("Test Batch Get Credentials", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		var createdIDs []string
+		for _, subject := range []string{"did:abc:456", "did:abc:789"} {
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  "did:abc:123",
+				Subject: subject,
+				Data: map[string]interface{}{
+					"firstName": "Jack",
+				},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			w := httptest.NewRecorder()
+			err = credService.CreateCredential(newRequestContext(), w, req)
+			assert.NoError(tt, err)
+
+			var resp router.CreateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(tt, err)
+			createdIDs = append(createdIDs, resp.Credential.ID)
+		}
+
+		// no ids
+		req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/batch-get", newRequestValue(tt, router.BatchGetCredentialsRequest{}))
+		w := httptest.NewRecorder()
+		err = credService.BatchGetCredentials(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid batch get credentials request")
+
+		// too many ids
+		tooMany := make([]string, credential.MaxBatchGetCredentials+1)
+		for i := range tooMany {
+			tooMany[i] = uuid.NewString()
+		}
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/batch-get", newRequestValue(tt, router.BatchGetCredentialsRequest{IDs: tooMany}))
+		err = credService.BatchGetCredentials(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "cannot get more than 100 credentials at once")
+
+		// two that exist, one that does not
+		batchRequest := router.BatchGetCredentialsRequest{IDs: append(createdIDs, "bad")}
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/batch-get", newRequestValue(tt, batchRequest))
+		err = credService.BatchGetCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var batchResp router.BatchGetCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&batchResp)
+		assert.NoError(tt, err)
+		assert.Len(tt, batchResp.Credentials, 2)
+		assert.ElementsMatch(tt, createdIDs, []string{batchResp.Credentials[0].ID, batchResp.Credentials[1].ID})
+		assert.Equal(tt, []string{"bad"}, batchResp.NotFound)
+
+		// each credential is returned once, however many times it is requested
+		batchRequest = router.BatchGetCredentialsRequest{IDs: []string{createdIDs[0], "bad", createdIDs[0], "bad", createdIDs[1]}}
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/batch-get", newRequestValue(tt, batchRequest))
+		w = httptest.NewRecorder()
+		err = credService.BatchGetCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		batchResp = router.BatchGetCredentialsResponse{}
+		err = json.NewDecoder(w.Body).Decode(&batchResp)
+		assert.NoError(tt, err)
+		assert.Len(tt, batchResp.Credentials, 2)
+		assert.ElementsMatch(tt, createdIDs, []string{batchResp.Credentials[0].ID, batchResp.Credentials[1].ID})
+		assert.Equal(tt, []string{"bad"}, batchResp.NotFound)
+	})
+
+	t.Run("Test Get Credential By Schema", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		schemaService, err := schema.NewSchemaService(config.SchemaServiceConfig{}, bolt)
+		require.NoError(tt, err)
+		createdSchema, err := schemaService.CreateSchema(schema.CreateSchemaRequest{Author: "did:test", Name: "name", Schema: map[string]interface{}{"type": "object"}})
+		require.NoError(tt, err)
+
+		w := httptest.NewRecorder()
+
+		schemaID := createdSchema.ID
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:  "did:abc:123",
+			Subject: "did:abc:456",
+			Schema:  schemaID,
+			Data: map[string]interface{}{
+				"firstName": "Jack",
+				"lastName":  "Dorsey",
+			},
+			Expiry: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		}
+		requestValue := newRequestValue(tt, createCredRequest)
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.CreateCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+
+		w.Flush()
+
+		// get credential by schema
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credential?schema=%s", schemaID), nil)
+		err = credService.GetCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var getCredsResp router.GetCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&getCredsResp)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, getCredsResp)
+		assert.Len(tt, getCredsResp.Credentials, 1)
+		assert.Equal(tt, resp.Credential.ID, getCredsResp.Credentials[0].ID)
+		assert.Equal(tt, resp.Credential.CredentialSchema.ID, getCredsResp.Credentials[0].CredentialSchema.ID)
+	})
+
+	t.Run("Test Get Credential By Issuer", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		w := httptest.NewRecorder()
+
+		issuerID := "did:abc:123"
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:  issuerID,
+			Subject: "did:abc:456",
+			Data: map[string]interface{}{
+				"firstName": "Jack",
+				"lastName":  "Dorsey",
+			},
+			Expiry: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		}
+		requestValue := newRequestValue(tt, createCredRequest)
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.CreateCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+
+		w.Flush()
+
+		// get credential by issuer id
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credential?issuer=%s", issuerID), nil)
+		err = credService.GetCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var getCredsResp router.GetCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&getCredsResp)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, getCredsResp)
+		assert.Len(tt, getCredsResp.Credentials, 1)
 		assert.Equal(tt, resp.Credential.ID, getCredsResp.Credentials[0].ID)
 		assert.Equal(tt, resp.Credential.Issuer, getCredsResp.Credentials[0].Issuer)
 	})
 
-	t.Run("Test Get Credential By Subject", func(tt *testing.T) {
+	t.Run("Test Get Credentials Paginated", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		issuerID := "did:abc:123"
+		var createdIDs []string
+		for i := 0; i < 3; i++ {
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  issuerID,
+				Subject: "did:abc:456",
+				Data: map[string]interface{}{
+					"index": i,
+				},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			w := httptest.NewRecorder()
+			err = credService.CreateCredential(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var resp router.CreateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			require.NoError(tt, err)
+			createdIDs = append(createdIDs, resp.Credential.ID)
+		}
+
+		// walk the pages of credentials
+		var gotIDs []string
+		query := fmt.Sprintf("issuer=%s&pageSize=2", issuerID)
+		for pages := 0; ; pages++ {
+			require.Less(tt, pages, 2)
+			req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials?"+query, nil)
+			w := httptest.NewRecorder()
+			err = credService.GetCredentials(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var creds []credsdk.VerifiableCredential
+			page := router.GetCredentialsPage{Page: framework.Page{Items: &creds}}
+			err = json.NewDecoder(w.Body).Decode(&page)
+			require.NoError(tt, err)
+			assert.Equal(tt, 3, page.TotalCount)
+			for _, cred := range creds {
+				gotIDs = append(gotIDs, cred.ID)
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			query = fmt.Sprintf("issuer=%s&pageSize=2&pageToken=%s", issuerID, page.NextPageToken)
+		}
+		assert.ElementsMatch(tt, createdIDs, gotIDs)
+
+		// a bad page token
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials?issuer=%s&pageToken=bad", issuerID), nil)
+		err = credService.GetCredentials(newRequestContext(), httptest.NewRecorder(), req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid pageToken")
+	})
+
+	t.Run("Test Get Credentials With Fields", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		issuerID := "did:abc:123"
+		var createdIDs []string
+		for i := 0; i < 2; i++ {
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  issuerID,
+				Subject: fmt.Sprintf("did:abc:45%d", i),
+				Data: map[string]interface{}{
+					"index": i,
+				},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			w := httptest.NewRecorder()
+			err = credService.CreateCredential(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var resp router.CreateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			require.NoError(tt, err)
+			createdIDs = append(createdIDs, resp.Credential.ID)
+		}
+
+		getCredentials := func(query string) (map[string]interface{}, error) {
+			req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials?"+query, nil)
+			w := httptest.NewRecorder()
+			if err := credService.GetCredentials(newRequestContext(), w, req); err != nil {
+				return nil, err
+			}
+			var resp map[string]interface{}
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(tt, err)
+			return resp, nil
+		}
+
+		// only the selected fields of each credential are returned
+		resp, err := getCredentials(fmt.Sprintf("issuer=%s&fields=id,credentialSubject", issuerID))
+		require.NoError(tt, err)
+		creds := resp["credentials"].([]interface{})
+		require.Len(tt, creds, 2)
+		for _, cred := range creds {
+			credFields := cred.(map[string]interface{})
+			assert.Len(tt, credFields, 2)
+			assert.Contains(tt, createdIDs, credFields["id"])
+			assert.Contains(tt, credFields, "credentialSubject")
+		}
+
+		// and of each credential in a page
+		resp, err = getCredentials(fmt.Sprintf("issuer=%s&pageSize=1&fields=id", issuerID))
+		require.NoError(tt, err)
+		assert.EqualValues(tt, 2, resp["totalCount"])
+		items := resp["items"].([]interface{})
+		require.Len(tt, items, 1)
+		assert.Len(tt, items[0].(map[string]interface{}), 1)
+
+		// an unknown field lists the valid ones
+		_, err = getCredentials(fmt.Sprintf("issuer=%s&fields=id,jwt", issuerID))
+		var safeErr *framework.SafeError
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		assert.Contains(tt, err.Error(), "unknown fields<jwt>, must be one or more of: @context, credentialSchema, credentialStatus, credentialSubject")
+
+		// a single credential, keeping its envelope
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s?fields=issuer", createdIDs[0]), nil)
+		w := httptest.NewRecorder()
+		err = credService.GetCredential(newRequestContextWithParams(map[string]string{"id": createdIDs[0]}), w, req)
+		require.NoError(tt, err)
+
+		var credResp map[string]interface{}
+		err = json.NewDecoder(w.Body).Decode(&credResp)
+		require.NoError(tt, err)
+		assert.Equal(tt, createdIDs[0], credResp["id"])
+		assert.Equal(tt, map[string]interface{}{"issuer": issuerID}, credResp["credential"])
+
+		// the digest of a canonical credential is of the whole credential
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s?fields=issuer&canonical=true", createdIDs[0]), nil)
+		err = credService.GetCredential(newRequestContextWithParams(map[string]string{"id": createdIDs[0]}), httptest.NewRecorder(), req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "fields cannot be selected for a canonical credential")
+	})
+
+	t.Run("Test Credential Status", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credentialService, err := credential.NewCredentialService(config.CredentialServiceConfig{}, bolt, newTestKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService, err := router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+
+		issuerID := "did:abc:123"
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:  issuerID,
+			Subject: "did:abc:456",
+			Data: map[string]interface{}{
+				"firstName": "Jack",
+			},
+			Expiry: time.Now().Add(time.Hour).Format(time.RFC3339),
+		}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+		w := httptest.NewRecorder()
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.CreateCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		credID := resp.Credential.ID
+
+		getStatus := func() router.GetCredentialStatusResponse {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s/status", credID), nil)
+			w := httptest.NewRecorder()
+			err := credService.GetCredentialStatus(newRequestContextWithParams(map[string]string{"id": credID}), w, req)
+			assert.NoError(tt, err)
+
+			var statusResp router.GetCredentialStatusResponse
+			err = json.NewDecoder(w.Body).Decode(&statusResp)
+			assert.NoError(tt, err)
+			return statusResp
+		}
+		getByStatus := func(status string) []credsdk.VerifiableCredential {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials?issuer=%s&status=%s", issuerID, status), nil)
+			w := httptest.NewRecorder()
+			err := credService.GetCredentials(newRequestContext(), w, req)
+			assert.NoError(tt, err)
+
+			var getCredsResp router.GetCredentialsResponse
+			err = json.NewDecoder(w.Body).Decode(&getCredsResp)
+			assert.NoError(tt, err)
+			return getCredsResp.Credentials
+		}
+
+		statusResp := getStatus()
+		assert.Equal(tt, credID, statusResp.ID)
+		assert.Equal(tt, credential.StatusActive, statusResp.Status)
+		assert.Equal(tt, createCredRequest.Expiry, statusResp.ExpirationDate)
+		assert.Len(tt, getByStatus(credential.StatusActive), 1)
+		assert.Empty(tt, getByStatus(credential.StatusExpired))
+
+		// once past its expiration date the credential is expired
+		credentialService.SetClock(func() time.Time { return time.Now().Add(2 * time.Hour) })
+		assert.Equal(tt, credential.StatusExpired, getStatus().Status)
+		assert.Empty(tt, getByStatus(credential.StatusActive))
+		assert.Len(tt, getByStatus(credential.StatusExpired), 1)
+
+		// unknown status
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials?issuer=%s&status=revoked", issuerID), nil)
+		err = credService.GetCredentials(newRequestContext(), httptest.NewRecorder(), req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid status<revoked>")
+	})
+
+	t.Run("Test Get Credential Bundle", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		didService := newDIDService(tt, bolt)
+		schemaService := newSchemaService(tt, bolt)
+		credService := newCredentialService(tt, bolt)
+
+		// an issuer created by the DID service
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/key", newRequestValue(tt, router.CreateDIDByMethodRequest{KeyType: crypto.Ed25519}))
+		w := httptest.NewRecorder()
+		err = didService.CreateDIDByMethod(newRequestContextWithParams(map[string]string{"method": "key"}), w, req)
+		require.NoError(tt, err)
+		var createDIDResp router.CreateDIDByMethodResponse
+		err = json.NewDecoder(w.Body).Decode(&createDIDResp)
+		require.NoError(tt, err)
+		issuerID := createDIDResp.DID.ID
+
+		simpleSchema := map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"firstName": map[string]interface{}{
+					"type": "string",
+				},
+			},
+		}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/schemas", newRequestValue(tt, router.CreateSchemaRequest{Author: issuerID, Name: "bundled schema", Schema: simpleSchema}))
+		w = httptest.NewRecorder()
+		err = schemaService.CreateSchema(newRequestContext(), w, req)
+		require.NoError(tt, err)
+		var createSchemaResp router.CreateSchemaResponse
+		err = json.NewDecoder(w.Body).Decode(&createSchemaResp)
+		require.NoError(tt, err)
+
+		createCred := func(issuer, schemaID string) string {
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  issuer,
+				Subject: "did:abc:456",
+				Schema:  schemaID,
+				Data:    map[string]interface{}{"firstName": "Jack"},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			w := httptest.NewRecorder()
+			err := credService.CreateCredential(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var resp router.CreateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			require.NoError(tt, err)
+			return resp.Credential.ID
+		}
+		getBundle := func(credID string) (*router.GetCredentialBundleResponse, error) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s/bundle", credID), nil)
+			w := httptest.NewRecorder()
+			if err := credService.GetCredentialBundle(newRequestContextWithParams(map[string]string{"id": credID}), w, req); err != nil {
+				return nil, err
+			}
+
+			var bundleResp router.GetCredentialBundleResponse
+			err := json.NewDecoder(w.Body).Decode(&bundleResp)
+			assert.NoError(tt, err)
+			return &bundleResp, nil
+		}
+
+		credID := createCred(issuerID, createSchemaResp.ID)
+		bundle, err := getBundle(credID)
+		require.NoError(tt, err)
+		assert.Equal(tt, credID, bundle.Credential.ID)
+		require.NotEmpty(tt, bundle.Schema)
+		assert.Equal(tt, createSchemaResp.ID, bundle.Schema.ID)
+		assert.EqualValues(tt, simpleSchema, bundle.Schema.Schema)
+		assert.Equal(tt, issuerID, bundle.IssuerDID.ID)
+		assert.Equal(tt, createDIDResp.DID.VerificationMethod, bundle.IssuerDID.VerificationMethod)
+		assert.Equal(tt, credential.LocalDIDResolver, bundle.IssuerDIDResolution.Resolver)
+
+		// a did:key the service did not create is resolved from its key, and a credential without a schema has none
+		_, externalDID, err := didsdk.GenerateDIDKey(crypto.Ed25519)
+		require.NoError(tt, err)
+		externalCredID := createCred(string(*externalDID), "")
+		bundle, err = getBundle(externalCredID)
+		require.NoError(tt, err)
+		assert.Nil(tt, bundle.Schema)
+		assert.Equal(tt, string(*externalDID), bundle.IssuerDID.ID)
+		assert.NotEmpty(tt, bundle.IssuerDID.VerificationMethod)
+		assert.Equal(tt, credential.DriverDIDResolver, bundle.IssuerDIDResolution.Resolver)
+
+		// an issuer which cannot be resolved cannot be bundled
+		_, err = getBundle(createCred("did:abc:123", ""))
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "could not resolve issuer<did:abc:123>")
+		assert.Contains(tt, err.Error(), "driver: no gateway or driver for DID method: abc")
+
+		_, err = getBundle("missing")
+		var safeErr *framework.SafeError
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusNotFound, safeErr.StatusCode)
+	})
+
+	t.Run("Test Get Credential Bundle With Remote Resolution", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		// a did:web gateway requiring a token, and a universal resolver responding with resolution results
+		var gatewayAuth []string
+		gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gatewayAuth = append(gatewayAuth, r.Header.Get("Authorization"))
+			id := strings.TrimPrefix(r.URL.Path, "/1.0/identifiers/")
+			_ = json.NewEncoder(w).Encode(didsdk.DIDDocument{ID: id})
+		}))
+		tt.Cleanup(gateway.Close)
+		universal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := strings.TrimPrefix(r.URL.Path, "/")
+			if id == "did:abc:missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"didDocument": didsdk.DIDDocument{ID: id}})
+		}))
+		tt.Cleanup(universal.Close)
+
+		serviceConfig := config.CredentialServiceConfig{
+			DIDResolution: config.DIDResolutionConfig{
+				Methods: map[string]config.DIDResolverConfig{
+					"web": {
+						Endpoint: gateway.URL + "/1.0/identifiers",
+						Headers:  map[string]string{"Authorization": "Bearer token"},
+					},
+				},
+				Universal: &config.DIDResolverConfig{Endpoint: universal.URL, Timeout: "1s"},
+			},
+		}
+		credentialService, err := credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService, err := router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+
+		getIssuerBundle := func(issuer string) (*router.GetCredentialBundleResponse, error) {
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  issuer,
+				Subject: "did:abc:456",
+				Data:    map[string]interface{}{"firstName": "Jack"},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			w := httptest.NewRecorder()
+			err := credService.CreateCredential(newRequestContext(), w, req)
+			require.NoError(tt, err)
+			var createResp router.CreateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&createResp)
+			require.NoError(tt, err)
+
+			credID := createResp.Credential.ID
+			req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s/bundle", credID), nil)
+			w = httptest.NewRecorder()
+			if err := credService.GetCredentialBundle(newRequestContextWithParams(map[string]string{"id": credID}), w, req); err != nil {
+				return nil, err
+			}
+			var bundleResp router.GetCredentialBundleResponse
+			err = json.NewDecoder(w.Body).Decode(&bundleResp)
+			assert.NoError(tt, err)
+			return &bundleResp, nil
+		}
+
+		// the method's gateway answers before the universal resolver
+		bundle, err := getIssuerBundle("did:web:example.com")
+		require.NoError(tt, err)
+		assert.Equal(tt, "did:web:example.com", bundle.IssuerDID.ID)
+		assert.Equal(tt, credential.DriverDIDResolver, bundle.IssuerDIDResolution.Resolver)
+		assert.Equal(tt, gateway.URL+"/1.0/identifiers/", bundle.IssuerDIDResolution.Endpoint)
+		assert.Equal(tt, []string{"Bearer token"}, gatewayAuth)
+
+		// a method without a gateway or driver falls back to the universal resolver
+		bundle, err = getIssuerBundle("did:abc:123")
+		require.NoError(tt, err)
+		assert.Equal(tt, "did:abc:123", bundle.IssuerDID.ID)
+		assert.Equal(tt, credential.UniversalDIDResolver, bundle.IssuerDIDResolution.Resolver)
+		assert.Equal(tt, universal.URL+"/", bundle.IssuerDIDResolution.Endpoint)
+
+		_, err = getIssuerBundle("did:abc:missing")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "universal: resolver responded with status 404")
+
+		// the order resolvers are tried in is configurable, and is validated
+		serviceConfig.DIDResolution.Order = []string{credential.UniversalDIDResolver, credential.DriverDIDResolver}
+		credentialService, err = credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService, err = router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+		bundle, err = getIssuerBundle("did:web:example.com")
+		require.NoError(tt, err)
+		assert.Equal(tt, credential.UniversalDIDResolver, bundle.IssuerDIDResolution.Resolver)
+
+		serviceConfig.DIDResolution.Order = []string{credential.LocalDIDResolver, "cache"}
+		_, err = credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "unknown DID resolver<cache>")
+
+		serviceConfig.DIDResolution.Order = nil
+		serviceConfig.DIDResolution.Universal = &config.DIDResolverConfig{Endpoint: universal.URL, Timeout: "soon"}
+		_, err = credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid universal DID resolver: timeout must be a positive duration: soon")
+	})
+
+	t.Run("Test Issuance Freeze", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		createCredential := func(issuer string) (*router.CreateCredentialResponse, error) {
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  issuer,
+				Subject: "did:abc:456",
+				Data: map[string]interface{}{
+					"firstName": "Jack",
+				},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			w := httptest.NewRecorder()
+			if err := credService.CreateCredential(newRequestContext(), w, req); err != nil {
+				return nil, err
+			}
+			var resp router.CreateCredentialResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(tt, err)
+			return &resp, nil
+		}
+		setFreeze := func(request router.SetIssuanceFreezeRequest) {
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/freeze", newRequestValue(tt, request))
+			err := credService.SetIssuanceFreeze(newRequestContext(), httptest.NewRecorder(), req)
+			assert.NoError(tt, err)
+		}
+
+		created, err := createCredential("did:abc:123")
+		assert.NoError(tt, err)
+
+		// freeze all issuance
+		setFreeze(router.SetIssuanceFreezeRequest{Frozen: true})
+
+		_, err = createCredential("did:abc:123")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "credential issuance is frozen for maintenance")
+
+		var safeErr *framework.SafeError
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusServiceUnavailable, safeErr.StatusCode)
+
+		// reads continue to work
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s", created.Credential.ID), nil)
+		err = credService.GetCredential(newRequestContextWithParams(map[string]string{"id": created.Credential.ID}), httptest.NewRecorder(), req)
+		assert.NoError(tt, err)
+
+		// the freeze is stored, so it applies to a newly started service
+		credService = newCredentialService(tt, bolt)
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/freeze", nil)
+		w := httptest.NewRecorder()
+		err = credService.GetIssuanceFreeze(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var freezeResp router.GetIssuanceFreezeResponse
+		err = json.NewDecoder(w.Body).Decode(&freezeResp)
+		assert.NoError(tt, err)
+		assert.True(tt, freezeResp.Global)
+
+		_, err = createCredential("did:abc:123")
+		assert.Error(tt, err)
+
+		// freeze a single issuer instead
+		setFreeze(router.SetIssuanceFreezeRequest{Frozen: false})
+		setFreeze(router.SetIssuanceFreezeRequest{Issuer: "did:abc:123", Frozen: true})
+
+		_, err = createCredential("did:abc:123")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "cannot issue credentials for issuer: did:abc:123")
+
+		_, err = createCredential("did:abc:789")
+		assert.NoError(tt, err)
+
+		setFreeze(router.SetIssuanceFreezeRequest{Issuer: "did:abc:123", Frozen: false})
+		_, err = createCredential("did:abc:123")
+		assert.NoError(tt, err)
+	})
+
+	t.Run("Test Get Credential By Subject", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		w := httptest.NewRecorder()
+
+		subjectID := "did:abc:456"
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:  "did:abc:123",
+			Subject: subjectID,
+			Data: map[string]interface{}{
+				"firstName": "Jack",
+				"lastName":  "Dorsey",
+			},
+			Expiry: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		}
+		requestValue := newRequestValue(tt, createCredRequest)
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.CreateCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+
+		// get credential by subject id
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credential?subject=%s", subjectID), nil)
+		err = credService.GetCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var getCredsResp router.GetCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&getCredsResp)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, getCredsResp)
+		assert.Len(tt, getCredsResp.Credentials, 1)
+		assert.Equal(tt, resp.Credential.ID, getCredsResp.Credentials[0].ID)
+		assert.Equal(tt, resp.Credential.CredentialSubject[credsdk.VerifiableCredentialIDProperty], getCredsResp.Credentials[0].CredentialSubject[credsdk.VerifiableCredentialIDProperty])
+	})
+
+	t.Run("Test Create Credential With Invalid DIDs", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		createCredential := func(issuer, subject string) error {
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  issuer,
+				Subject: subject,
+				Data: map[string]interface{}{
+					"firstName": "Jack",
+				},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			return credService.CreateCredential(newRequestContext(), httptest.NewRecorder(), req)
+		}
+
+		// each malformed DID is reported against its field
+		err = createCredential("did:ABC:123", "did:abc:45 6")
+		var safeErr *framework.SafeError
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		assert.ElementsMatch(tt, []framework.FieldError{
+			{Field: "issuer", Error: "issuer is not a valid DID: DID method<ABC> may only contain lowercase letters and digits"},
+			{Field: "subject", Error: "subject is not a valid DID: DID method-specific ID may not contain ' '"},
+		}, safeErr.Fields)
+
+		// a DID of a method the service knows is checked further
+		err = createCredential("did:key:z123", "did:abc:456")
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, []framework.FieldError{
+			{Field: "issuer", Error: "issuer is not a valid DID: did:key method-specific ID is not a multibase encoded public key"},
+		}, safeErr.Fields)
+
+		// a subject which is not a DID is an alias, so is not checked as a DID
+		err = createCredential("did:abc:123", "jack")
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Empty(tt, safeErr.Fields)
+		assert.Contains(tt, err.Error(), "no subject alias registered with name: jack")
+
+		// the requests of a batch are each checked
+		batchRequest := router.BatchCreateCredentialsRequest{Requests: []router.CreateCredentialRequest{
+			{Issuer: "did:abc:123", Subject: "did:abc:456", Data: map[string]interface{}{"firstName": "Jack"}},
+			{Issuer: "abc:123", Subject: "did:abc:456", Data: map[string]interface{}{"firstName": "Jack"}},
+		}}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/batch", newRequestValue(tt, batchRequest))
+		err = credService.BatchCreateCredentials(newRequestContext(), httptest.NewRecorder(), req)
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		assert.Equal(tt, []framework.FieldError{
+			{Field: "issuer", Error: "issuer is not a valid DID: DID must begin with 'did:'"},
+		}, safeErr.Fields)
+	})
+
+	t.Run("Test Subject Aliases", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		createCredential := func(subject string) error {
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  "did:abc:123",
+				Subject: subject,
+				Data: map[string]interface{}{
+					"firstName": "Jack",
+				},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			return credService.CreateCredential(newRequestContext(), httptest.NewRecorder(), req)
+		}
+
+		// an alias which is not registered cannot be resolved
+		err = createCredential("jack")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "no subject alias registered with name: jack")
+		var safeErr *framework.SafeError
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+
+		req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/aliases/jack", nil)
+		err = credService.GetSubjectAlias(newRequestContextWithParams(map[string]string{"alias": "jack"}), httptest.NewRecorder(), req)
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusNotFound, safeErr.StatusCode)
+
+		// a DID cannot be an alias
+		badAliasRequest := router.SetSubjectAliasRequest{Alias: "did:abc:456", DID: "did:abc:456"}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/aliases", newRequestValue(tt, badAliasRequest))
+		err = credService.SetSubjectAlias(newRequestContext(), httptest.NewRecorder(), req)
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+
+		aliasRequest := router.SetSubjectAliasRequest{Alias: "jack", DID: "did:abc:456"}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/aliases", newRequestValue(tt, aliasRequest))
+		err = credService.SetSubjectAlias(newRequestContext(), httptest.NewRecorder(), req)
+		assert.NoError(tt, err)
+
+		err = createCredential("jack")
+		assert.NoError(tt, err)
+
+		// credentials are listed by the alias as by the DID
+		w := httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials?subject=jack", nil)
+		err = credService.GetCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var getCredsResp router.GetCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&getCredsResp)
+		assert.NoError(tt, err)
+		assert.Len(tt, getCredsResp.Credentials, 1)
+		assert.Equal(tt, "did:abc:456", getCredsResp.Credentials[0].CredentialSubject[credsdk.VerifiableCredentialIDProperty])
+
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/aliases", nil)
+		err = credService.GetSubjectAliases(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var aliasesResp router.GetSubjectAliasesResponse
+		err = json.NewDecoder(w.Body).Decode(&aliasesResp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []router.GetSubjectAliasResponse{{Alias: "jack", DID: "did:abc:456"}}, aliasesResp.Aliases)
+
+		// or a page of them
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/aliases?pageSize=1", nil)
+		err = credService.GetSubjectAliases(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var aliases []router.GetSubjectAliasResponse
+		aliasesPage := framework.Page{Items: &aliases}
+		err = json.NewDecoder(w.Body).Decode(&aliasesPage)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []router.GetSubjectAliasResponse{{Alias: "jack", DID: "did:abc:456"}}, aliases)
+		assert.Equal(tt, 1, aliasesPage.TotalCount)
+		assert.Empty(tt, aliasesPage.NextPageToken)
+
+		// once deleted, the alias no longer resolves
+		req = httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials/aliases/jack", nil)
+		err = credService.DeleteSubjectAlias(newRequestContextWithParams(map[string]string{"alias": "jack"}), httptest.NewRecorder(), req)
+		assert.NoError(tt, err)
+
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials?subject=jack", nil)
+		err = credService.GetCredentials(newRequestContext(), httptest.NewRecorder(), req)
+		assert.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+	})
+
+	t.Run("Test Delete Credential", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:  "did:abc:123",
+			Subject: "did:abc:456",
+			Data: map[string]interface{}{
+				"firstName": "Jack",
+				"lastName":  "Dorsey",
+			},
+			Expiry: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		}
+		requestValue := newRequestValue(tt, createCredRequest)
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
+		w := httptest.NewRecorder()
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.CreateCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+
+		w.Flush()
+
+		// get credential by id
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s", resp.Credential.ID), nil)
+		err = credService.GetCredential(newRequestContextWithParams(map[string]string{"id": resp.Credential.ID}), w, req)
+		assert.NoError(tt, err)
+
+		var getCredResp router.GetCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&getCredResp)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, getCredResp)
+		assert.Equal(tt, resp.Credential.ID, getCredResp.ID)
+
+		w.Flush()
+
+		// delete it
+		req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s", resp.Credential.ID), nil)
+		err = credService.DeleteCredential(newRequestContextWithParams(map[string]string{"id": resp.Credential.ID}), w, req)
+		assert.NoError(tt, err)
+
+		w.Flush()
+
+		// get it back
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s", resp.Credential.ID), nil)
+		err = credService.GetCredential(newRequestContextWithParams(map[string]string{"id": resp.Credential.ID}), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), fmt.Sprintf("could not get credential with id: %s", resp.Credential.ID))
+	})
+
+	t.Run("Test Get Expiring Credentials", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		now := time.Now()
+		createWithExpiry := func(issuer string, expiresIn time.Duration) string {
+			createRequest := router.CreateCredentialRequest{Issuer: issuer, Subject: "did:abc:456", Data: map[string]interface{}{"firstName": "Jack"}}
+			if expiresIn != 0 {
+				createRequest.Expiry = now.Add(expiresIn).Format(time.RFC3339)
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createRequest))
+			w := httptest.NewRecorder()
+			err := credService.CreateCredential(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var resp router.CreateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			require.NoError(tt, err)
+			return resp.Credential.ID
+		}
+		tenDays := createWithExpiry("did:abc:123", 10*24*time.Hour)
+		createWithExpiry("did:abc:123", 40*24*time.Hour)
+		oneDay := createWithExpiry("did:abc:123", 24*time.Hour)
+		createWithExpiry("did:abc:123", -24*time.Hour)
+		createWithExpiry("did:abc:123", 0)
+		otherIssuer := createWithExpiry("did:abc:789", 2*24*time.Hour)
+
+		getExpiring := func(query string) (*router.GetExpiringCredentialsResponse, error) {
+			req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/expiring?"+query, nil)
+			w := httptest.NewRecorder()
+			if err := credService.GetExpiringCredentials(newRequestContext(), w, req); err != nil {
+				return nil, err
+			}
+			var resp router.GetExpiringCredentialsResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			return &resp, err
+		}
+		ids := func(resp *router.GetExpiringCredentialsResponse) []string {
+			var gotIDs []string
+			for _, cred := range resp.Credentials {
+				gotIDs = append(gotIDs, cred.ID)
+			}
+			return gotIDs
+		}
+
+		// a window is required
+		_, err = getExpiring("")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "cannot get expiring credentials without the within query parameter")
+
+		_, err = getExpiring("within=soon")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid window: soon")
+
+		// soonest first, excluding expired credentials and those expiring later
+		resp, err := getExpiring("within=30d")
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{oneDay, otherIssuer, tenDays}, ids(resp))
+
+		// filtered by issuer
+		resp, err = getExpiring("within=30d&issuer=did:abc:123")
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{oneDay, tenDays}, ids(resp))
+
+		// paged
+		getPage := func(query string) (*framework.Page, []string) {
+			req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/expiring?"+query, nil)
+			w := httptest.NewRecorder()
+			err := credService.GetExpiringCredentials(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var creds []credsdk.VerifiableCredential
+			page := framework.Page{Items: &creds}
+			err = json.NewDecoder(w.Body).Decode(&page)
+			require.NoError(tt, err)
+			return &page, ids(&router.GetExpiringCredentialsResponse{Credentials: creds})
+		}
+		page, pageIDs := getPage("within=30d&pageSize=2")
+		assert.Equal(tt, []string{oneDay, otherIssuer}, pageIDs)
+		assert.Equal(tt, 3, page.TotalCount)
+		require.NotEmpty(tt, page.NextPageToken)
+
+		page, pageIDs = getPage("within=30d&pageSize=2&pageToken=" + page.NextPageToken)
+		assert.Equal(tt, []string{tenDays}, pageIDs)
+		assert.Empty(tt, page.NextPageToken)
+
+		_, err = getExpiring("within=30d&pageSize=101")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "pageSize must be between 1 and 100")
+	})
+
+	t.Run("Test Delete Credentials", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		schemaService, err := schema.NewSchemaService(config.SchemaServiceConfig{}, bolt)
+		require.NoError(tt, err)
+		createdSchema, err := schemaService.CreateSchema(schema.CreateSchemaRequest{Author: "did:test", Name: "test schema", Schema: map[string]interface{}{"type": "object"}})
+		require.NoError(tt, err)
+
+		// two credentials from one issuer, one with a schema, and one from an issuer whose DID contains the first's
+		createRequests := []router.CreateCredentialRequest{
+			{Issuer: "did:abc:123", Subject: "did:abc:456", Schema: createdSchema.ID, Data: map[string]interface{}{"firstName": "Jack"}},
+			{Issuer: "did:abc:123", Subject: "did:abc:456", Data: map[string]interface{}{"firstName": "Jack"}},
+			{Issuer: "did:abc:1234", Subject: "did:abc:456", Data: map[string]interface{}{"firstName": "Jack"}},
+		}
+		for _, createRequest := range createRequests {
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createRequest))
+			err = credService.CreateCredential(newRequestContext(), httptest.NewRecorder(), req)
+			require.NoError(tt, err)
+		}
+
+		// no filters
+		req := httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials", nil)
+		w := httptest.NewRecorder()
+		err = credService.DeleteCredentials(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "must use at least one of the following query parameters: issuer, subject, schema")
+
+		// a dry run counts without deleting
+		req = httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials?issuer=did:abc:123&dryRun=true", nil)
+		err = credService.DeleteCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.DeleteCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 2, resp.Count)
+		assert.True(tt, resp.DryRun)
+
+		// so does any other way of saying true
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials?issuer=did:abc:123&dryRun=1", nil)
+		err = credService.DeleteCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 2, resp.Count)
+		assert.True(tt, resp.DryRun)
+
+		// a value which is not a boolean deletes nothing
+		for _, dryRun := range []string{"yes", "maybe"} {
+			req = httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials?issuer=did:abc:123&dryRun="+dryRun, nil)
+			err = credService.DeleteCredentials(newRequestContext(), httptest.NewRecorder(), req)
+			var safeErr *framework.SafeError
+			require.ErrorAs(tt, err, &safeErr)
+			assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+			assert.Contains(tt, err.Error(), "invalid dryRun<"+dryRun+">, must be true or false")
+		}
+
+		// both filters must match
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("https://ssi-service.com/v1/credentials?issuer=did:abc:123&schema=%s", createdSchema.ID), nil)
+		err = credService.DeleteCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 1, resp.Count)
+		assert.False(tt, resp.DryRun)
+
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials?issuer=did:abc:123", nil)
+		err = credService.DeleteCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 1, resp.Count)
+
+		// only the other issuer's credential remains
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials?issuer=did:abc:123", nil)
+		err = credService.GetCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var getResp router.GetCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&getResp)
+		assert.NoError(tt, err)
+		require.Len(tt, getResp.Credentials, 1)
+		assert.Equal(tt, "did:abc:1234", getResp.Credentials[0].Issuer)
+	})
+
+	t.Run("Test Delete Credentials By Subject", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		// two credentials about one subject, from different issuers, and one about a subject whose DID contains the first's
+		createRequests := []router.CreateCredentialRequest{
+			{Issuer: "did:abc:123", Subject: "did:abc:456", Data: map[string]interface{}{"firstName": "Jack"}},
+			{Issuer: "did:abc:789", Subject: "did:abc:456", Data: map[string]interface{}{"firstName": "Jack"}},
+			{Issuer: "did:abc:123", Subject: "did:abc:4567", Data: map[string]interface{}{"firstName": "Jill"}},
+		}
+		var subjectIDs []string
+		for _, createRequest := range createRequests {
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createRequest))
+			w := httptest.NewRecorder()
+			err = credService.CreateCredential(newRequestContext(), w, req)
+			require.NoError(tt, err)
+
+			var createResp router.CreateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&createResp)
+			require.NoError(tt, err)
+			if createRequest.Subject == "did:abc:456" {
+				subjectIDs = append(subjectIDs, createResp.Credential.ID)
+			}
+		}
+
+		// two aliases for the subject, and one for the other subject
+		aliasRequests := []router.SetSubjectAliasRequest{
+			{Alias: "jack", DID: "did:abc:456"},
+			{Alias: "jacky", DID: "did:abc:456"},
+			{Alias: "jill", DID: "did:abc:4567"},
+		}
+		for _, aliasRequest := range aliasRequests {
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/aliases", newRequestValue(tt, aliasRequest))
+			err = credService.SetSubjectAlias(newRequestContext(), httptest.NewRecorder(), req)
+			require.NoError(tt, err)
+		}
+
+		// an alias which is not registered
+		req := httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials?subject=bob", nil)
+		err = credService.DeleteCredentials(newRequestContext(), httptest.NewRecorder(), req)
+		var safeErr *framework.SafeError
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+
+		// a dry run lists the subject's credentials and aliases without deleting them
+		req = httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials?subject=did:abc:456&dryRun=true", nil)
+		w := httptest.NewRecorder()
+		err = credService.DeleteCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var resp router.DeleteCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 2, resp.Count)
+		assert.ElementsMatch(tt, subjectIDs, resp.IDs)
+		assert.Equal(tt, []string{"jack", "jacky"}, resp.Aliases)
+		assert.True(tt, resp.DryRun)
+
+		// the subject may be given by alias
+		req = httptest.NewRequest(http.MethodDelete, "https://ssi-service.com/v1/credentials?subject=jack", nil)
+		w = httptest.NewRecorder()
+		err = credService.DeleteCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		resp = router.DeleteCredentialsResponse{}
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 2, resp.Count)
+		assert.ElementsMatch(tt, subjectIDs, resp.IDs)
+		assert.Equal(tt, []string{"jack", "jacky"}, resp.Aliases)
+		assert.False(tt, resp.DryRun)
+
+		// only the other subject's alias remains
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/aliases", nil)
+		err = credService.GetSubjectAliases(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var aliasesResp router.GetSubjectAliasesResponse
+		err = json.NewDecoder(w.Body).Decode(&aliasesResp)
+		assert.NoError(tt, err)
+		require.Len(tt, aliasesResp.Aliases, 1)
+		assert.Equal(tt, "jill", aliasesResp.Aliases[0].Alias)
+
+		// only the other subject's credential remains
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials?issuer=did:abc:123", nil)
+		w = httptest.NewRecorder()
+		err = credService.GetCredentials(newRequestContext(), w, req)
+		assert.NoError(tt, err)
+
+		var getResp router.GetCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&getResp)
+		assert.NoError(tt, err)
+		require.Len(tt, getResp.Credentials, 1)
+		assert.Equal(tt, "did:abc:4567", getResp.Credentials[0].CredentialSubject["id"])
+	})
+
+	t.Run("Test Validate Credential", func(tt *testing.T) {
 		bolt, err := storage.NewBoltDB()
 
 		// remove the db file after the test
@@ -624,42 +2661,74 @@ func TestCredentialAPI(t *testing.T) {
 
 		credService := newCredentialService(tt, bolt)
 
-		w := httptest.NewRecorder()
-
-		subjectID := "did:abc:456"
-		createCredRequest := router.CreateCredentialRequest{
-			Issuer:  "did:abc:123",
-			Subject: subjectID,
-			Data: map[string]interface{}{
-				"firstName": "Jack",
-				"lastName":  "Dorsey",
+		simpleSchema := map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"firstName": map[string]interface{}{
+					"type": "string",
+				},
+				"age": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 0,
+				},
 			},
-			Expiry: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			"required":             []interface{}{"firstName"},
+			"additionalProperties": false,
 		}
-		requestValue := newRequestValue(tt, createCredRequest)
-		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
-		err = credService.CreateCredential(newRequestContext(), w, req)
+		schemaService, err := schema.NewSchemaService(config.SchemaServiceConfig{}, bolt)
+		require.NoError(tt, err)
+		createdSchema, err := schemaService.CreateSchema(schema.CreateSchemaRequest{Author: "did:test", Name: "test schema", Schema: simpleSchema})
+		require.NoError(tt, err)
+
+		// no schema
+		badRequest := router.ValidateCredentialRequest{Data: map[string]interface{}{"firstName": "Jack"}}
+		req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/validate", newRequestValue(tt, badRequest))
+		w := httptest.NewRecorder()
+		err = credService.ValidateCredential(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "exactly one of a schema reference or an inline schema must be provided")
+
+		// unknown schema
+		badRequest = router.ValidateCredentialRequest{SchemaID: "bad", Data: map[string]interface{}{"firstName": "Jack"}}
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/validate", newRequestValue(tt, badRequest))
+		err = credService.ValidateCredential(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "could not resolve schema: bad")
+
+		// valid data against a referenced schema
+		validRequest := router.ValidateCredentialRequest{SchemaID: createdSchema.ID, Data: map[string]interface{}{"firstName": "Jack", "age": 40}}
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/validate", newRequestValue(tt, validRequest))
+		err = credService.ValidateCredential(newRequestContext(), w, req)
 		assert.NoError(tt, err)
 
-		var resp router.CreateCredentialResponse
+		var resp router.ValidateCredentialResponse
 		err = json.NewDecoder(w.Body).Decode(&resp)
 		assert.NoError(tt, err)
+		assert.True(tt, resp.Valid)
+		assert.Empty(tt, resp.Errors)
 
-		// get credential by subject id
-		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credential?subject=%s", subjectID), nil)
-		err = credService.GetCredentials(newRequestContext(), w, req)
+		w = httptest.NewRecorder()
+
+		// invalid data against an inline schema
+		invalidRequest := router.ValidateCredentialRequest{Schema: simpleSchema, Data: map[string]interface{}{"age": -1}}
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/validate", newRequestValue(tt, invalidRequest))
+		err = credService.ValidateCredential(newRequestContext(), w, req)
 		assert.NoError(tt, err)
 
-		var getCredsResp router.GetCredentialsResponse
-		err = json.NewDecoder(w.Body).Decode(&getCredsResp)
+		err = json.NewDecoder(w.Body).Decode(&resp)
 		assert.NoError(tt, err)
-		assert.NotEmpty(tt, getCredsResp)
-		assert.Len(tt, getCredsResp.Credentials, 1)
-		assert.Equal(tt, resp.Credential.ID, getCredsResp.Credentials[0].ID)
-		assert.Equal(tt, resp.Credential.CredentialSubject[credsdk.VerifiableCredentialIDProperty], getCredsResp.Credentials[0].CredentialSubject[credsdk.VerifiableCredentialIDProperty])
+		assert.False(tt, resp.Valid)
+		assert.Len(tt, resp.Errors, 2)
+
+		fields := make(map[string]string)
+		for _, fieldErr := range resp.Errors {
+			fields[fieldErr.Field] = fieldErr.Type
+		}
+		assert.Equal(tt, "required", fields["(root)"])
+		assert.Equal(tt, "number_gte", fields["age"])
 	})
 
-	t.Run("Test Delete Credential", func(tt *testing.T) {
+	t.Run("Test Expand Credential", func(tt *testing.T) {
 		bolt, err := storage.NewBoltDB()
 
 		// remove the db file after the test
@@ -670,57 +2739,608 @@ func TestCredentialAPI(t *testing.T) {
 
 		credService := newCredentialService(tt, bolt)
 
-		createCredRequest := router.CreateCredentialRequest{
-			Issuer:  "did:abc:123",
-			Subject: "did:abc:456",
-			Data: map[string]interface{}{
-				"firstName": "Jack",
-				"lastName":  "Dorsey",
+		// missing credential
+		badRequest := router.ExpandCredentialRequest{}
+		req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/expand", newRequestValue(tt, badRequest))
+		w := httptest.NewRecorder()
+		err = credService.ExpandCredential(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid expand credential request")
+
+		// an inline context defines name, but not nickname or the Person type
+		expandRequest := router.ExpandCredentialRequest{
+			Credential: map[string]interface{}{
+				"@context": map[string]interface{}{"name": "http://schema.org/name"},
+				"@type":    "Person",
+				"name":     "Satoshi",
+				"nickname": "Sats",
 			},
-			Expiry: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
 		}
-		requestValue := newRequestValue(tt, createCredRequest)
-		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", requestValue)
-		w := httptest.NewRecorder()
-		err = credService.CreateCredential(newRequestContext(), w, req)
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/expand", newRequestValue(tt, expandRequest))
+		err = credService.ExpandCredential(newRequestContext(), w, req)
 		assert.NoError(tt, err)
 
-		var resp router.CreateCredentialResponse
+		var resp router.ExpandCredentialResponse
 		err = json.NewDecoder(w.Body).Decode(&resp)
 		assert.NoError(tt, err)
+		assert.Equal(tt, []string{"Person", "nickname"}, resp.UndefinedTerms)
+		require.Len(tt, resp.Expanded, 1)
+		expanded, ok := resp.Expanded[0].(map[string]interface{})
+		require.True(tt, ok)
+		assert.Contains(tt, expanded, "http://schema.org/name")
+		assert.NotContains(tt, expanded, "nickname")
+	})
 
-		w.Flush()
+	t.Run("Test Match Credentials", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
 
-		// get credential by id
-		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s", resp.Credential.ID), nil)
-		err = credService.GetCredential(newRequestContextWithParams(map[string]string{"id": resp.Credential.ID}), w, req)
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		newCredential := func(subject map[string]interface{}) credsdk.VerifiableCredential {
+			return credsdk.VerifiableCredential{
+				Context:           []string{"https://www.w3.org/2018/credentials/v1"},
+				ID:                uuid.NewString(),
+				Type:              []string{"VerifiableCredential"},
+				Issuer:            "did:abc:123",
+				IssuanceDate:      time.Now().Format(time.RFC3339),
+				CredentialSubject: subject,
+			}
+		}
+		adult := newCredential(map[string]interface{}{"id": "did:abc:456", "age": 21, "license": "A-123"})
+		minor := newCredential(map[string]interface{}{"id": "did:abc:789", "age": 16})
+		unrelated := newCredential(map[string]interface{}{"id": "did:abc:789", "nickname": "Sats"})
+
+		definition := exchange.PresentationDefinition{
+			ID: "driver",
+			InputDescriptors: []exchange.InputDescriptor{
+				{
+					ID: "age",
+					Constraints: &exchange.Constraints{
+						Fields: []exchange.Field{{
+							ID:     "age-field",
+							Path:   []string{"$.credentialSubject.age"},
+							Filter: &exchange.Filter{Type: "number", Minimum: 18},
+						}},
+					},
+				},
+				{
+					ID: "license",
+					Constraints: &exchange.Constraints{
+						Fields: []exchange.Field{{
+							Path:   []string{"$.credentialSubject.licenseNumber", "$.credentialSubject.license"},
+							Filter: &exchange.Filter{Type: "string"},
+						}},
+					},
+				},
+			},
+		}
+
+		matchCredentials := func(matchRequest router.MatchCredentialsRequest) (*router.MatchCredentialsResponse, error) {
+			req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/match", newRequestValue(tt, matchRequest))
+			w := httptest.NewRecorder()
+			if err := credService.MatchCredentials(newRequestContext(), w, req); err != nil {
+				return nil, err
+			}
+			var resp router.MatchCredentialsResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			return &resp, err
+		}
+
+		// missing credentials
+		_, err = matchCredentials(router.MatchCredentialsRequest{PresentationDefinition: definition})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "invalid match credentials request")
+
+		resp, err := matchCredentials(router.MatchCredentialsRequest{
+			PresentationDefinition: definition,
+			Credentials:            []credsdk.VerifiableCredential{adult, minor, unrelated},
+		})
+		assert.NoError(tt, err)
+		assert.True(tt, resp.Satisfied)
+		require.Len(tt, resp.Descriptors, 2)
+
+		age := resp.Descriptors[0]
+		assert.Equal(tt, "age", age.ID)
+		assert.True(tt, age.Satisfied)
+		assert.Equal(tt, []router.CredentialMatch{
+			{Index: 0, Match: true},
+			{Index: 1, Field: "age-field", Reason: "no value passes the filter"},
+			{Index: 2, Field: "age-field", Reason: "no value at any path"},
+		}, age.Credentials)
+
+		// the second path is tried when the first selects nothing
+		license := resp.Descriptors[1]
+		assert.True(tt, license.Satisfied)
+		assert.True(tt, license.Credentials[0].Match)
+		assert.Equal(tt, "$.credentialSubject.licenseNumber, $.credentialSubject.license", license.Credentials[1].Field)
+
+		// without the adult's credential, neither descriptor is satisfied
+		resp, err = matchCredentials(router.MatchCredentialsRequest{
+			PresentationDefinition: definition,
+			Credentials:            []credsdk.VerifiableCredential{minor, unrelated},
+		})
+		assert.NoError(tt, err)
+		assert.False(tt, resp.Satisfied)
+		assert.False(tt, resp.Descriptors[0].Satisfied)
+		assert.False(tt, resp.Descriptors[1].Satisfied)
+
+		// a filter is evaluated as the JSON Schema it was sent as, including keywords exchange.Filter cannot hold
+		rawMatchRequest := map[string]interface{}{
+			"presentationDefinition": map[string]interface{}{
+				"id": "membership",
+				"input_descriptors": []interface{}{map[string]interface{}{
+					"id": "member",
+					"constraints": map[string]interface{}{
+						"fields": []interface{}{map[string]interface{}{
+							"path":   []string{"$.type"},
+							"filter": map[string]interface{}{"type": "array", "contains": map[string]interface{}{"const": "MembershipCredential"}},
+						}},
+					},
+				}},
+			},
+			"credentials": []credsdk.VerifiableCredential{adult},
+		}
+		req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/match", newRequestValue(tt, rawMatchRequest))
+		w := httptest.NewRecorder()
+		err = credService.MatchCredentials(newRequestContext(), w, req)
 		assert.NoError(tt, err)
 
-		var getCredResp router.GetCredentialResponse
-		err = json.NewDecoder(w.Body).Decode(&getCredResp)
+		var rawResp router.MatchCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&rawResp)
 		assert.NoError(tt, err)
-		assert.NotEmpty(tt, getCredResp)
-		assert.Equal(tt, resp.Credential.ID, getCredResp.ID)
+		assert.False(tt, rawResp.Satisfied)
+		require.Len(tt, rawResp.Descriptors, 1)
+		assert.Equal(tt, []router.CredentialMatch{{Index: 0, Field: "$.type", Reason: "no value passes the filter"}}, rawResp.Descriptors[0].Credentials)
+
+		// features which are not evaluated are rejected rather than ignored
+		definition.InputDescriptors[0].Constraints.SubjectIsIssuer = exchange.Required.Ptr()
+		_, err = matchCredentials(router.MatchCredentialsRequest{
+			PresentationDefinition: definition,
+			Credentials:            []credsdk.VerifiableCredential{adult},
+		})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "presentation definition uses unsupported feature(s): relational constraints")
+	})
 
-		w.Flush()
+	t.Run("Test Strict Validation", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
 
-		// delete it
-		req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s", resp.Credential.ID), nil)
-		err = credService.DeleteCredential(newRequestContextWithParams(map[string]string{"id": resp.Credential.ID}), w, req)
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		serviceConfig := config.CredentialServiceConfig{StrictValidation: true}
+		credentialService, err := credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService, err := router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+
+		// a schema which allows additional properties
+		permissiveSchema := map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"firstName": map[string]interface{}{
+					"type": "string",
+				},
+				"address": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"country": map[string]interface{}{
+							"type": "string",
+						},
+					},
+				},
+			},
+			"additionalProperties": true,
+		}
+		schemaService, err := schema.NewSchemaService(config.SchemaServiceConfig{}, bolt)
+		require.NoError(tt, err)
+		strictSchema, err := schemaService.CreateSchema(schema.CreateSchemaRequest{Author: "did:test", Name: "strict", Schema: permissiveSchema})
+		require.NoError(tt, err)
+		notStrict := false
+		lenientSchema, err := schemaService.CreateSchema(schema.CreateSchemaRequest{Author: "did:test", Name: "lenient", Schema: permissiveSchema, StrictValidation: &notStrict})
+		require.NoError(tt, err)
+
+		validate := func(schemaID string) router.ValidateCredentialResponse {
+			request := router.ValidateCredentialRequest{
+				SchemaID: schemaID,
+				Data: map[string]interface{}{
+					"firstName": "Jack",
+					"nickname":  "JD",
+					"address": map[string]interface{}{
+						"country": "US",
+						"zip":     "94107",
+					},
+				},
+			}
+			req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/validate", newRequestValue(tt, request))
+			w := httptest.NewRecorder()
+			err := credService.ValidateCredential(newRequestContext(), w, req)
+			assert.NoError(tt, err)
+
+			var resp router.ValidateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(tt, err)
+			return resp
+		}
+
+		// by default, the unexpected properties are each reported by name
+		resp := validate(strictSchema.ID)
+		assert.False(tt, resp.Valid)
+		assert.Len(tt, resp.Errors, 2)
+
+		var messages []string
+		for _, fieldErr := range resp.Errors {
+			assert.Equal(tt, "additional_property_not_allowed", fieldErr.Type)
+			messages = append(messages, fieldErr.Message)
+		}
+		assert.ElementsMatch(tt, []string{"Additional property nickname is not allowed", "Additional property zip is not allowed"}, messages)
+
+		// the schema's own setting takes precedence
+		resp = validate(lenientSchema.ID)
+		assert.True(tt, resp.Valid)
+
+		// credentials are validated the same way when issued
+		issue := func(schemaID string, data map[string]interface{}) error {
+			request := router.CreateCredentialRequest{
+				Issuer:  "did:abc:123",
+				Subject: "did:abc:456",
+				Schema:  schemaID,
+				Data:    data,
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, request))
+			return credService.CreateCredential(newRequestContext(), httptest.NewRecorder(), req)
+		}
+		extraData := map[string]interface{}{"firstName": "Jack", "nickname": "JD"}
+		err = issue(strictSchema.ID, extraData)
+		var safeErr *framework.SafeError
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		assert.Equal(tt, []framework.FieldError{{Field: "data", Error: "Additional property nickname is not allowed"}}, safeErr.Fields)
+
+		err = issue(lenientSchema.ID, extraData)
 		assert.NoError(tt, err)
 
-		w.Flush()
+		// as is data which does not conform to the schema at all
+		err = issue(lenientSchema.ID, map[string]interface{}{"firstName": 7})
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		require.Len(tt, safeErr.Fields, 1)
+		assert.Equal(tt, "data.firstName", safeErr.Fields[0].Field)
+
+		// a schema the schema service does not know of cannot be validated against, so by default is rejected
+		err = issue("https://example.com/schemas/unknown.json", extraData)
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+		assert.Equal(tt, []framework.FieldError{{Field: "schema", Error: "schema not found: https://example.com/schemas/unknown.json"}}, safeErr.Fields)
+	})
 
-		// get it back
-		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/credentials/%s", resp.Credential.ID), nil)
-		err = credService.GetCredential(newRequestContextWithParams(map[string]string{"id": resp.Credential.ID}), w, req)
+	t.Run("Test Schema Not Found Policy", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		var buf bytes.Buffer
+		logrus.SetOutput(&buf)
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			logrus.SetOutput(os.Stderr)
+			logrus.SetFormatter(&logrus.TextFormatter{})
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		_, err = credential.NewCredentialService(config.CredentialServiceConfig{SchemaNotFoundPolicy: "ignore"}, bolt, newTestKeyStoreService(tt, bolt))
 		assert.Error(tt, err)
-		assert.Contains(tt, err.Error(), fmt.Sprintf("could not get credential with id: %s", resp.Credential.ID))
+		assert.Contains(tt, err.Error(), "invalid schema not found policy<ignore>, must be one of: fail, warn, info")
+
+		unknownSchema := "https://example.com/schemas/unknown.json"
+		issueWithPolicy := func(policy string) error {
+			serviceConfig := config.CredentialServiceConfig{SchemaNotFoundPolicy: policy}
+			credentialService, err := credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+			require.NoError(tt, err)
+			credService, err := router.NewCredentialRouter(credentialService)
+			require.NoError(tt, err)
+
+			createCredRequest := router.CreateCredentialRequest{
+				Issuer:  "did:abc:123",
+				Subject: "did:abc:456",
+				Schema:  unknownSchema,
+				Data:    map[string]interface{}{"firstName": "Jack"},
+			}
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+			return credService.CreateCredential(newRequestContext(), httptest.NewRecorder(), req)
+		}
+		// the level the service logged the unknown schema at, if it did
+		loggedLevel := func() string {
+			defer buf.Reset()
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var entry map[string]interface{}
+				require.NoError(tt, json.Unmarshal([]byte(line), &entry))
+				msg, _ := entry["msg"].(string)
+				errMsg, _ := entry["error"].(string)
+				if strings.Contains(msg+errMsg, "is not known to the schema service") {
+					return entry["level"].(string)
+				}
+			}
+			return ""
+		}
+
+		// fail, the default, rejects the credential
+		for _, policy := range []string{"", credential.SchemaNotFoundFail} {
+			err = issueWithPolicy(policy)
+			var safeErr *framework.SafeError
+			require.ErrorAs(tt, err, &safeErr)
+			assert.Equal(tt, http.StatusBadRequest, safeErr.StatusCode)
+			assert.Equal(tt, []framework.FieldError{{Field: "schema", Error: "schema not found: " + unknownSchema}}, safeErr.Fields)
+			assert.Equal(tt, "error", loggedLevel())
+		}
+
+		// warn issues the credential, logging a warning
+		err = issueWithPolicy(credential.SchemaNotFoundWarn)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "warning", loggedLevel())
+
+		// info issues the credential, logging at the info level
+		err = issueWithPolicy(credential.SchemaNotFoundInfo)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "info", loggedLevel())
+	})
+
+	t.Run("Test Strict Validation Of Composed Schemas", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		serviceConfig := config.CredentialServiceConfig{StrictValidation: true}
+		credentialService, err := credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService, err := router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+		schemaService, err := schema.NewSchemaService(config.SchemaServiceConfig{}, bolt)
+		require.NoError(tt, err)
+
+		stringProperty := map[string]interface{}{"type": "string"}
+		// properties defined across the branches of a composition
+		composedSchema, err := schemaService.CreateSchema(schema.CreateSchemaRequest{Author: "did:test", Name: "composed", Schema: map[string]interface{}{
+			"type": "object",
+			"allOf": []interface{}{
+				map[string]interface{}{"properties": map[string]interface{}{"a": stringProperty}},
+				map[string]interface{}{"properties": map[string]interface{}{"b": stringProperty}},
+			},
+		}})
+		require.NoError(tt, err)
+		// additional properties constrained by a sub-schema
+		constrainedSchema, err := schemaService.CreateSchema(schema.CreateSchemaRequest{Author: "did:test", Name: "constrained", Schema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{"a": stringProperty},
+			"additionalProperties": stringProperty,
+		}})
+		require.NoError(tt, err)
+
+		validate := func(schemaID string, data map[string]interface{}) router.ValidateCredentialResponse {
+			request := router.ValidateCredentialRequest{SchemaID: schemaID, Data: data}
+			req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/credentials/validate", newRequestValue(tt, request))
+			w := httptest.NewRecorder()
+			err := credService.ValidateCredential(newRequestContext(), w, req)
+			assert.NoError(tt, err)
+
+			var resp router.ValidateCredentialResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(tt, err)
+			return resp
+		}
+
+		// properties from every branch are allowed
+		resp := validate(composedSchema.ID, map[string]interface{}{"a": "A", "b": "B"})
+		assert.True(tt, resp.Valid)
+		assert.Empty(tt, resp.Errors)
+
+		// and only those properties
+		resp = validate(composedSchema.ID, map[string]interface{}{"a": "A", "b": "B", "c": "C"})
+		assert.False(tt, resp.Valid)
+		require.Len(tt, resp.Errors, 1)
+		assert.Equal(tt, "Additional property c is not allowed", resp.Errors[0].Message)
+
+		// the branches still constrain their own properties
+		resp = validate(composedSchema.ID, map[string]interface{}{"a": "A", "b": 2})
+		assert.False(tt, resp.Valid)
+		require.NotEmpty(tt, resp.Errors)
+		assert.Equal(tt, "b", resp.Errors[0].Field)
+		assert.Equal(tt, "invalid_type", resp.Errors[0].Type)
+
+		// an additionalProperties sub-schema is kept as it is
+		resp = validate(constrainedSchema.ID, map[string]interface{}{"a": "A", "c": "C"})
+		assert.True(tt, resp.Valid)
+		resp = validate(constrainedSchema.ID, map[string]interface{}{"a": "A", "c": 3})
+		assert.False(tt, resp.Valid)
+		require.Len(tt, resp.Errors, 1)
+		assert.Equal(tt, "c", resp.Errors[0].Field)
+	})
+
+	t.Run("Test Issue Credential From Schema Form", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		schemaService := newSchemaService(tt, bolt)
+		serviceConfig := config.CredentialServiceConfig{StrictValidation: true}
+		credentialService, err := credential.NewCredentialService(serviceConfig, bolt, newTestKeyStoreService(tt, bolt))
+		require.NoError(tt, err)
+		credService, err := router.NewCredentialRouter(credentialService)
+		require.NoError(tt, err)
+
+		// nested objects through $refs, including one composed with allOf, and properties composed with allOf
+		membershipSchema := map[string]interface{}{
+			"type": "object",
+			"definitions": map[string]interface{}{
+				"address": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"city":     map[string]interface{}{"type": "string"},
+						"zip/code": map[string]interface{}{"type": "string"},
+					},
+					"required": []interface{}{"city"},
+				},
+				"organization": map[string]interface{}{
+					"type": "object",
+					"allOf": []interface{}{
+						map[string]interface{}{"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}}},
+						map[string]interface{}{"properties": map[string]interface{}{"size": map[string]interface{}{"type": "integer"}}},
+					},
+				},
+			},
+			"allOf": []interface{}{
+				map[string]interface{}{
+					"properties": map[string]interface{}{
+						"email":    map[string]interface{}{"type": "string", "format": "email"},
+						"employer": map[string]interface{}{"$ref": "#/definitions/organization"},
+					},
+					"required": []interface{}{"email"},
+				},
+			},
+			"properties": map[string]interface{}{
+				"address":   map[string]interface{}{"$ref": "#/definitions/address"},
+				"birthDate": map[string]interface{}{"type": []interface{}{"null", "string"}, "format": "date"},
+				"level":     map[string]interface{}{"type": "string", "enum": []interface{}{"gold", "silver"}},
+				"member":    map[string]interface{}{"type": "boolean"},
+			},
+			"required": []interface{}{"address", "level"},
+		}
+		schemaRequest := router.CreateSchemaRequest{Author: "did:test", Name: "membership", Schema: membershipSchema}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/schemas", newRequestValue(tt, schemaRequest))
+		w := httptest.NewRecorder()
+		err = schemaService.CreateSchema(newRequestContext(), w, req)
+		require.NoError(tt, err)
+		var schemaResp router.CreateSchemaResponse
+		err = json.NewDecoder(w.Body).Decode(&schemaResp)
+		require.NoError(tt, err)
+
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/schemas/%s/form", schemaResp.ID), nil)
+		w = httptest.NewRecorder()
+		err = schemaService.GetSchemaForm(newRequestContextWithParams(map[string]string{"id": schemaResp.ID}), w, req)
+		require.NoError(tt, err)
+		var form router.GetSchemaFormResponse
+		err = json.NewDecoder(w.Body).Decode(&form)
+		require.NoError(tt, err)
+		require.Len(tt, form.Fields, 8)
+
+		// fill in every field of the form, setting each value at the field's pointer
+		data := make(map[string]interface{})
+		for _, field := range form.Fields {
+			var value interface{}
+			switch {
+			case len(field.Enum) > 0:
+				value = field.Enum[0]
+			case field.Format == "date":
+				value = "1990-01-01"
+			case field.Format == "email":
+				value = "jack@example.com"
+			case field.Type == "integer":
+				value = 10
+			case field.Type == "boolean":
+				value = true
+			default:
+				value = "filled"
+			}
+			var tokens []string
+			for _, token := range strings.Split(field.Pointer, "/")[1:] {
+				tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+			}
+			current := data
+			for _, token := range tokens[:len(tokens)-1] {
+				next, ok := current[token].(map[string]interface{})
+				if !ok {
+					next = make(map[string]interface{})
+					current[token] = next
+				}
+				current = next
+			}
+			current[tokens[len(tokens)-1]] = value
+		}
+
+		// the filled in data is issued under strict validation
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:  "did:abc:123",
+			Subject: "did:abc:456",
+			Schema:  schemaResp.ID,
+			Data:    data,
+		}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+		w = httptest.NewRecorder()
+		err = credService.CreateCredential(newRequestContext(), w, req)
+		require.NoError(tt, err)
+
+		var resp router.CreateCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		require.NoError(tt, err)
+		subject := resp.Credential.CredentialSubject
+		assert.Equal(tt, map[string]interface{}{"city": "filled", "zip/code": "filled"}, subject["address"])
+		assert.Equal(tt, map[string]interface{}{"name": "filled", "size": float64(10)}, subject["employer"])
+		assert.Equal(tt, "jack@example.com", subject["email"])
+		assert.Equal(tt, "gold", subject["level"])
+		assert.Equal(tt, true, subject["member"])
+	})
+
+	t.Run("Test Service Logs Carry Request ID", func(tt *testing.T) {
+		bolt, err := storage.NewBoltDB()
+
+		var buf bytes.Buffer
+		logrus.SetOutput(&buf)
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+
+		// remove the db file after the test
+		tt.Cleanup(func() {
+			logrus.SetOutput(os.Stderr)
+			logrus.SetFormatter(&logrus.TextFormatter{})
+			_ = bolt.Close()
+			_ = os.Remove(storage.DBFile)
+		})
+
+		credService := newCredentialService(tt, bolt)
+
+		ctx := context.WithValue(context.Background(), framework.KeyRequestState, &framework.RequestState{
+			TraceID: "1234",
+			Now:     time.Now(),
+		})
+		ctx = httptreemux.AddParamsToContext(ctx, map[string]string{"id": "bad"})
+		req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/credentials/bad", nil)
+		err = credService.GetCredential(ctx, httptest.NewRecorder(), req)
+		assert.Error(tt, err)
+
+		// the service logs the error it returns along with the ID of the request it was called for
+		var serviceEntry map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry map[string]interface{}
+			require.NoError(tt, json.Unmarshal([]byte(line), &entry))
+			if entry["msg"] == "could not get credential: bad" {
+				serviceEntry = entry
+			}
+		}
+		require.NotNil(tt, serviceEntry)
+		assert.Equal(tt, "1234", serviceEntry["requestId"])
 	})
+
 }
 
 func newCredentialService(t *testing.T, bolt *storage.BoltDB) *router.CredentialRouter {
-	credentialService, err := credential.NewCredentialService(config.CredentialServiceConfig{}, bolt)
+	credentialService, err := credential.NewCredentialService(config.CredentialServiceConfig{}, bolt, newTestKeyStoreService(t, bolt))
 	require.NoError(t, err)
 	require.NotEmpty(t, credentialService)
 
@@ -780,6 +3400,29 @@ func TestKeyStoreAPI(t *testing.T) {
 		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/keys", requestValue)
 		err = keyStoreService.StoreKey(newRequestContext(), w, req)
 		assert.NoError(tt, err)
+
+		// a key with the same ID is a conflict, pointing at the existing key
+		storeKeyRequest.Controller = "did:test:someone-else"
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/keys", newRequestValue(tt, storeKeyRequest))
+		w = httptest.NewRecorder()
+		err = keyStoreService.StoreKey(newRequestContext(), w, req)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "key already exists with id: did:test:me#key-1")
+		var safeErr *framework.SafeError
+		require.ErrorAs(tt, err, &safeErr)
+		assert.Equal(tt, http.StatusConflict, safeErr.StatusCode)
+		assert.Equal(tt, "/v1/keys/did:test:me%23key-1", w.Header().Get("Location"))
+
+		// the existing key is kept
+		getRecorder := httptest.NewRecorder()
+		getReq := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/keys/did:test:me%23key-1", nil)
+		err = keyStoreService.GetKeyDetails(newRequestContextWithParams(map[string]string{"id": "did:test:me#key-1"}), getRecorder, getReq)
+		assert.NoError(tt, err)
+
+		var resp router.GetKeyDetailsResponse
+		err = json.NewDecoder(getRecorder.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "did:test:me", resp.Controller)
 	})
 
 	t.Run("Test Get Key Details", func(tt *testing.T) {
@@ -832,10 +3475,7 @@ func TestKeyStoreAPI(t *testing.T) {
 }
 
 func newKeyStoreService(t *testing.T, bolt *storage.BoltDB) *router.KeyStoreRouter {
-	serviceConfig := config.KeyStoreServiceConfig{ServiceKeyPassword: "test-password"}
-	keyStoreService, err := keystore.NewKeyStoreService(serviceConfig, bolt)
-	require.NoError(t, err)
-	require.NotEmpty(t, keyStoreService)
+	keyStoreService := newTestKeyStoreService(t, bolt)
 
 	// create router for service
 	keyStoreRouter, err := router.NewKeyStoreRouter(keyStoreService)
@@ -845,6 +3485,14 @@ func newKeyStoreService(t *testing.T, bolt *storage.BoltDB) *router.KeyStoreRout
 	return keyStoreRouter
 }
 
+func newTestKeyStoreService(t *testing.T, bolt *storage.BoltDB) *keystore.Service {
+	serviceConfig := config.KeyStoreServiceConfig{ServiceKeyPassword: "test-password"}
+	keyStoreService, err := keystore.NewKeyStoreService(serviceConfig, bolt)
+	require.NoError(t, err)
+	require.NotEmpty(t, keyStoreService)
+	return keyStoreService
+}
+
 func newRequestValue(t *testing.T, data interface{}) io.Reader {
 	dataBytes, err := json.Marshal(data)
 	require.NoError(t, err)
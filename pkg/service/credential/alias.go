@@ -0,0 +1,121 @@
+package credential
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+	credstorage "github.com/tbd54566975/ssi-service/pkg/service/credential/storage"
+)
+
+// SubjectAliasNotFoundError is returned when no alias is registered with a name, including when a subject is
+// given by an alias which cannot be resolved
+type SubjectAliasNotFoundError struct {
+	Alias string
+}
+
+func (e SubjectAliasNotFoundError) Error() string {
+	return fmt.Sprintf("no subject alias registered with name: %s", e.Alias)
+}
+
+// InvalidSubjectAliasError is returned when an alias cannot be registered for a DID
+type InvalidSubjectAliasError struct {
+	Reason string
+}
+
+func (e InvalidSubjectAliasError) Error() string {
+	return fmt.Sprintf("invalid subject alias: %s", e.Reason)
+}
+
+// SetSubjectAlias registers an alias for a subject's DID, replacing any DID it was registered for before. Credentials
+// already issued by the alias keep the DID it resolved to at the time.
+func (s Service) SetSubjectAlias(request SetSubjectAliasRequest) (*GetSubjectAliasResponse, error) {
+
+	s.log.Debugf("setting subject alias<%s> for DID: %s", util.SanitizeLog(request.Alias), util.SanitizeLog(request.DID))
+
+	switch {
+	case strings.TrimSpace(request.Alias) == "":
+		return nil, s.log.LoggingError(InvalidSubjectAliasError{Reason: "alias cannot be empty"})
+	case strings.HasPrefix(request.Alias, util.DIDPrefix):
+		reason := fmt.Sprintf("alias<%s> cannot be a DID", request.Alias)
+		return nil, s.log.LoggingError(InvalidSubjectAliasError{Reason: reason})
+	}
+	if err := util.ValidateDID(request.DID); err != nil {
+		reason := fmt.Sprintf("alias<%s> must be for a valid DID: %s", request.Alias, err.Error())
+		return nil, s.log.LoggingError(InvalidSubjectAliasError{Reason: reason})
+	}
+
+	alias := credstorage.SubjectAlias{Alias: request.Alias, DID: request.DID}
+	if err := s.storage.StoreSubjectAlias(alias); err != nil {
+		errMsg := fmt.Sprintf("could not store subject alias: %s", request.Alias)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	return &GetSubjectAliasResponse{Alias: alias.Alias, DID: alias.DID}, nil
+}
+
+func (s Service) GetSubjectAlias(request GetSubjectAliasRequest) (*GetSubjectAliasResponse, error) {
+	alias, err := s.storage.GetSubjectAlias(request.Alias)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get subject alias: %s", request.Alias)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	if alias == nil {
+		return nil, s.log.LoggingError(SubjectAliasNotFoundError{Alias: request.Alias})
+	}
+	return &GetSubjectAliasResponse{Alias: alias.Alias, DID: alias.DID}, nil
+}
+
+// GetSubjectAliases gets every registered alias, ordered by name
+func (s Service) GetSubjectAliases() (*GetSubjectAliasesResponse, error) {
+	gotAliases, err := s.storage.GetSubjectAliases()
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not get subject aliases")
+	}
+	aliases := make([]GetSubjectAliasResponse, 0, len(gotAliases))
+	for _, alias := range gotAliases {
+		aliases = append(aliases, GetSubjectAliasResponse{Alias: alias.Alias, DID: alias.DID})
+	}
+	return &GetSubjectAliasesResponse{Aliases: aliases}, nil
+}
+
+func (s Service) DeleteSubjectAlias(request DeleteSubjectAliasRequest) error {
+
+	s.log.Debugf("deleting subject alias: %s", util.SanitizeLog(request.Alias))
+
+	if _, err := s.GetSubjectAlias(GetSubjectAliasRequest{Alias: request.Alias}); err != nil {
+		return err
+	}
+	if err := s.storage.DeleteSubjectAlias(request.Alias); err != nil {
+		errMsg := fmt.Sprintf("could not delete subject alias: %s", request.Alias)
+		return s.log.LoggingErrorMsg(err, errMsg)
+	}
+	return nil
+}
+
+// resolveSubject gives the DID of a subject given either by DID or by alias, along with the alias if there was one.
+// A subject which does not begin with the DID prefix is taken to be an alias.
+func (s Service) resolveSubject(subject string) (did string, alias string, err error) {
+	if strings.HasPrefix(subject, util.DIDPrefix) {
+		return subject, "", nil
+	}
+	resolved, err := s.GetSubjectAlias(GetSubjectAliasRequest{Alias: subject})
+	if err != nil {
+		return "", "", err
+	}
+	return resolved.DID, subject, nil
+}
+
+// subjectAliases gives the names of every alias registered for a DID
+func (s Service) subjectAliases(did string) ([]string, error) {
+	gotAliases, err := s.storage.GetSubjectAliases()
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not get subject aliases")
+	}
+	var aliases []string
+	for _, alias := range gotAliases {
+		if alias.DID == did {
+			aliases = append(aliases, alias.Alias)
+		}
+	}
+	return aliases, nil
+}
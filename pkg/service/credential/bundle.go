@@ -0,0 +1,70 @@
+package credential
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-json"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+)
+
+// GetCredentialBundle gets a credential along with the schema it references and its issuer's DID document, so that a
+// third party can verify it without calling back to the service. Bundles larger than MaxCredentialBundleSize are
+// rejected rather than returned.
+func (s Service) GetCredentialBundle(request GetCredentialBundleRequest) (*GetCredentialBundleResponse, error) {
+
+	s.log.Debugf("getting credential bundle: %s", util.SanitizeLog(request.ID))
+
+	gotCred, err := s.GetCredential(GetCredentialRequest{ID: request.ID})
+	if err != nil {
+		return nil, err
+	}
+	cred := gotCred.Credential
+	bundle := GetCredentialBundleResponse{Credential: cred}
+
+	if cred.CredentialSchema != nil {
+		gotSchema, err := s.schemaStorage.GetSchema(cred.CredentialSchema.ID)
+		if err != nil {
+			errMsg := fmt.Sprintf("could not resolve schema<%s> of credential: %s", cred.CredentialSchema.ID, request.ID)
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
+		}
+		bundle.Schema = &gotSchema.Schema
+	}
+
+	issuer, err := credentialIssuer(cred.Issuer)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get issuer of credential: %s", request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	issuerDID, resolution, err := s.didResolver.resolve(s.log, issuer)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not resolve issuer<%s> of credential: %s", issuer, request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	bundle.IssuerDID = *issuerDID
+	bundle.IssuerDIDResolution = *resolution
+
+	bundleBytes, err := json.Marshal(bundle)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not marshal bundle of credential: %s", request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	if len(bundleBytes) > MaxCredentialBundleSize {
+		errMsg := fmt.Sprintf("bundle of credential<%s> is %d bytes, more than the maximum of %d", request.ID, len(bundleBytes), MaxCredentialBundleSize)
+		return nil, s.log.LoggingNewError(errMsg)
+	}
+	return &bundle, nil
+}
+
+// credentialIssuer gets the DID of a credential's issuer, which is either a URI or an object with an id property
+func credentialIssuer(issuer interface{}) (string, error) {
+	switch i := issuer.(type) {
+	case string:
+		return i, nil
+	case map[string]interface{}:
+		if id, ok := i["id"].(string); ok {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported issuer: %v", issuer)
+}
@@ -0,0 +1,50 @@
+package credential
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/goccy/go-json"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+)
+
+const (
+	// JCSCanonicalization is the JSON Canonicalization Scheme, RFC 8785
+	JCSCanonicalization string = "JCS"
+)
+
+// GetCanonicalCredential gets a credential serialized in canonical form, along with the SHA-256 digest of that
+// serialization. The canonical form of a credential never changes, so its digest is stable across calls.
+//
+// JCS is used rather than RDF dataset canonicalization (URDNA2015) since the latter drops any claims which are not
+// defined by the credential's contexts, so two credentials differing only in such claims would share a digest.
+func (s Service) GetCanonicalCredential(request GetCredentialRequest) (*GetCanonicalCredentialResponse, error) {
+
+	s.log.Debugf("getting canonical credential: %s", util.SanitizeLog(request.ID))
+
+	gotCred, err := s.GetCredential(request)
+	if err != nil {
+		return nil, err
+	}
+
+	credBytes, err := json.Marshal(gotCred.Credential)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not marshal credential: %s", request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	canonical, err := util.CanonicalizeJSON(credBytes)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not canonicalize credential: %s", request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	digest := sha256.Sum256(canonical)
+	return &GetCanonicalCredentialResponse{
+		ID:               gotCred.Credential.ID,
+		Canonicalization: JCSCanonicalization,
+		Credential:       canonical,
+		Digest:           hex.EncodeToString(digest[:]),
+	}, nil
+}
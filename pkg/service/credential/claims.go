@@ -0,0 +1,67 @@
+package credential
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingClaimsError is returned when credential data does not contain claims required by the service's config
+type MissingClaimsError struct {
+	Claims []string
+}
+
+func (m MissingClaimsError) Error() string {
+	return fmt.Sprintf("credential data is missing required claim(s): %s", strings.Join(m.Claims, ", "))
+}
+
+// mergeIssuerDefaults returns a copy of the data with the issuer's default claims added. Claims present in the
+// data are never overridden by a default.
+func mergeIssuerDefaults(defaults map[string]interface{}, data map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(defaults)+len(data))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range data {
+		merged[k] = v
+	}
+	return merged
+}
+
+// missingRequiredClaims returns each of the required claim paths which is absent or empty in the given data
+func missingRequiredClaims(required []string, data map[string]interface{}) []string {
+	var missing []string
+	for _, claim := range required {
+		if isEmptyClaim(lookupClaim(claim, data)) {
+			missing = append(missing, claim)
+		}
+	}
+	return missing
+}
+
+// lookupClaim resolves a '.' separated path of properties in the given data, returning nil if it is not present
+func lookupClaim(path string, data map[string]interface{}) interface{} {
+	var current interface{} = data
+	for _, property := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[property]
+	}
+	return current
+}
+
+func isEmptyClaim(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
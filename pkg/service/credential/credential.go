@@ -1,22 +1,51 @@
 package credential
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/TBD54566975/ssi-sdk/credential"
-	"github.com/sirupsen/logrus"
 
 	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/internal/util"
 	credstorage "github.com/tbd54566975/ssi-service/pkg/service/credential/storage"
+	didstorage "github.com/tbd54566975/ssi-service/pkg/service/did/storage"
 	"github.com/tbd54566975/ssi-service/pkg/service/framework"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+	schemastorage "github.com/tbd54566975/ssi-service/pkg/service/schema/storage"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
 
 type Service struct {
 	storage credstorage.Storage
-	config  config.CredentialServiceConfig
+	// used to resolve schemas referenced by credentials
+	schemaStorage schemastorage.Storage
+	// used to resolve the DID documents of issuers
+	didResolver *didResolver
+	// holds the keys issuers sign with
+	keyStore  *keystore.Service
+	keyMaxAge time.Duration
+	// the current time, used to determine whether credentials have expired
+	clock func() time.Time
+	// the layout credential timestamps are formatted with
+	timestampLayout string
+	// how often credentials past their storage TTL are purged
+	purgeInterval time.Duration
+	// the most credentials of a batch built at once
+	batchParallelism int
+	// held while a subject's credentials are counted against a limit and another is stored, shared by copies of
+	// the service
+	limitLocks *subjectLimitLocks
+	// what issuance does when a credential references a schema the schema service does not know
+	schemaNotFoundPolicy string
+	config               config.CredentialServiceConfig
+	// logs on behalf of the service, see WithLogger
+	log util.Logger
 }
 
 func (s Service) Type() framework.Type {
@@ -37,50 +66,321 @@ func (s Service) Config() config.CredentialServiceConfig {
 	return s.config
 }
 
-func NewCredentialService(config config.CredentialServiceConfig, s storage.ServiceStorage) (*Service, error) {
+func (s Service) Describe() map[string]interface{} {
+	return map[string]interface{}{
+		"formats":         []string{JSONLDFormat},
+		"encryptedAtRest": !s.config.Encryption.IsEmpty(),
+	}
+}
+
+func NewCredentialService(config config.CredentialServiceConfig, s storage.ServiceStorage, keyStore *keystore.Service) (*Service, error) {
+	if keyStore == nil {
+		return nil, util.LoggingNewError("could not instantiate the credential service without a key store")
+	}
 	credentialStorage, err := credstorage.NewCredentialStorage(s)
 	if err != nil {
 		errMsg := "could not instantiate storage for the credential service"
 		return nil, util.LoggingErrorMsg(err, errMsg)
 	}
+	if err := validateSubjectLimits(config.SubjectLimits); err != nil {
+		return nil, util.LoggingErrorMsg(err, "invalid credential service config")
+	}
+	keyMaxAge, err := parseKeyMaxAge(config.IssuerKeyMaxAge)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "invalid credential service config")
+	}
+	timestampLayout, err := timestampLayoutForPrecision(config.TimestampPrecision)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "invalid credential service config")
+	}
+	purgeInterval, err := parsePurgeInterval(config.StoragePurgeInterval)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "invalid credential service config")
+	}
+	schemaNotFoundPolicy, err := schemaNotFoundPolicyFor(config.SchemaNotFoundPolicy)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "invalid credential service config")
+	}
+	batchParallelism := config.BatchParallelism
+	if batchParallelism < 0 {
+		errMsg := fmt.Sprintf("invalid credential service config: batch parallelism cannot be negative: %d", batchParallelism)
+		return nil, util.LoggingNewError(errMsg)
+	}
+	if batchParallelism == 0 {
+		batchParallelism = runtime.NumCPU()
+	}
+	if !config.Encryption.IsEmpty() {
+		encryptedStorage, err := credstorage.NewEncryptedCredentialStorage(credentialStorage, config.Encryption.Keys, config.Encryption.ActiveKeyVersion)
+		if err != nil {
+			errMsg := "could not instantiate encrypted storage for the credential service"
+			return nil, util.LoggingErrorMsg(err, errMsg)
+		}
+		credentialStorage = encryptedStorage
+	}
+	schemaStorage, err := schemastorage.NewSchemaStorage(s)
+	if err != nil {
+		errMsg := "could not instantiate schema storage for the credential service"
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+	didStorage, err := didstorage.NewDIDStorage(s)
+	if err != nil {
+		errMsg := "could not instantiate DID storage for the credential service"
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+	didResolver, err := newDIDResolver(config.DIDResolution, didStorage)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "invalid credential service config")
+	}
 	return &Service{
-		storage: credentialStorage,
-		config:  config,
+		storage:              credentialStorage,
+		schemaStorage:        schemaStorage,
+		didResolver:          didResolver,
+		keyStore:             keyStore,
+		keyMaxAge:            keyMaxAge,
+		clock:                time.Now,
+		timestampLayout:      timestampLayout,
+		purgeInterval:        purgeInterval,
+		batchParallelism:     batchParallelism,
+		limitLocks:           newSubjectLimitLocks(),
+		schemaNotFoundPolicy: schemaNotFoundPolicy,
+		config:               config,
+		log:                  util.NewLogger(),
 	}, nil
 }
 
+// WithLogger gets a copy of the service which logs through the given logger, such as one carrying the ID of the
+// request the service is called for
+func (s Service) WithLogger(log util.Logger) *Service {
+	s.log = log
+	return &s
+}
+
 func (s Service) CreateCredential(request CreateCredentialRequest) (*CreateCredentialResponse, error) {
 
-	logrus.Debugf("creating credential: %+v", request)
+	s.log.Debugf("creating credential for issuer<%s> with subject: %s", util.SanitizeLog(request.Issuer), util.TruncateSubject(request.Data))
+
+	var validate time.Duration
+	buildStart := time.Now()
+	storageRequest, err := s.buildCredential(request, &validate)
+	if err != nil {
+		return nil, err
+	}
+
+	// store the credential, checking the subject's limit again since other credentials may have been stored since
+	storeStart := time.Now()
+	unlock := s.lockSubjectLimits(*storageRequest)
+	defer unlock()
+	if err := s.checkSubjectLimit(storageRequest.Subject, storageRequest.Schema); err != nil {
+		return nil, err
+	}
+	if err := s.storage.StoreCredential(*storageRequest); err != nil {
+		errMsg := "could not store credential"
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	// return the result
+	response := CreateCredentialResponse{Credential: storageRequest.Credential, SubjectAlias: storageRequest.SubjectAlias}
+	if request.Timings {
+		response.Timings = &IssuanceTimings{
+			Build:    storeStart.Sub(buildStart) - validate,
+			Validate: validate,
+			Store:    time.Since(storeStart),
+		}
+	}
+	return &response, nil
+}
+
+// CreateCredentials issues a set of credentials together: either every credential is issued and stored, or, if
+// any one of them fails, none are. Building stops early once the context is done, and nothing is stored.
+func (s Service) CreateCredentials(ctx context.Context, request BatchCreateCredentialsRequest) (*BatchCreateCredentialsResponse, error) {
+
+	s.log.Debugf("creating %d credential(s) as a batch", len(request.Requests))
+
+	if len(request.Requests) > MaxBatchCreateCredentials {
+		errMsg := fmt.Sprintf("cannot create more than %d credentials at once, requested: %d", MaxBatchCreateCredentials, len(request.Requests))
+		return nil, s.log.LoggingNewError(errMsg)
+	}
+
+	storageRequests, itemErrs, err := s.buildCredentials(ctx, request.Requests)
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "stopped building credentials")
+	}
+	if len(itemErrs) > 0 {
+		return nil, itemErrs[0]
+	}
+	return s.storeCredentials(storageRequests)
+}
+
+// buildCredentials builds the requested credentials in parallel, up to the service's batch parallelism at once.
+// Built credentials are returned in request order, along with an error for each request which fails, by index.
+// If the context is done before every request is built, the remaining requests are abandoned and its error returned.
+func (s Service) buildCredentials(ctx context.Context, requests []CreateCredentialRequest) ([]credstorage.StoredCredential, []BatchItemError, error) {
+	built := make([]*credstorage.StoredCredential, len(requests))
+	buildErrs := make([]error, len(requests))
+
+	workers := s.batchParallelism
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// each worker only writes the results at the indexes it is given, so they need no locking
+			for i := range indexes {
+				built[i], buildErrs[i] = s.buildCredential(requests[i], nil)
+			}
+		}()
+	}
+
+	// hand out requests until they are all taken or the context is done
+	ctxErr := func() error {
+		for i := range requests {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}()
+	close(indexes)
+	wg.Wait()
+	if ctxErr != nil {
+		return nil, nil, ctxErr
+	}
+
+	// each credential was built without knowing of the others, so the batch may take a subject past a limit
+	for i, err := range s.checkBatchSubjectLimits(built) {
+		if buildErrs[i] == nil {
+			buildErrs[i] = err
+		}
+	}
+
+	storageRequests := make([]credstorage.StoredCredential, 0, len(requests))
+	var itemErrs []BatchItemError
+	for i := range requests {
+		if buildErrs[i] != nil {
+			itemErrs = append(itemErrs, BatchItemError{Index: i, Err: buildErrs[i]})
+			continue
+		}
+		storageRequests = append(storageRequests, *built[i])
+	}
+	return storageRequests, itemErrs, nil
+}
+
+// storeCredentials stores built credentials in a single transaction. The subject limits are checked again first,
+// since other credentials may have been stored since the credentials were built; a credential which would now take
+// its subject past a limit is reported by index, and none are stored.
+func (s Service) storeCredentials(storageRequests []credstorage.StoredCredential) (*BatchCreateCredentialsResponse, error) {
+	unlock := s.lockSubjectLimits(storageRequests...)
+	defer unlock()
+	built := make([]*credstorage.StoredCredential, 0, len(storageRequests))
+	for i := range storageRequests {
+		built = append(built, &storageRequests[i])
+	}
+	if limitErrs := s.checkBatchSubjectLimits(built); len(limitErrs) > 0 {
+		first := len(storageRequests)
+		for i := range limitErrs {
+			if i < first {
+				first = i
+			}
+		}
+		return nil, BatchItemError{Index: first, Err: limitErrs[first]}
+	}
+
+	if err := s.storage.StoreCredentials(storageRequests); err != nil {
+		errMsg := "could not store credentials"
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	creds := make([]credential.VerifiableCredential, 0, len(storageRequests))
+	for _, stored := range storageRequests {
+		creds = append(creds, stored.Credential)
+	}
+	return &BatchCreateCredentialsResponse{Credentials: creds}, nil
+}
+
+// buildCredential checks a request for a credential against the service's policies and builds the credential,
+// ready to be stored. When given, validate is set to how long validating the credential's data took.
+func (s Service) buildCredential(request CreateCredentialRequest, validate *time.Duration) (*credstorage.StoredCredential, error) {
+	// no credentials are issued while issuance is frozen for maintenance
+	if err := s.checkIssuanceFreeze(request.Issuer); err != nil {
+		return nil, err
+	}
+
+	// check the requested types are allowed
+	if disallowed := disallowedTypes(s.config.AllowedTypes, request.Type); len(disallowed) > 0 {
+		return nil, s.log.LoggingError(DisallowedTypeError{Types: disallowed})
+	}
+
+	// a subject given by alias is issued the credential under the DID the alias resolves to
+	subjectDID, subjectAlias, err := s.resolveSubject(request.Subject)
+	if err != nil {
+		return nil, err
+	}
+	request.Subject = subjectDID
 
 	builder := credential.NewVerifiableCredentialBuilder()
 
 	if err := builder.SetIssuer(request.Issuer); err != nil {
 		errMsg := fmt.Sprintf("could not build credential when setting issuer: %s", request.Issuer)
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 
 	// check if there's a conflict with subject ID
 	if id, ok := request.Data[credential.VerifiableCredentialIDProperty]; ok && id != request.Subject {
 		errMsg := fmt.Sprintf("cannot set subject<%s>, data already contains a different ID value: %s", request.Subject, id)
-		logrus.Error(errMsg)
-		return nil, util.LoggingNewError(errMsg)
+		s.log.Error(errMsg)
+		return nil, s.log.LoggingNewError(errMsg)
+	}
+
+	// add the issuer's default claims, which are then subject to the same checks as any other claim
+	data := mergeIssuerDefaults(s.config.IssuerDefaults[request.Issuer], request.Data)
+
+	// check for claims required of every credential
+	if missing := missingRequiredClaims(s.config.RequiredClaims, data); len(missing) > 0 {
+		return nil, s.log.LoggingError(MissingClaimsError{Claims: missing})
+	}
+
+	// check the data, including any defaults, conforms to the credential's schema
+	validateStart := time.Now()
+	err = s.validateCredentialData(request.JSONSchema, data)
+	if validate != nil {
+		*validate = time.Since(validateStart)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// check the subject may hold another credential of this schema
+	if err := s.checkSubjectLimit(request.Subject, request.JSONSchema); err != nil {
+		return nil, err
 	}
 
 	// set subject value
-	subject := credential.CredentialSubject(request.Data)
+	subject := credential.CredentialSubject(data)
 	subject[credential.VerifiableCredentialIDProperty] = request.Subject
 
 	if err := builder.SetCredentialSubject(subject); err != nil {
-		errMsg := fmt.Sprintf("could not set subject: %+v", subject)
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		errMsg := fmt.Sprintf("could not set subject: %s", util.TruncateSubject(subject))
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 
 	// if a context value exists, set it
 	if request.Context != "" {
 		if err := builder.AddContext(request.Context); err != nil {
 			errMsg := fmt.Sprintf("could not add context to credential: %s", request.Context)
-			return nil, util.LoggingErrorMsg(err, errMsg)
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
+		}
+	}
+
+	if len(request.Type) > 0 {
+		if err := builder.AddType(request.Type); err != nil {
+			errMsg := fmt.Sprintf("could not add type(s) to credential: %s", request.Type)
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
 		}
 	}
 
@@ -92,127 +392,301 @@ func (s Service) CreateCredential(request CreateCredentialRequest) (*CreateCrede
 		}
 		if err := builder.SetCredentialSchema(schema); err != nil {
 			errMsg := fmt.Sprintf("could not set JSON Schema for credential: %s", request.JSONSchema)
-			return nil, util.LoggingErrorMsg(err, errMsg)
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
 		}
 	}
 
 	// if an expiry value exists, set it
 	if request.Expiry != "" {
-		if err := builder.SetExpirationDate(request.Expiry); err != nil {
+		if err := builder.SetExpirationDate(s.formatTimestamp(request.Expiry)); err != nil {
 			errMsg := fmt.Sprintf("could not set expirty for credential: %s", request.Expiry)
-			return nil, util.LoggingErrorMsg(err, errMsg)
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
+		}
+	}
+
+	var purgeAt string
+	if request.StorageTTL != "" {
+		ttl, err := time.ParseDuration(request.StorageTTL)
+		if err != nil || ttl <= 0 {
+			return nil, s.log.LoggingError(InvalidStorageTTLError{TTL: request.StorageTTL})
 		}
+		purgeAt = s.clock().Add(ttl).Format(time.RFC3339Nano)
 	}
 
-	if err := builder.SetIssuanceDate(time.Now().Format(time.RFC3339)); err != nil {
+	if err := builder.SetIssuanceDate(s.clock().Format(s.timestampLayout)); err != nil {
 		errMsg := fmt.Sprintf("could not set credential issuance date")
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 
 	cred, err := builder.Build()
 	if err != nil {
 		errMsg := "could not build credential"
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 
-	// store the credential
-	storageRequest := credstorage.StoredCredential{
+	return &credstorage.StoredCredential{
 		ID:           cred.ID,
 		Credential:   *cred,
 		Issuer:       request.Issuer,
 		Subject:      request.Subject,
 		Schema:       request.JSONSchema,
 		IssuanceDate: cred.IssuanceDate,
-	}
-	if err := s.storage.StoreCredential(storageRequest); err != nil {
-		errMsg := "could not store credential"
-		return nil, util.LoggingErrorMsg(err, errMsg)
-	}
+		SubjectAlias: subjectAlias,
+		PurgeAt:      purgeAt,
+	}, nil
+}
 
-	// return the result
-	response := CreateCredentialResponse{Credential: *cred}
-	return &response, nil
+// CredentialNotFoundError is returned when there is no credential with an ID, including when it has been purged
+type CredentialNotFoundError struct {
+	ID string
+}
+
+func (e CredentialNotFoundError) Error() string {
+	return fmt.Sprintf("credential not found with id: %s", e.ID)
 }
 
 func (s Service) GetCredential(request GetCredentialRequest) (*GetCredentialResponse, error) {
 
-	logrus.Debugf("getting credential: %s", request.ID)
+	s.log.Debugf("getting credential: %s", request.ID)
 
 	gotCred, err := s.storage.GetCredential(request.ID)
 	if err != nil {
+		if errors.As(err, &credstorage.CredentialNotFoundError{}) {
+			return nil, s.log.LoggingError(CredentialNotFoundError{ID: request.ID})
+		}
 		errMsg := fmt.Sprintf("could not get credential: %s", request.ID)
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	// a credential past its storage TTL is gone, even if it has yet to be purged
+	if s.isPurged(*gotCred) {
+		return nil, s.log.LoggingError(CredentialNotFoundError{ID: request.ID})
 	}
 
-	response := GetCredentialResponse{Credential: gotCred.Credential}
+	response := GetCredentialResponse{Credential: gotCred.Credential, SubjectAlias: gotCred.SubjectAlias}
 	return &response, nil
 }
 
-func (s Service) GetCredentialsByIssuer(request GetCredentialByIssuerRequest) (*GetCredentialsResponse, error) {
+// GetCredentials gets each of a list of credentials by ID in a single read, reporting the IDs which were not found
+func (s Service) GetCredentials(request BatchGetCredentialsRequest) (*BatchGetCredentialsResponse, error) {
 
-	logrus.Debugf("getting credential(s) for issuer: %s", util.SanitizeLog(request.Issuer))
+	s.log.Debugf("getting %d credential(s) by id", len(request.IDs))
 
-	gotCreds, err := s.storage.GetCredentialsByIssuer(request.Issuer)
+	// each credential is listed once, however many times it is requested
+	ids := make([]string, 0, len(request.IDs))
+	requested := make(map[string]bool, len(request.IDs))
+	for _, id := range request.IDs {
+		if !requested[id] {
+			requested[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > MaxBatchGetCredentials {
+		errMsg := fmt.Sprintf("cannot get more than %d credentials at once, requested: %d", MaxBatchGetCredentials, len(ids))
+		return nil, s.log.LoggingNewError(errMsg)
+	}
+
+	gotCreds, err := s.storage.GetCredentials(ids)
 	if err != nil {
-		errMsg := fmt.Sprintf("could not get credential(s) for issuer: %s", request.Issuer)
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		errMsg := "could not get credentials by id"
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 
+	found := make(map[string]bool, len(gotCreds))
 	var creds []credential.VerifiableCredential
 	for _, cred := range gotCreds {
+		if s.isPurged(cred) {
+			continue
+		}
+		found[cred.Credential.ID] = true
 		creds = append(creds, cred.Credential)
 	}
+	var notFound []string
+	for _, id := range ids {
+		if !found[id] {
+			notFound = append(notFound, id)
+		}
+	}
 
-	response := GetCredentialsResponse{Credentials: creds}
+	response := BatchGetCredentialsResponse{Credentials: creds, NotFound: notFound}
 	return &response, nil
 }
 
-func (s Service) GetCredentialsBySubject(request GetCredentialBySubjectRequest) (*GetCredentialsResponse, error) {
+func (s Service) GetCredentialsByIssuer(request GetCredentialByIssuerRequest) (*GetCredentialsResponse, error) {
 
-	logrus.Debugf("getting credential(s) for subject: %s", util.SanitizeLog(request.Subject))
+	s.log.Debugf("getting credential(s) for issuer: %s", util.SanitizeLog(request.Issuer))
 
-	gotCreds, err := s.storage.GetCredentialsBySubject(request.Subject)
+	gotCreds, err := s.storage.GetCredentialsByIssuer(request.Issuer)
 	if err != nil {
-		errMsg := fmt.Sprintf("could not get credential(s) for subject: %s", request.Subject)
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		errMsg := fmt.Sprintf("could not get credential(s) for issuer: %s", request.Issuer)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 
-	var creds []credential.VerifiableCredential
-	for _, cred := range gotCreds {
-		creds = append(creds, cred.Credential)
+	return s.listCredentials(gotCreds, request.Status, request.Sort, false), nil
+}
+
+func (s Service) GetCredentialsBySubject(request GetCredentialBySubjectRequest) (*GetCredentialsResponse, error) {
+
+	s.log.Debugf("getting credential(s) for subject: %s", util.SanitizeLog(request.Subject))
+
+	subject, _, err := s.resolveSubject(request.Subject)
+	if err != nil {
+		return nil, err
+	}
+	gotCreds, err := s.storage.GetCredentialsBySubject(subject)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get credential(s) for subject: %s", request.Subject)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 
-	response := GetCredentialsResponse{Credentials: creds}
-	return &response, nil
+	return s.listCredentials(gotCreds, request.Status, request.Sort, request.Dedupe), nil
 }
 
 func (s Service) GetCredentialsBySchema(request GetCredentialBySchemaRequest) (*GetCredentialsResponse, error) {
 
-	logrus.Debugf("getting credential(s) for schema: %s", util.SanitizeLog(request.Schema))
+	s.log.Debugf("getting credential(s) for schema: %s", util.SanitizeLog(request.Schema))
 
 	gotCreds, err := s.storage.GetCredentialsBySchema(request.Schema)
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get credential(s) for schema: %s", request.Schema)
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 
-	var creds []credential.VerifiableCredential
+	return s.listCredentials(gotCreds, request.Status, request.Sort, false), nil
+}
+
+// listCredentials filters credentials listed by storage to those with the given status, if any, and puts them in the
+// requested order, optionally deduplicating them
+func (s Service) listCredentials(gotCreds []credstorage.StoredCredential, status, order string, dedupe bool) *GetCredentialsResponse {
+	var filtered []credstorage.StoredCredential
 	for _, cred := range gotCreds {
-		creds = append(creds, cred.Credential)
+		if s.isPurged(cred) {
+			continue
+		}
+		if status != "" && s.credentialStatus(cred.Credential) != status {
+			continue
+		}
+		filtered = append(filtered, cred)
 	}
 
-	response := GetCredentialsResponse{Credentials: creds}
-	return &response, nil
+	// storage lists credentials most recently issued first, then by ID
+	if order == framework.SortCreatedAsc {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			if c := credstorage.CompareIssuanceDates(filtered[i], filtered[j]); c != 0 {
+				return c < 0
+			}
+			return filtered[i].Credential.ID < filtered[j].Credential.ID
+		})
+	}
+
+	var supersededCounts map[string]int
+	if dedupe {
+		filtered, supersededCounts = s.dedupeCredentials(filtered)
+	}
+
+	var creds []credential.VerifiableCredential
+	for _, cred := range filtered {
+		creds = append(creds, cred.Credential)
+	}
+	return &GetCredentialsResponse{Credentials: creds, SupersededCounts: supersededCounts}
 }
 
 func (s Service) DeleteCredential(request DeleteCredentialRequest) error {
 
-	logrus.Debugf("deleting credential: %s", request.ID)
+	s.log.Debugf("deleting credential: %s", request.ID)
 
 	if err := s.storage.DeleteCredential(request.ID); err != nil {
 		errMsg := fmt.Sprintf("could not delete credential with id: %s", request.ID)
-		return util.LoggingErrorMsg(err, errMsg)
+		return s.log.LoggingErrorMsg(err, errMsg)
 	}
 
 	return nil
 }
+
+// DeleteCredentials deletes every credential issued by the given issuer, about the given subject, and/or against the
+// given schema. The subject may be given by DID or by alias. Credentials are deleted in batches, each in its own
+// transaction, so a failure part way through leaves earlier batches deleted. Deleting by subject alone erases the
+// subject, so every alias registered for the subject's DID is deleted too, once its credentials are.
+func (s Service) DeleteCredentials(request DeleteCredentialsRequest) (*DeleteCredentialsResponse, error) {
+
+	s.log.Debugf("deleting credential(s) for issuer<%s>, subject<%s> and schema<%s>", util.SanitizeLog(request.Issuer), util.SanitizeLog(request.Subject), util.SanitizeLog(request.Schema))
+
+	if request.Issuer == "" && request.Subject == "" && request.Schema == "" {
+		return nil, s.log.LoggingNewError("cannot delete credentials without an issuer, subject or schema")
+	}
+	if request.Subject != "" {
+		subject, _, err := s.resolveSubject(request.Subject)
+		if err != nil {
+			return nil, err
+		}
+		request.Subject = subject
+	}
+
+	var gotCreds []credstorage.StoredCredential
+	var err error
+	switch {
+	case request.Issuer != "":
+		gotCreds, err = s.storage.GetCredentialsByIssuer(request.Issuer)
+	case request.Subject != "":
+		gotCreds, err = s.storage.GetCredentialsBySubject(request.Subject)
+	default:
+		gotCreds, err = s.storage.GetCredentialsBySchema(request.Schema)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get credential(s) to delete for issuer<%s>, subject<%s> and schema<%s>", request.Issuer, request.Subject, request.Schema)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	// storage matches issuers and subjects loosely, so filter to exact matches before deleting anything
+	var ids []string
+	for _, cred := range gotCreds {
+		if request.Issuer != "" && cred.Issuer != request.Issuer {
+			continue
+		}
+		if request.Subject != "" && cred.Subject != request.Subject {
+			continue
+		}
+		if request.Schema != "" && cred.Schema != request.Schema {
+			continue
+		}
+		ids = append(ids, cred.Credential.ID)
+	}
+
+	var aliases []string
+	if request.Subject != "" && request.Issuer == "" && request.Schema == "" {
+		if aliases, err = s.subjectAliases(request.Subject); err != nil {
+			return nil, err
+		}
+	}
+
+	// credentials which cannot be read cannot be matched, so are reported rather than left behind unnoticed
+	unreadable, err := s.storage.GetUnreadableCredentials()
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not check for credentials which cannot be read")
+	}
+	if len(unreadable) > 0 {
+		s.log.Warnf("%d credential(s) could not be read, so could not be checked for deletion", len(unreadable))
+	}
+
+	if request.DryRun {
+		return &DeleteCredentialsResponse{Count: len(ids), IDs: ids, Aliases: aliases, Unreadable: unreadable}, nil
+	}
+
+	for start := 0; start < len(ids); start += deleteCredentialsBatchSize {
+		end := start + deleteCredentialsBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err = s.storage.DeleteCredentials(ids[start:end]); err != nil {
+			errMsg := fmt.Sprintf("could not delete credential(s), %d of %d deleted", start, len(ids))
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
+		}
+	}
+	for _, alias := range aliases {
+		if err = s.storage.DeleteSubjectAlias(alias); err != nil {
+			errMsg := fmt.Sprintf("deleted %d credential(s), but could not delete subject alias: %s", len(ids), alias)
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
+		}
+	}
+
+	return &DeleteCredentialsResponse{Count: len(ids), IDs: ids, Aliases: aliases, Unreadable: unreadable}, nil
+}
@@ -0,0 +1,211 @@
+package credential
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+)
+
+var (
+	// matches a reference to a row's value in a template, e.g. ${email}
+	csvColumnRefRegex = regexp.MustCompile(`\$\{([^}]+)}`)
+)
+
+// CSVRowError identifies which line of a CSV document a credential could not be issued for
+type CSVRowError struct {
+	Line int
+	Err  error
+}
+
+func (c CSVRowError) Error() string {
+	return fmt.Sprintf("line<%d>: %s", c.Line, c.Err.Error())
+}
+
+func (c CSVRowError) Unwrap() error {
+	return c.Err
+}
+
+// CSVRowErrors reports every row which failed; when any row fails no credentials are issued
+type CSVRowErrors []CSVRowError
+
+func (c CSVRowErrors) Error() string {
+	rowErrs := make([]string, 0, len(c))
+	for _, rowErr := range c {
+		rowErrs = append(rowErrs, rowErr.Error())
+	}
+	return fmt.Sprintf("could not issue credentials for %d row(s): %s", len(c), strings.Join(rowErrs, "; "))
+}
+
+// CreateCredentialsFromCSV issues a credential for each row of a CSV document as a single batch: either a credential
+// is issued for every row or, if any row fails, none are. Failures are reported for every failed row, by line.
+// Building stops early once the context is done, and nothing is stored.
+func (s Service) CreateCredentialsFromCSV(ctx context.Context, request CreateCredentialsFromCSVRequest) (*BatchCreateCredentialsResponse, error) {
+
+	s.log.Debugf("creating credentials from CSV for issuer: %s", util.SanitizeLog(request.Template.Issuer))
+
+	reader := csv.NewReader(strings.NewReader(request.CSV))
+	header, err := reader.Read()
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not read CSV header")
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimSpace(column)] = i
+	}
+	if err = validateCSVMapping(request, columns); err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "invalid CSV mapping")
+	}
+
+	var requests []CreateCredentialRequest
+	var lines []int
+	var rowErrs CSVRowErrors
+	rows := 0
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, s.log.LoggingErrorMsg(err, "could not read CSV")
+		}
+		if rows == MaxCSVRows {
+			errMsg := fmt.Sprintf("cannot create credentials from more than %d CSV rows at once", MaxCSVRows)
+			return nil, s.log.LoggingNewError(errMsg)
+		}
+		rows++
+		line, _ := reader.FieldPos(0)
+		rowRequest := csvRowRequest(request, columns, row)
+		// a row whose DIDs are invalid once filled in is reported without being built
+		if err = validateCSVRowDIDs(rowRequest); err != nil {
+			rowErrs = append(rowErrs, CSVRowError{Line: line, Err: err})
+			continue
+		}
+		lines = append(lines, line)
+		requests = append(requests, rowRequest)
+	}
+	if rows == 0 {
+		return nil, s.log.LoggingNewError("CSV has no rows to create credentials from")
+	}
+
+	storageRequests, itemErrs, err := s.buildCredentials(ctx, requests)
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "stopped building credentials from CSV")
+	}
+	for _, itemErr := range itemErrs {
+		rowErrs = append(rowErrs, CSVRowError{Line: lines[itemErr.Index], Err: itemErr.Err})
+	}
+	if len(rowErrs) > 0 {
+		sort.SliceStable(rowErrs, func(i, j int) bool { return rowErrs[i].Line < rowErrs[j].Line })
+		return nil, rowErrs
+	}
+	created, err := s.storeCredentials(storageRequests)
+	var itemErr BatchItemError
+	if errors.As(err, &itemErr) {
+		return nil, CSVRowErrors{{Line: lines[itemErr.Index], Err: itemErr.Err}}
+	}
+	return created, err
+}
+
+// validateCSVMapping checks every column the request refers to is present in the CSV, and that no two claim paths
+// in the mapping conflict
+func validateCSVMapping(request CreateCredentialsFromCSVRequest, columns map[string]int) error {
+	if len(request.Mapping) == 0 {
+		return fmt.Errorf("no columns are mapped to claims")
+	}
+
+	var unknown []string
+	claimPaths := make([]string, 0, len(request.Mapping))
+	for column, claimPath := range request.Mapping {
+		if _, ok := columns[column]; !ok {
+			unknown = append(unknown, column)
+		}
+		claimPaths = append(claimPaths, claimPath)
+	}
+	template := request.Template
+	for _, value := range []string{template.Issuer, template.Subject, template.JSONSchema, template.Expiry} {
+		for _, ref := range csvColumnRefRegex.FindAllStringSubmatch(value, -1) {
+			if _, ok := columns[ref[1]]; !ok {
+				unknown = append(unknown, ref[1])
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("column(s) not in CSV header: %s", strings.Join(unknown, ", "))
+	}
+
+	// a claim cannot be both a value and an object holding another claim
+	sort.Strings(claimPaths)
+	for i := 1; i < len(claimPaths); i++ {
+		if claimPaths[i] == claimPaths[i-1] || strings.HasPrefix(claimPaths[i], claimPaths[i-1]+".") {
+			return fmt.Errorf("claim paths<%s> and <%s> conflict", claimPaths[i-1], claimPaths[i])
+		}
+	}
+	return nil
+}
+
+// csvRowRequest builds the request for a row's credential from the template. Empty values are left out of the
+// credential's data, so that required claims are reported as missing.
+func csvRowRequest(request CreateCredentialsFromCSVRequest, columns map[string]int, row []string) CreateCredentialRequest {
+	value := func(column string) string {
+		return strings.TrimSpace(row[columns[column]])
+	}
+	fill := func(template string) string {
+		return csvColumnRefRegex.ReplaceAllStringFunc(template, func(ref string) string {
+			return value(csvColumnRefRegex.FindStringSubmatch(ref)[1])
+		})
+	}
+
+	data := mergeIssuerDefaults(request.Template.Data, nil)
+	for column, claimPath := range request.Mapping {
+		if v := value(column); v != "" {
+			setClaim(claimPath, v, data)
+		}
+	}
+
+	rowRequest := request.Template
+	rowRequest.Issuer = fill(request.Template.Issuer)
+	rowRequest.Subject = fill(request.Template.Subject)
+	rowRequest.JSONSchema = fill(request.Template.JSONSchema)
+	rowRequest.Expiry = fill(request.Template.Expiry)
+	rowRequest.Data = data
+	return rowRequest
+}
+
+// validateCSVRowDIDs checks a row's issuer is a DID, and its subject too unless it is given by alias
+func validateCSVRowDIDs(rowRequest CreateCredentialRequest) error {
+	if err := util.ValidateDID(rowRequest.Issuer); err != nil {
+		return fmt.Errorf("issuer<%s> is not a valid DID: %s", util.SanitizeLog(rowRequest.Issuer), err.Error())
+	}
+	if strings.HasPrefix(rowRequest.Subject, util.DIDPrefix) {
+		if err := util.ValidateDID(rowRequest.Subject); err != nil {
+			return fmt.Errorf("subject<%s> is not a valid DID: %s", util.SanitizeLog(rowRequest.Subject), err.Error())
+		}
+	}
+	return nil
+}
+
+// setClaim sets a value at a '.' separated path of properties in the given data. Objects along the path are copied
+// rather than modified, since they may be shared with the template, and created as needed.
+func setClaim(path string, value interface{}, data map[string]interface{}) {
+	properties := strings.Split(path, ".")
+	current := data
+	for _, property := range properties[:len(properties)-1] {
+		next := make(map[string]interface{})
+		if existing, ok := current[property].(map[string]interface{}); ok {
+			for k, v := range existing {
+				next[k] = v
+			}
+		}
+		current[property] = next
+		current = next
+	}
+	current[properties[len(properties)-1]] = value
+}
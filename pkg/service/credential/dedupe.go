@@ -0,0 +1,49 @@
+package credential
+
+import (
+	"strings"
+
+	credstorage "github.com/tbd54566975/ssi-service/pkg/service/credential/storage"
+)
+
+// dedupeCredentials collapses active credentials issued by the same issuer, to the same subject, against the same
+// schema, keeping only the most recently issued. Expired credentials are never collapsed. Order is otherwise
+// preserved. Alongside the remaining credentials, it returns how many credentials each kept credential superseded,
+// for those which superseded any.
+func (s Service) dedupeCredentials(creds []credstorage.StoredCredential) ([]credstorage.StoredCredential, map[string]int) {
+	latest := make(map[string]int)
+	superseded := make(map[string]int)
+	for i, cred := range creds {
+		if s.credentialStatus(cred.Credential) != StatusActive {
+			continue
+		}
+		key := strings.Join([]string{cred.Issuer, cred.Subject, cred.Schema}, "|")
+		kept, ok := latest[key]
+		if !ok {
+			latest[key] = i
+			continue
+		}
+		superseded[key]++
+		if credstorage.CompareIssuanceDates(cred, creds[kept]) > 0 {
+			latest[key] = i
+		}
+	}
+
+	supersededCounts := make(map[string]int)
+	keep := make(map[int]bool, len(latest))
+	for key, i := range latest {
+		keep[i] = true
+		if count := superseded[key]; count > 0 {
+			supersededCounts[creds[i].Credential.ID] = count
+		}
+	}
+
+	var deduped []credstorage.StoredCredential
+	for i, cred := range creds {
+		if s.credentialStatus(cred.Credential) == StatusActive && !keep[i] {
+			continue
+		}
+		deduped = append(deduped, cred)
+	}
+	return deduped, supersededCounts
+}
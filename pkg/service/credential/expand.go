@@ -0,0 +1,90 @@
+package credential
+
+import (
+	"sort"
+	"strings"
+
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+)
+
+const (
+	// undefinedTermVocab is a vocabulary placed ahead of a document's own contexts, so that terms the contexts do not
+	// define expand to IRIs under it, rather than being dropped
+	undefinedTermVocab = "urn:ssi-service:undefined-term:"
+)
+
+// ExpandCredential computes the expanded JSON-LD form of a credential, as a verifier processing it would, and reports
+// each term which the credential's contexts do not define. Undefined terms are dropped by JSON-LD expansion, so the
+// data they hold would not be covered by a linked data proof. Remote contexts are fetched and cached.
+func (s Service) ExpandCredential(request ExpandCredentialRequest) (*ExpandCredentialResponse, error) {
+
+	s.log.Debug("expanding credential")
+
+	processor := sdkutil.NewLDProcessor()
+	expanded, err := processor.Expand(request.Credential, processor.GetOptions())
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not expand credential")
+	}
+
+	// expand again with a fallback vocabulary, under which the undefined terms can be found
+	withFallback := make(map[string]interface{}, len(request.Credential))
+	for k, v := range request.Credential {
+		withFallback[k] = v
+	}
+	withFallback["@context"] = prependContext(map[string]interface{}{"@vocab": undefinedTermVocab}, request.Credential["@context"])
+	expandedWithFallback, err := processor.Expand(withFallback, processor.GetOptions())
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not expand credential to find undefined terms")
+	}
+
+	undefined := make(map[string]bool)
+	findUndefinedTerms(expandedWithFallback, undefined)
+	undefinedTerms := make([]string, 0, len(undefined))
+	for term := range undefined {
+		undefinedTerms = append(undefinedTerms, term)
+	}
+	sort.Strings(undefinedTerms)
+
+	return &ExpandCredentialResponse{Expanded: expanded, UndefinedTerms: undefinedTerms}, nil
+}
+
+// prependContext places a context ahead of a document's existing contexts, so that the document's contexts take
+// precedence over it
+func prependContext(context interface{}, existing interface{}) []interface{} {
+	contexts := []interface{}{context}
+	switch v := existing.(type) {
+	case nil:
+	case []interface{}:
+		contexts = append(contexts, v...)
+	default:
+		contexts = append(contexts, v)
+	}
+	return contexts
+}
+
+// findUndefinedTerms walks an expanded document, collecting the terms of properties and types which expanded
+// under the fallback vocabulary
+func findUndefinedTerms(expanded interface{}, undefined map[string]bool) {
+	switch v := expanded.(type) {
+	case []interface{}:
+		for _, element := range v {
+			findUndefinedTerms(element, undefined)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			if term := strings.TrimPrefix(key, undefinedTermVocab); term != key {
+				undefined[term] = true
+			}
+			if key == "@type" {
+				types, _ := value.([]interface{})
+				for _, t := range types {
+					if typeIRI, ok := t.(string); ok && strings.HasPrefix(typeIRI, undefinedTermVocab) {
+						undefined[strings.TrimPrefix(typeIRI, undefinedTermVocab)] = true
+					}
+				}
+				continue
+			}
+			findUndefinedTerms(value, undefined)
+		}
+	}
+}
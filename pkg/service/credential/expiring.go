@@ -0,0 +1,94 @@
+package credential
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+
+	credstorage "github.com/tbd54566975/ssi-service/pkg/service/credential/storage"
+)
+
+// ParseExpiryWindow parses a window of time, given either as a number of days such as 30d, or in Go duration form
+// such as 36h
+func ParseExpiryWindow(window string) (time.Duration, error) {
+	var duration time.Duration
+	if days := strings.TrimSuffix(window, "d"); days != window {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window: %s", window)
+		}
+		duration = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(window)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window: %s", window)
+		}
+		duration = d
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("window<%s> must be positive", window)
+	}
+	return duration, nil
+}
+
+// GetExpiringCredentials lists the credentials which will expire within a window from now, soonest first. Credentials
+// which have already expired, or never expire, are not listed.
+func (s Service) GetExpiringCredentials(request GetExpiringCredentialsRequest) (*GetExpiringCredentialsResponse, error) {
+
+	s.log.Debugf("getting credential(s) expiring within: %s", request.Within)
+
+	var gotCreds []credstorage.StoredCredential
+	var err error
+	if request.Issuer != "" {
+		gotCreds, err = s.storage.GetCredentialsByIssuer(request.Issuer)
+	} else {
+		gotCreds, err = s.storage.GetAllCredentials()
+	}
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not get credentials to check for expiry")
+	}
+
+	type expiring struct {
+		cred      credential.VerifiableCredential
+		expiresAt time.Time
+	}
+	now := s.clock()
+	until := now.Add(request.Within)
+	var expiringCreds []expiring
+	for _, cred := range gotCreds {
+		if s.isPurged(cred) {
+			continue
+		}
+		// storage matches issuers loosely
+		if request.Issuer != "" && cred.Issuer != request.Issuer {
+			continue
+		}
+		if cred.Credential.ExpirationDate == "" {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, cred.Credential.ExpirationDate)
+		if err != nil {
+			s.log.WithError(err).Warnf("could not parse expiration date of credential: %s", cred.Credential.ID)
+			continue
+		}
+		if expiresAt.After(now) && !expiresAt.After(until) {
+			expiringCreds = append(expiringCreds, expiring{cred: cred.Credential, expiresAt: expiresAt})
+		}
+	}
+	sort.Slice(expiringCreds, func(i, j int) bool {
+		if !expiringCreds[i].expiresAt.Equal(expiringCreds[j].expiresAt) {
+			return expiringCreds[i].expiresAt.Before(expiringCreds[j].expiresAt)
+		}
+		return expiringCreds[i].cred.ID < expiringCreds[j].cred.ID
+	})
+
+	creds := make([]credential.VerifiableCredential, 0, len(expiringCreds))
+	for _, expiringCred := range expiringCreds {
+		creds = append(creds, expiringCred.cred)
+	}
+	return &GetExpiringCredentialsResponse{Credentials: creds}, nil
+}
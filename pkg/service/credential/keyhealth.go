@@ -0,0 +1,69 @@
+package credential
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+// IssuerKeyNotFoundError is returned when the key store holds no key controlled by an issuer
+type IssuerKeyNotFoundError struct {
+	Issuer string
+}
+
+func (e IssuerKeyNotFoundError) Error() string {
+	return fmt.Sprintf("no signing key found for issuer: %s", e.Issuer)
+}
+
+// GetIssuerKeyHealth reports on the age of an issuer's signing key, so that it can be rotated before reaching the
+// configured maximum age. When an issuer controls more than one key, the most recently created key is reported on.
+func (s Service) GetIssuerKeyHealth(request GetIssuerKeyHealthRequest) (*GetIssuerKeyHealthResponse, error) {
+	gotKeys, err := s.keyStore.GetKeyDetailsByController(keystore.GetKeyDetailsByControllerRequest{Controller: request.Issuer})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get keys for issuer: %s", request.Issuer)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	var latestKey *keystore.GetKeyDetailsResponse
+	var latestCreated time.Time
+	for i, key := range gotKeys.Keys {
+		created, err := time.Parse(time.RFC3339, key.CreatedAt)
+		if err != nil {
+			errMsg := fmt.Sprintf("could not parse creation date of key: %s", key.ID)
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
+		}
+		if latestKey == nil || created.After(latestCreated) {
+			latestKey = &gotKeys.Keys[i]
+			latestCreated = created
+		}
+	}
+	if latestKey == nil {
+		return nil, s.log.LoggingError(IssuerKeyNotFoundError{Issuer: request.Issuer})
+	}
+
+	age := s.clock().Sub(latestCreated)
+	return &GetIssuerKeyHealthResponse{
+		KeyID:      latestKey.ID,
+		Algorithm:  latestKey.Type,
+		CreatedAt:  latestKey.CreatedAt,
+		Age:        age,
+		MaxAge:     s.keyMaxAge,
+		PastMaxAge: s.keyMaxAge > 0 && age > s.keyMaxAge,
+	}, nil
+}
+
+// parseKeyMaxAge parses the configured maximum age of issuer keys, where empty means there is no maximum
+func parseKeyMaxAge(maxAge string) (time.Duration, error) {
+	if maxAge == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(maxAge)
+	if err != nil {
+		return 0, fmt.Errorf("invalid issuer key max age: %s", maxAge)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("issuer key max age<%s> must be positive", maxAge)
+	}
+	return duration, nil
+}
@@ -0,0 +1,185 @@
+package credential
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tbd54566975/ssi-service/config"
+	credstorage "github.com/tbd54566975/ssi-service/pkg/service/credential/storage"
+)
+
+const (
+	// SubjectLimitReject rejects requests for credentials beyond a subject's limit
+	SubjectLimitReject string = "reject"
+)
+
+// SubjectLimitError is returned when issuing a credential would exceed the number of active credentials a subject
+// may hold for a schema
+type SubjectLimitError struct {
+	Subject   string
+	Schema    string
+	MaxActive int
+}
+
+func (s SubjectLimitError) Error() string {
+	return fmt.Sprintf("subject<%s> already holds the maximum of %d active credential(s) for schema: %s", s.Subject, s.MaxActive, s.Schema)
+}
+
+// validateSubjectLimits checks the configured subject limits can be enforced
+func validateSubjectLimits(limits map[string]config.SubjectLimitConfig) error {
+	for schema, limit := range limits {
+		if limit.MaxActive < 1 {
+			return fmt.Errorf("subject limit for schema<%s> must allow at least one active credential", schema)
+		}
+		// revoking prior credentials requires credential status support, which the service does not yet have
+		if limit.Policy != SubjectLimitReject {
+			return fmt.Errorf("unsupported subject limit policy<%s> for schema: %s", limit.Policy, schema)
+		}
+	}
+	return nil
+}
+
+// checkSubjectLimit makes sure the subject can be issued another credential for the schema. Credentials which have
+// expired are no longer active, and do not count towards the limit.
+func (s Service) checkSubjectLimit(subject, schema string) error {
+	limit, ok := s.config.SubjectLimits[schema]
+	if !ok || schema == "" {
+		return nil
+	}
+	active, err := s.countActiveCredentials(subject, schema)
+	if err != nil {
+		return err
+	}
+	if active >= limit.MaxActive {
+		return s.log.LoggingError(SubjectLimitError{Subject: subject, Schema: schema, MaxActive: limit.MaxActive})
+	}
+	return nil
+}
+
+// subjectSchema identifies the credentials of a schema held by a subject
+type subjectSchema struct {
+	subject string
+	schema  string
+}
+
+// subjectLimitLocks holds a lock for each subject and schema with a limit which credentials are being stored for.
+// Counting a subject's active credentials and storing another are separate steps, so without the lock concurrent
+// requests could each count the same credentials and together take the subject past its limit.
+type subjectLimitLocks struct {
+	mu    sync.Mutex
+	locks map[subjectSchema]*subjectLimitLock
+}
+
+type subjectLimitLock struct {
+	sync.Mutex
+	// how many callers hold or are waiting for the lock, so that it is removed once none are
+	refs int
+}
+
+func newSubjectLimitLocks() *subjectLimitLocks {
+	return &subjectLimitLocks{locks: make(map[subjectSchema]*subjectLimitLock)}
+}
+
+// lockSubjectLimits locks the subject limit of each credential with a limited schema, returning a function which
+// unlocks them. Locks are taken in order, so that callers locking overlapping limits cannot deadlock.
+func (s Service) lockSubjectLimits(creds ...credstorage.StoredCredential) (unlock func()) {
+	var keys []subjectSchema
+	seen := make(map[subjectSchema]bool)
+	for _, cred := range creds {
+		key := subjectSchema{subject: cred.Subject, schema: cred.Schema}
+		if _, ok := s.config.SubjectLimits[cred.Schema]; !ok || cred.Schema == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].subject != keys[j].subject {
+			return keys[i].subject < keys[j].subject
+		}
+		return keys[i].schema < keys[j].schema
+	})
+
+	locks := s.limitLocks
+	held := make([]*subjectLimitLock, 0, len(keys))
+	for _, key := range keys {
+		locks.mu.Lock()
+		lock, ok := locks.locks[key]
+		if !ok {
+			lock = new(subjectLimitLock)
+			locks.locks[key] = lock
+		}
+		lock.refs++
+		locks.mu.Unlock()
+
+		lock.Lock()
+		held = append(held, lock)
+	}
+	return func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			held[i].Unlock()
+			locks.mu.Lock()
+			if held[i].refs--; held[i].refs == 0 {
+				delete(locks.locks, keys[i])
+			}
+			locks.mu.Unlock()
+		}
+	}
+}
+
+// checkBatchSubjectLimits checks the credentials of a batch against the subject limits together, since each was
+// only checked against the credentials already stored. Credentials are counted in batch order, so the errors, by
+// index, are for the credentials which would take a subject past its limit.
+func (s Service) checkBatchSubjectLimits(built []*credstorage.StoredCredential) map[int]error {
+	held := make(map[subjectSchema]int)
+	errs := make(map[int]error)
+	for i, cred := range built {
+		if cred == nil || cred.Schema == "" {
+			continue
+		}
+		limit, ok := s.config.SubjectLimits[cred.Schema]
+		if !ok || s.credentialStatus(cred.Credential) != StatusActive {
+			continue
+		}
+
+		key := subjectSchema{subject: cred.Subject, schema: cred.Schema}
+		active, counted := held[key]
+		if !counted {
+			stored, err := s.countActiveCredentials(cred.Subject, cred.Schema)
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			active = stored
+		}
+		if active >= limit.MaxActive {
+			errs[i] = s.log.LoggingError(SubjectLimitError{Subject: cred.Subject, Schema: cred.Schema, MaxActive: limit.MaxActive})
+			held[key] = active
+			continue
+		}
+		held[key] = active + 1
+	}
+	return errs
+}
+
+// countActiveCredentials counts the stored credentials of a schema held by a subject which have not expired
+func (s Service) countActiveCredentials(subject, schema string) (int, error) {
+	gotCreds, err := s.storage.GetCredentialsBySubject(subject)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not check active credentials for subject: %s", subject)
+		return 0, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	active := 0
+	for _, cred := range gotCreds {
+		// subject queries match on substrings, so make sure this is the same subject
+		if cred.Subject != subject || cred.Schema != schema {
+			continue
+		}
+		if s.credentialStatus(cred.Credential) == StatusActive {
+			active++
+		}
+	}
+	return active, nil
+}
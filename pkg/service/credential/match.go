@@ -0,0 +1,198 @@
+package credential
+
+import (
+	"fmt"
+	"strings"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/goccy/go-json"
+	"github.com/oliveagle/jsonpath"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+)
+
+// UnsupportedDefinitionError is returned when a presentation definition uses Presentation Exchange features which
+// credentials cannot be matched against
+type UnsupportedDefinitionError struct {
+	Features []string
+}
+
+func (u UnsupportedDefinitionError) Error() string {
+	return fmt.Sprintf("presentation definition uses unsupported feature(s): %s", strings.Join(u.Features, ", "))
+}
+
+// MatchCredentials evaluates each credential against each input descriptor of a presentation definition, following
+// the input evaluation of https://identity.foundation/presentation-exchange/#input-evaluation. Credentials are
+// only matched; neither their proofs nor their status are checked.
+func (s Service) MatchCredentials(request MatchCredentialsRequest) (*MatchCredentialsResponse, error) {
+
+	s.log.Debugf("matching %d credential(s) against presentation definition: %s", len(request.Credentials), util.SanitizeLog(request.Definition.ID))
+
+	if len(request.Credentials) > MaxMatchCredentials {
+		errMsg := fmt.Sprintf("cannot match more than %d credentials at once, requested: %d", MaxMatchCredentials, len(request.Credentials))
+		return nil, s.log.LoggingNewError(errMsg)
+	}
+	if len(request.Definition.InputDescriptors) == 0 {
+		return nil, s.log.LoggingNewError("presentation definition has no input descriptors")
+	}
+	if unsupported := unsupportedFeatures(request.Definition); len(unsupported) > 0 {
+		return nil, s.log.LoggingError(UnsupportedDefinitionError{Features: unsupported})
+	}
+
+	// input descriptor paths are evaluated against the JSON form of each credential
+	credentials := make([]interface{}, 0, len(request.Credentials))
+	for i, cred := range request.Credentials {
+		credJSON, err := credentialJSON(cred)
+		if err != nil {
+			errMsg := fmt.Sprintf("could not read credential<%d> as JSON", i)
+			return nil, s.log.LoggingErrorMsg(err, errMsg)
+		}
+		credentials = append(credentials, credJSON)
+	}
+
+	response := MatchCredentialsResponse{Satisfied: true}
+	for d, descriptor := range request.Definition.InputDescriptors {
+		var filters []map[string]interface{}
+		if d < len(request.Filters) {
+			filters = request.Filters[d]
+		}
+		descriptorMatch := DescriptorMatch{ID: descriptor.ID}
+		for i, cred := range credentials {
+			credentialMatch, err := matchInputDescriptor(descriptor, filters, cred)
+			if err != nil {
+				errMsg := fmt.Sprintf("could not match credential<%d> against input descriptor: %s", i, descriptor.ID)
+				return nil, s.log.LoggingErrorMsg(err, errMsg)
+			}
+			credentialMatch.Index = i
+			if credentialMatch.Match {
+				descriptorMatch.Satisfied = true
+			}
+			descriptorMatch.Credentials = append(descriptorMatch.Credentials, *credentialMatch)
+		}
+		if !descriptorMatch.Satisfied {
+			response.Satisfied = false
+		}
+		response.Descriptors = append(response.Descriptors, descriptorMatch)
+	}
+	return &response, nil
+}
+
+// unsupportedFeatures lists each feature of a presentation definition which matching does not evaluate. Ignoring
+// them could report a definition as satisfied when it is not, so such definitions are rejected instead.
+func unsupportedFeatures(definition exchange.PresentationDefinition) []string {
+	var unsupported []string
+	add := func(feature string) {
+		for _, f := range unsupported {
+			if f == feature {
+				return
+			}
+		}
+		unsupported = append(unsupported, feature)
+	}
+	if len(definition.SubmissionRequirements) > 0 {
+		add("submission_requirements")
+	}
+	if definition.Format != nil {
+		add("format")
+	}
+	if definition.Frame != nil {
+		add("frame")
+	}
+	for _, descriptor := range definition.InputDescriptors {
+		if descriptor.Format != nil {
+			add("format")
+		}
+		constraints := descriptor.Constraints
+		if constraints == nil {
+			continue
+		}
+		if constraints.SubjectIsIssuer != nil || constraints.IsHolder != nil || constraints.SameSubject != nil {
+			add("relational constraints")
+		}
+		if constraints.Statuses != nil {
+			add("statuses")
+		}
+	}
+	return unsupported
+}
+
+// credentialJSON converts a credential to its generic JSON form
+func credentialJSON(cred credsdk.VerifiableCredential) (interface{}, error) {
+	credBytes, err := json.Marshal(cred)
+	if err != nil {
+		return nil, err
+	}
+	var credJSON interface{}
+	if err := json.Unmarshal(credBytes, &credJSON); err != nil {
+		return nil, err
+	}
+	return credJSON, nil
+}
+
+// matchInputDescriptor checks a credential against each field of an input descriptor, reporting the first field the
+// credential does not satisfy. A descriptor without fields is satisfied by any credential. A field's filter is taken
+// from the given filters, in the fields' order, in place of the field's own when there is one.
+func matchInputDescriptor(descriptor exchange.InputDescriptor, filters []map[string]interface{}, cred interface{}) (*CredentialMatch, error) {
+	if descriptor.Constraints == nil {
+		return &CredentialMatch{Match: true}, nil
+	}
+	for i, field := range descriptor.Constraints.Fields {
+		var filter interface{}
+		switch {
+		case i < len(filters) && filters[i] != nil:
+			filter = filters[i]
+		case field.Filter != nil:
+			filter = field.Filter
+		}
+		reason, err := matchField(field, filter, cred)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			fieldName := field.ID
+			if fieldName == "" {
+				fieldName = strings.Join(field.Path, ", ")
+			}
+			return &CredentialMatch{Field: fieldName, Reason: reason}, nil
+		}
+	}
+	return &CredentialMatch{Match: true}, nil
+}
+
+// matchField tries each of a field's paths in order, until one selects a value which passes the filter, a JSON Schema
+// evaluated in full. If none does, the reason the field is not satisfied is returned.
+func matchField(field exchange.Field, filter interface{}, cred interface{}) (string, error) {
+	var filterSchema *gojsonschema.Schema
+	if filter != nil {
+		var err error
+		if filterSchema, err = gojsonschema.NewSchema(gojsonschema.NewGoLoader(filter)); err != nil {
+			return "", fmt.Errorf("invalid field filter: %s", err.Error())
+		}
+	}
+
+	found := false
+	for _, path := range field.Path {
+		value, err := jsonpath.JsonPathLookup(cred, path)
+		if err != nil {
+			// no value at this path, so try the next
+			continue
+		}
+		found = true
+		if filterSchema == nil {
+			return "", nil
+		}
+		result, err := filterSchema.Validate(gojsonschema.NewGoLoader(value))
+		if err != nil {
+			return "", fmt.Errorf("could not evaluate field filter: %s", err.Error())
+		}
+		if result.Valid() {
+			return "", nil
+		}
+	}
+	if !found {
+		return "no value at any path", nil
+	}
+	return "no value passes the filter", nil
+}
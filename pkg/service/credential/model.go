@@ -1,27 +1,106 @@
 package credential
 
 import (
+	"fmt"
+	"time"
+
 	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/TBD54566975/ssi-sdk/credential/schema"
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
 )
 
 const (
 	SchemaType string = "JsonSchemaValidator2018"
+
+	// JSONLDFormat is the format of credentials issued by the service, following the VC data model
+	JSONLDFormat string = "json-ld"
+
+	// MaxBatchGetCredentials is the most credentials which can be requested at once by ID
+	MaxBatchGetCredentials = 100
+	// MaxBatchCreateCredentials is the most credentials which can be issued together
+	MaxBatchCreateCredentials = 100
+	// MaxCSVRows is the most rows, excluding the header, from which credentials can be issued at once
+	MaxCSVRows = MaxBatchCreateCredentials
+	// MaxMatchCredentials is the most credentials which can be matched against a presentation definition at once
+	MaxMatchCredentials = 100
+	// MaxCredentialBundleSize is the most bytes a credential bundle may be once serialized as JSON
+	MaxCredentialBundleSize = 1 << 20
+	// DefaultStoragePurgeInterval is how often credentials past their storage TTL are purged when not configured
+	DefaultStoragePurgeInterval = time.Minute
+	// deleteCredentialsBatchSize is the most credentials deleted in a single storage transaction
+	deleteCredentialsBatchSize = 100
 )
 
 type CreateCredentialRequest struct {
-	Issuer  string
+	Issuer string
+	// Either the subject's DID, or an alias registered for it
 	Subject string
 	// A context is optional. If not present, we'll apply default, required context values.
 	Context string
-	// A schema is optional. If present, we'll attempt to look it up and validate the data against it.
+	// Types of the credential in addition to VerifiableCredential, which is always included
+	Type []string
+	// A schema is optional. If present and known to the schema service, the data is validated against it.
 	JSONSchema string
 	Data       map[string]interface{}
 	Expiry     string
+	// Optionally, how long the credential is stored before it is purged, as a duration such as "15m". This is
+	// independent of the credential's expiry.
+	StorageTTL string
+	// Optionally, measure how long each phase of issuance takes
+	Timings bool
 	// TODO(gabe) support more capabilities like signature type, format, status, and more.
 }
 
 type CreateCredentialResponse struct {
 	Credential credsdk.VerifiableCredential
+	// Set when the subject was given by alias, the alias it was resolved from
+	SubjectAlias string
+	// Set when requested, how long each phase of issuance took
+	Timings *IssuanceTimings
+}
+
+// IssuanceTimings breaks down the time taken to issue a credential by phase
+type IssuanceTimings struct {
+	// Checking the request against the service's policies and building the credential, other than validation
+	Build time.Duration
+	// Resolving the credential's schema and validating its data against it
+	Validate time.Duration
+	Store    time.Duration
+}
+
+type BatchCreateCredentialsRequest struct {
+	Requests []CreateCredentialRequest
+}
+
+type BatchCreateCredentialsResponse struct {
+	Credentials []credsdk.VerifiableCredential
+}
+
+// BatchItemError identifies which request in a batch failed
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (b BatchItemError) Error() string {
+	return fmt.Sprintf("request<%d> in batch failed: %s", b.Index, b.Err.Error())
+}
+
+func (b BatchItemError) Unwrap() error {
+	return b.Err
+}
+
+// CreateCredentialsFromCSVRequest issues a credential for each row of a CSV document, the first line of which names
+// its columns
+type CreateCredentialsFromCSVRequest struct {
+	CSV string
+	// Maps column names to the '.' separated claim path each column's value is set at in a credential's data
+	Mapping map[string]string
+	// The request each credential is built from. Its issuer, subject, schema and expiry may reference a row's
+	// values by column name, as ${column}. Its data holds claims common to every row, which mapped columns override.
+	Template CreateCredentialRequest
 }
 
 type GetCredentialRequest struct {
@@ -30,24 +109,239 @@ type GetCredentialRequest struct {
 
 type GetCredentialResponse struct {
 	Credential credsdk.VerifiableCredential
+	// Set when the subject was given by alias, the alias it was resolved from
+	SubjectAlias string
+}
+
+type GetCredentialBundleRequest struct {
+	ID string
+}
+
+// GetCredentialBundleResponse holds a credential with everything needed to verify it offline
+type GetCredentialBundleResponse struct {
+	Credential credsdk.VerifiableCredential
+	// Set when the credential references a schema
+	Schema *schema.VCJSONSchema
+	// The issuer's resolved DID document, and how it was resolved
+	IssuerDID           didsdk.DIDDocument
+	IssuerDIDResolution DIDResolution
+}
+
+// DIDResolution describes which resolver resolved a DID
+type DIDResolution struct {
+	// One of LocalDIDResolver, DriverDIDResolver or UniversalDIDResolver
+	Resolver string
+	// Set when the DID was resolved remotely, the endpoint which resolved it
+	Endpoint string
+}
+
+type GetCanonicalCredentialResponse struct {
+	ID               string
+	Canonicalization string
+	// The credential in canonical form
+	Credential []byte
+	// Hex encoded SHA-256 digest of the canonical credential
+	Digest string
+}
+
+type BatchGetCredentialsRequest struct {
+	IDs []string
+}
+
+type BatchGetCredentialsResponse struct {
+	Credentials []credsdk.VerifiableCredential
+	NotFound    []string
 }
 
 type GetCredentialByIssuerRequest struct {
 	Issuer string
+	// Optionally, only get credentials with this status
+	Status string
+	// Optionally, one of the framework sort orders. By default, credentials are listed most recently issued first.
+	Sort string
 }
 
 type GetCredentialBySubjectRequest struct {
+	// Either the subject's DID, or an alias registered for it
 	Subject string
+	// Optionally, only get credentials with this status
+	Status string
+	// Optionally, one of the framework sort orders. By default, credentials are listed most recently issued first.
+	Sort string
+	// Optionally, collapse active credentials from the same issuer against the same schema to the most recent
+	Dedupe bool
 }
 
 type GetCredentialBySchemaRequest struct {
 	Schema string
+	// Optionally, only get credentials with this status
+	Status string
+	// Optionally, one of the framework sort orders. By default, credentials are listed most recently issued first.
+	Sort string
 }
 
 type GetCredentialsResponse struct {
 	Credentials []credsdk.VerifiableCredential
+	// Set when deduplicating, the number of credentials superseded by each returned credential, keyed by its ID
+	SupersededCounts map[string]int
+}
+
+// GetExpiringCredentialsRequest lists credentials which have not yet expired, but will within a window
+type GetExpiringCredentialsRequest struct {
+	Within time.Duration
+	// Optionally, only list credentials from this issuer
+	Issuer string
+}
+
+type GetExpiringCredentialsResponse struct {
+	// Credentials ordered by expiration date, soonest first
+	Credentials []credsdk.VerifiableCredential
+}
+
+type GetCredentialStatusRequest struct {
+	ID string
+}
+
+type GetCredentialStatusResponse struct {
+	ID             string
+	Status         string
+	ExpirationDate string
+}
+
+// SetIssuanceFreezeRequest freezes or unfreezes issuance for an issuer, or for all issuers if none is given
+type SetIssuanceFreezeRequest struct {
+	Issuer string
+	Frozen bool
+}
+
+type GetIssuanceFreezeResponse struct {
+	Global  bool
+	Issuers []string
+}
+
+// SetSubjectAliasRequest registers a human-friendly name by which a subject's DID may be referred to in place of
+// the DID when issuing or listing credentials
+type SetSubjectAliasRequest struct {
+	Alias string
+	DID   string
+}
+
+type GetSubjectAliasRequest struct {
+	Alias string
+}
+
+type GetSubjectAliasResponse struct {
+	Alias string
+	DID   string
+}
+
+type GetSubjectAliasesResponse struct {
+	Aliases []GetSubjectAliasResponse
+}
+
+type DeleteSubjectAliasRequest struct {
+	Alias string
 }
 
 type DeleteCredentialRequest struct {
 	ID string
 }
+
+// DeleteCredentialsRequest deletes every credential matching the given filters, at least one of which is required.
+// When more than one is given, credentials must match all of them.
+type DeleteCredentialsRequest struct {
+	Issuer  string
+	Subject string
+	Schema  string
+	// When set, matching credentials are counted but not deleted
+	DryRun bool
+}
+
+type DeleteCredentialsResponse struct {
+	// The number of credentials deleted, or which would be deleted for a dry run
+	Count int
+	// The IDs of the credentials deleted, or which would be deleted for a dry run
+	IDs []string
+	// The subject aliases deleted along with a subject's credentials, or which would be deleted for a dry run
+	Aliases []string
+	// The IDs of stored credentials which could not be read, such as those encrypted with a key which is no longer
+	// configured. They could not be checked against the filters, so were not deleted.
+	Unreadable []string
+}
+
+// ValidateCredentialRequest validates credential data against a schema, either referenced by the ID of a schema
+// known to the schema service, or provided inline. Exactly one of the two must be set.
+type ValidateCredentialRequest struct {
+	SchemaID string
+	Schema   schema.JSONSchema
+	Data     map[string]interface{}
+}
+
+type ValidateCredentialResponse struct {
+	Valid  bool
+	Errors []FieldError
+}
+
+// FieldError describes a single failure of credential data to conform to a schema
+type FieldError struct {
+	// Field is the path to the offending value, or "(root)" for the data as a whole
+	Field   string `json:"field"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type ExpandCredentialRequest struct {
+	// A JSON-LD credential
+	Credential map[string]interface{}
+}
+
+type ExpandCredentialResponse struct {
+	Expanded []interface{}
+	// Terms used by the credential which its contexts do not define, and so are dropped from the expanded form
+	UndefinedTerms []string
+}
+
+// MatchCredentialsRequest matches credentials against the input descriptors of a DIF Presentation Exchange
+// presentation definition
+type MatchCredentialsRequest struct {
+	Definition exchange.PresentationDefinition
+	// Each field's filter in its JSON form, by input descriptor and field. When given, a filter is evaluated in place
+	// of the definition's, since exchange.Filter holds only some JSON Schema keywords.
+	Filters     [][]map[string]interface{}
+	Credentials []credsdk.VerifiableCredential
+}
+
+type MatchCredentialsResponse struct {
+	// Whether every input descriptor is satisfied by at least one credential
+	Satisfied   bool
+	Descriptors []DescriptorMatch
+}
+
+// DescriptorMatch holds the result of matching each credential against an input descriptor
+type DescriptorMatch struct {
+	ID          string
+	Satisfied   bool
+	Credentials []CredentialMatch
+}
+
+type CredentialMatch struct {
+	// The position of the credential in the request
+	Index int
+	Match bool
+	// When the credential does not match, the first field it does not satisfy, by ID or else by path, and why
+	Field  string
+	Reason string
+}
+
+type GetIssuerKeyHealthRequest struct {
+	Issuer string
+}
+
+type GetIssuerKeyHealthResponse struct {
+	KeyID      string
+	Algorithm  crypto.KeyType
+	CreatedAt  string
+	Age        time.Duration
+	MaxAge     time.Duration
+	PastMaxAge bool
+}
@@ -0,0 +1,91 @@
+package credential
+
+import (
+	"fmt"
+	"time"
+
+	credstorage "github.com/tbd54566975/ssi-service/pkg/service/credential/storage"
+)
+
+// InvalidStorageTTLError is returned when a credential is requested with a storage TTL which is not a positive duration
+type InvalidStorageTTLError struct {
+	TTL string
+}
+
+func (e InvalidStorageTTLError) Error() string {
+	return fmt.Sprintf("invalid storage TTL<%s>, must be a positive duration such as 15m", e.TTL)
+}
+
+// parsePurgeInterval parses the configured interval between purges, where empty means the default interval
+func parsePurgeInterval(interval string) (time.Duration, error) {
+	if interval == "" {
+		return DefaultStoragePurgeInterval, nil
+	}
+	duration, err := time.ParseDuration(interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid storage purge interval: %s", interval)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("storage purge interval<%s> must be positive", interval)
+	}
+	return duration, nil
+}
+
+// isPurged determines whether a credential is past its storage TTL. Such credentials are treated as deleted, even
+// before they are purged.
+func (s Service) isPurged(cred credstorage.StoredCredential) bool {
+	if cred.PurgeAt == "" {
+		return false
+	}
+	purgeAt, err := time.Parse(time.RFC3339Nano, cred.PurgeAt)
+	if err != nil {
+		s.log.WithError(err).Errorf("could not parse purge time of credential: %s", cred.Credential.ID)
+		return false
+	}
+	return !s.clock().Before(purgeAt)
+}
+
+// PurgeCredentials deletes every stored credential past its storage TTL, returning how many were deleted.
+// Credentials are found through the purge index, so only those which are due are read, and since the index holds
+// their IDs, credentials which cannot otherwise be read are purged too. Credentials are deleted in batches, each in
+// its own transaction, and their index entries are removed once all of them have been.
+func (s Service) PurgeCredentials() (int, error) {
+	now := s.clock()
+	ids, err := s.storage.GetCredentialsDueForPurge(now)
+	if err != nil {
+		return 0, s.log.LoggingErrorMsg(err, "could not get credentials to purge")
+	}
+
+	for start := 0; start < len(ids); start += deleteCredentialsBatchSize {
+		end := start + deleteCredentialsBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := s.storage.DeleteCredentials(ids[start:end]); err != nil {
+			errMsg := fmt.Sprintf("could not purge credentials, %d of %d purged", start, len(ids))
+			return start, s.log.LoggingErrorMsg(err, errMsg)
+		}
+	}
+	if err := s.storage.DeletePurgeEntries(now); err != nil {
+		return len(ids), s.log.LoggingErrorMsg(err, "could not remove purged credentials from the purge index")
+	}
+	if len(ids) > 0 {
+		s.log.Infof("purged %d credential(s) past their storage TTL", len(ids))
+	}
+	return len(ids), nil
+}
+
+// RunStoragePurge purges credentials past their storage TTL at the configured interval, until stop is closed
+func (s Service) RunStoragePurge(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.purgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			// failures are logged, and the purge is retried at the next interval
+			_, _ = s.PurgeCredentials()
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package credential
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/goccy/go-json"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/internal/util"
+	didstorage "github.com/tbd54566975/ssi-service/pkg/service/did/storage"
+)
+
+const (
+	// LocalDIDResolver resolves DIDs created by the DID service
+	LocalDIDResolver string = "local"
+	// DriverDIDResolver resolves DIDs through the gateway configured for their method, or the service's own driver
+	DriverDIDResolver string = "driver"
+	// UniversalDIDResolver resolves DIDs of any method through a configured universal resolver
+	UniversalDIDResolver string = "universal"
+
+	// DefaultDIDResolutionTimeout is how long a remote resolver is waited on when no timeout is configured
+	DefaultDIDResolutionTimeout = 5 * time.Second
+
+	// maxResolvedDIDSize is the most bytes read from a remote resolver's response
+	maxResolvedDIDSize = 1 << 20
+	// didResolutionMediaType asks a universal resolver for a resolution result, holding the DID document
+	didResolutionMediaType = `application/ld+json;profile="https://w3id.org/did-resolution"`
+)
+
+// defaultDIDResolutionOrder is the order resolvers are tried in when none is configured
+var defaultDIDResolutionOrder = []string{LocalDIDResolver, DriverDIDResolver, UniversalDIDResolver}
+
+// didResolver resolves DIDs through a chain of resolvers, in a configured order
+type didResolver struct {
+	order   []string
+	storage didstorage.Storage
+	// remote resolvers by DID method
+	gateways  map[string]remoteDIDResolver
+	universal *remoteDIDResolver
+}
+
+// remoteDIDResolver resolves DIDs over HTTP, following the universal resolver's API
+type remoteDIDResolver struct {
+	endpoint   string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+func newDIDResolver(config config.DIDResolutionConfig, storage didstorage.Storage) (*didResolver, error) {
+	order := config.Order
+	if len(order) == 0 {
+		order = defaultDIDResolutionOrder
+	}
+	seen := make(map[string]bool, len(order))
+	for _, name := range order {
+		switch name {
+		case LocalDIDResolver, DriverDIDResolver, UniversalDIDResolver:
+		default:
+			return nil, fmt.Errorf("unknown DID resolver<%s>, must be one of: %s", name, strings.Join(defaultDIDResolutionOrder, ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("DID resolver<%s> cannot be ordered more than once", name)
+		}
+		seen[name] = true
+	}
+
+	resolver := didResolver{
+		order:    order,
+		storage:  storage,
+		gateways: make(map[string]remoteDIDResolver, len(config.Methods)),
+	}
+	for method, gatewayConfig := range config.Methods {
+		gateway, err := newRemoteDIDResolver(gatewayConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DID resolution gateway for method<%s>: %s", method, err.Error())
+		}
+		resolver.gateways[method] = *gateway
+	}
+	if config.Universal != nil {
+		universal, err := newRemoteDIDResolver(*config.Universal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid universal DID resolver: %s", err.Error())
+		}
+		resolver.universal = universal
+	}
+	return &resolver, nil
+}
+
+func newRemoteDIDResolver(config config.DIDResolverConfig) (*remoteDIDResolver, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("endpoint must be an absolute URL: %s", config.Endpoint)
+	}
+
+	timeout := DefaultDIDResolutionTimeout
+	if config.Timeout != "" {
+		timeout, err = time.ParseDuration(config.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("timeout must be a positive duration: %s", config.Timeout)
+		}
+	}
+
+	httpClient := http.Client{Timeout: timeout}
+	if config.Proxy != "" {
+		proxy, err := url.Parse(config.Proxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("proxy must be an absolute URL: %s", config.Proxy)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxy)
+		httpClient.Transport = transport
+	}
+
+	return &remoteDIDResolver{
+		endpoint:   strings.TrimSuffix(config.Endpoint, "/") + "/",
+		headers:    config.Headers,
+		httpClient: &httpClient,
+	}, nil
+}
+
+// resolve tries each resolver in order, reporting which one resolved the DID. When none do, the error names why
+// each failed. Each failure is logged to the given logger, so it is attributed to the request being served.
+func (r didResolver) resolve(log util.Logger, id string) (*didsdk.DIDDocument, *DIDResolution, error) {
+	method := didMethod(id)
+	var failures []string
+	for _, name := range r.order {
+		doc, endpoint, err := r.resolveWith(name, method, id)
+		if err != nil {
+			log.WithError(err).Debugf("%s resolver could not resolve DID: %s", name, util.SanitizeLog(id))
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+		return doc, &DIDResolution{Resolver: name, Endpoint: endpoint}, nil
+	}
+	return nil, nil, fmt.Errorf("no resolver could resolve DID<%s>: %s", id, strings.Join(failures, "; "))
+}
+
+// resolveWith resolves a DID with a single resolver, giving the endpoint which answered if it was resolved remotely
+func (r didResolver) resolveWith(name, method, id string) (*didsdk.DIDDocument, string, error) {
+	switch name {
+	case LocalDIDResolver:
+		gotDID, err := r.storage.GetDID(id)
+		if err != nil {
+			return nil, "", err
+		}
+		return &gotDID.DID, "", nil
+	case DriverDIDResolver:
+		if gateway, ok := r.gateways[method]; ok {
+			doc, err := gateway.resolve(id)
+			return doc, gateway.endpoint, err
+		}
+		if method == "key" {
+			doc, err := didsdk.DIDKey(id).Expand()
+			return doc, "", err
+		}
+		return nil, "", fmt.Errorf("no gateway or driver for DID method: %s", method)
+	case UniversalDIDResolver:
+		if r.universal == nil {
+			return nil, "", fmt.Errorf("no universal resolver is configured")
+		}
+		doc, err := r.universal.resolve(id)
+		return doc, r.universal.endpoint, err
+	default:
+		return nil, "", fmt.Errorf("unknown DID resolver: %s", name)
+	}
+}
+
+// resolve gets a DID's document from the remote resolver, which may respond with either a resolution result or the
+// document itself
+func (r remoteDIDResolver) resolve(id string) (*didsdk.DIDDocument, error) {
+	req, err := http.NewRequest(http.MethodGet, r.endpoint+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", didResolutionMediaType)
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("resolver responded with status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResolvedDIDSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxResolvedDIDSize {
+		return nil, fmt.Errorf("resolver response is larger than %d bytes", maxResolvedDIDSize)
+	}
+
+	var result struct {
+		DIDDocument *didsdk.DIDDocument `json:"didDocument"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("could not decode resolver response: %s", err.Error())
+	}
+	doc := result.DIDDocument
+	if doc == nil {
+		doc = new(didsdk.DIDDocument)
+		if err := json.Unmarshal(body, doc); err != nil {
+			return nil, fmt.Errorf("could not decode resolver response: %s", err.Error())
+		}
+	}
+	if doc.ID != id {
+		return nil, fmt.Errorf("resolver responded with the document of another DID: %s", doc.ID)
+	}
+	return doc, nil
+}
+
+// didMethod gets the method of a DID, which is empty if it is not a DID
+func didMethod(id string) string {
+	parts := strings.SplitN(strings.TrimPrefix(id, util.DIDPrefix), ":", 2)
+	if !strings.HasPrefix(id, util.DIDPrefix) || len(parts) != 2 {
+		return ""
+	}
+	return parts[0]
+}
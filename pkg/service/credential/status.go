@@ -0,0 +1,61 @@
+package credential
+
+import (
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+)
+
+const (
+	// StatusActive is the status of a credential which has not expired
+	StatusActive string = "active"
+	// StatusExpired is the status of a credential past its expiration date
+	StatusExpired string = "expired"
+)
+
+// IsValidStatus determines whether the status is one a credential can have
+func IsValidStatus(status string) bool {
+	return status == StatusActive || status == StatusExpired
+}
+
+// SetClock replaces the source of the current time used to date issued credentials and to determine whether
+// credentials have expired
+func (s *Service) SetClock(clock func() time.Time) {
+	s.clock = clock
+}
+
+// GetCredentialStatus gets the status of a credential, which is computed from its expiration date
+func (s Service) GetCredentialStatus(request GetCredentialStatusRequest) (*GetCredentialStatusResponse, error) {
+
+	s.log.Debugf("getting status of credential: %s", util.SanitizeLog(request.ID))
+
+	gotCred, err := s.GetCredential(GetCredentialRequest{ID: request.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetCredentialStatusResponse{
+		ID:             gotCred.Credential.ID,
+		Status:         s.credentialStatus(gotCred.Credential),
+		ExpirationDate: gotCred.Credential.ExpirationDate,
+	}, nil
+}
+
+// credentialStatus determines whether a credential has expired. Credentials without an expiration date, or with
+// one which cannot be parsed, are active.
+func (s Service) credentialStatus(cred credential.VerifiableCredential) string {
+	if cred.ExpirationDate == "" {
+		return StatusActive
+	}
+	expiresAt, err := time.Parse(time.RFC3339, cred.ExpirationDate)
+	if err != nil {
+		s.log.WithError(err).Warnf("could not parse expiration date of credential: %s", cred.ID)
+		return StatusActive
+	}
+	if expiresAt.After(s.clock()) {
+		return StatusActive
+	}
+	return StatusExpired
+}
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
@@ -15,6 +17,16 @@ import (
 const (
 	namespace                = "credential"
 	credentialNotFoundErrMsg = "credential not found"
+
+	// kept apart from credentials, since credential queries search across every key in their namespace
+	freezeNamespace = "credential-freeze"
+	freezeKey       = "issuance-freeze"
+	aliasNamespace  = "credential-alias"
+
+	// indexes credentials with a storage TTL by their purge time, so purges read only the credentials which are due
+	purgeNamespace = "credential-purge"
+	// fixed width, so that purge index keys sort in time order
+	purgeTimeFormat = "2006-01-02T15:04:05.000000000Z"
 )
 
 type BoltCredentialStorage struct {
@@ -42,9 +54,65 @@ func (b BoltCredentialStorage) StoreCredential(credential StoredCredential) erro
 		errMsg := fmt.Sprintf("could not store credential: %s", id)
 		return util.LoggingErrorMsg(err, errMsg)
 	}
+
+	// index the purge time first, so that no credential is stored without it
+	if credential.PurgeAt != "" {
+		purgeKey, err := createPurgeKey(credential.PurgeAt, id)
+		if err != nil {
+			errMsg := fmt.Sprintf("could not store credential: %s", id)
+			return util.LoggingErrorMsg(err, errMsg)
+		}
+		if err := b.db.Write(purgeNamespace, purgeKey, []byte(id)); err != nil {
+			errMsg := fmt.Sprintf("could not index purge time of credential: %s", id)
+			return util.LoggingErrorMsg(err, errMsg)
+		}
+	}
 	return b.db.Write(namespace, credential.ID, credBytes)
 }
 
+// StoreCredentials stores all the given credentials in a single transaction, so that either every credential is
+// stored or none are
+func (b BoltCredentialStorage) StoreCredentials(credentials []StoredCredential) error {
+	keys := make([]string, 0, len(credentials))
+	values := make([][]byte, 0, len(credentials))
+	var purgeKeys []string
+	var purgeValues [][]byte
+	for _, credential := range credentials {
+		id := credential.Credential.ID
+		if id == "" {
+			return util.LoggingNewError("could not store credential without an ID")
+		}
+		credential.ID = createPrefixKey(id, credential.Issuer, credential.Subject, credential.Schema)
+
+		credBytes, err := json.Marshal(credential)
+		if err != nil {
+			errMsg := fmt.Sprintf("could not store credential: %s", id)
+			return util.LoggingErrorMsg(err, errMsg)
+		}
+		keys = append(keys, credential.ID)
+		values = append(values, credBytes)
+
+		if credential.PurgeAt != "" {
+			purgeKey, err := createPurgeKey(credential.PurgeAt, id)
+			if err != nil {
+				errMsg := fmt.Sprintf("could not store credential: %s", id)
+				return util.LoggingErrorMsg(err, errMsg)
+			}
+			purgeKeys = append(purgeKeys, purgeKey)
+			purgeValues = append(purgeValues, []byte(id))
+		}
+	}
+
+	// as for a single credential, index the purge times first
+	if len(purgeKeys) > 0 {
+		if err := b.db.WriteMany(purgeNamespace, purgeKeys, purgeValues); err != nil {
+			errMsg := fmt.Sprintf("could not index purge times of %d credential(s)", len(purgeKeys))
+			return util.LoggingErrorMsg(err, errMsg)
+		}
+	}
+	return b.db.WriteMany(namespace, keys, values)
+}
+
 func (b BoltCredentialStorage) GetCredential(id string) (*StoredCredential, error) {
 	prefixValues, err := b.db.ReadPrefix(namespace, id)
 	if err != nil {
@@ -63,8 +131,7 @@ func (b BoltCredentialStorage) GetCredential(id string) (*StoredCredential, erro
 		break
 	}
 	if len(credBytes) == 0 {
-		err := fmt.Errorf("%s with id: %s", credentialNotFoundErrMsg, id)
-		return nil, util.LoggingErrorMsg(err, "could not get credential from storage")
+		return nil, util.LoggingErrorMsg(CredentialNotFoundError{ID: id}, "could not get credential from storage")
 	}
 
 	var stored StoredCredential
@@ -75,6 +142,32 @@ func (b BoltCredentialStorage) GetCredential(id string) (*StoredCredential, erro
 	return &stored, nil
 }
 
+// GetCredentials reads all credentials with the given IDs at once. IDs which do not match a credential are skipped.
+func (b BoltCredentialStorage) GetCredentials(ids []string) ([]StoredCredential, error) {
+	prefixes := make([]string, 0, len(ids))
+	for _, id := range ids {
+		prefixes = append(prefixes, createIDPrefix(id))
+	}
+	prefixValues, err := b.db.ReadPrefixes(namespace, prefixes)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get credentials from storage: %s", ids)
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+
+	var storedCreds []StoredCredential
+	for _, prefix := range prefixes {
+		for key, credBytes := range prefixValues[prefix] {
+			var cred StoredCredential
+			if err := json.Unmarshal(credBytes, &cred); err != nil {
+				logrus.WithError(err).Errorf("could not unmarshal credential with key: %s", key)
+				continue
+			}
+			storedCreds = append(storedCreds, cred)
+		}
+	}
+	return storedCreds, nil
+}
+
 // Note: this is a lazy  implementation. Optimizations are to be had by adjusting prefix
 // queries, and nested buckets. It is not intended that bolt is run in production, or at any scale,
 // so this is not much of a concern.
@@ -119,6 +212,7 @@ func (b BoltCredentialStorage) GetCredentialsByIssuer(issuer string) ([]StoredCr
 		logrus.Warnf("no credentials able to be retrieved for issuer: %s", issuerKeys)
 	}
 
+	SortCredentials(storedCreds)
 	return storedCreds, nil
 }
 
@@ -163,6 +257,7 @@ func (b BoltCredentialStorage) GetCredentialsBySubject(subject string) ([]Stored
 		logrus.Warnf("no credentials able to be retrieved for subject: %s", subjectKeys)
 	}
 
+	SortCredentials(storedCreds)
 	return storedCreds, nil
 }
 
@@ -208,9 +303,34 @@ func (b BoltCredentialStorage) GetCredentialsBySchema(schema string) ([]StoredCr
 		logrus.Warnf("no credentials able to be retrieved for schema: %s", schemaKeys)
 	}
 
+	SortCredentials(storedCreds)
 	return storedCreds, nil
 }
 
+// GetAllCredentials gets every stored credential. Like the other queries, credentials which cannot be read are
+// logged and skipped.
+func (b BoltCredentialStorage) GetAllCredentials() ([]StoredCredential, error) {
+	gotCreds, err := b.db.ReadAll(namespace)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "could not read all credentials from storage")
+	}
+	storedCreds := make([]StoredCredential, 0, len(gotCreds))
+	for key, credBytes := range gotCreds {
+		var cred StoredCredential
+		if err := json.Unmarshal(credBytes, &cred); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal credential with key: %s", key)
+			continue
+		}
+		storedCreds = append(storedCreds, cred)
+	}
+	return storedCreds, nil
+}
+
+// GetUnreadableCredentials gets nothing, since every credential bolt storage holds is stored as it is read
+func (b BoltCredentialStorage) GetUnreadableCredentials() ([]string, error) {
+	return nil, nil
+}
+
 func (b BoltCredentialStorage) DeleteCredential(id string) error {
 	credDoesNotExistMsg := fmt.Sprintf("credential does not exist, cannot delete: %s", id)
 
@@ -242,7 +362,165 @@ func (b BoltCredentialStorage) DeleteCredential(id string) error {
 	return nil
 }
 
+// DeleteCredentials deletes the credentials with the given IDs in a single transaction. IDs which do not exist are
+// skipped.
+func (b BoltCredentialStorage) DeleteCredentials(ids []string) error {
+	prefixes := make([]string, 0, len(ids))
+	for _, id := range ids {
+		prefixes = append(prefixes, createIDPrefix(id))
+	}
+	prefixValues, err := b.db.ReadPrefixes(namespace, prefixes)
+	if err != nil {
+		return util.LoggingErrorMsg(err, "could not get credentials before deletion")
+	}
+
+	var keys []string
+	for _, values := range prefixValues {
+		for key := range values {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := b.db.DeleteMany(namespace, keys); err != nil {
+		errMsg := fmt.Sprintf("could not delete %d credential(s)", len(keys))
+		return util.LoggingErrorMsg(err, errMsg)
+	}
+	return nil
+}
+
+// GetCredentialsDueForPurge gets the IDs of credentials whose purge time is at or before the given time, in purge
+// time order. Only the purge index entries which are due are read, rather than every credential.
+func (b BoltCredentialStorage) GetCredentialsDueForPurge(now time.Time) ([]string, error) {
+	purgeKeys, err := b.readDuePurgeKeys(now)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "could not read purge index from storage")
+	}
+	ids := make([]string, 0, len(purgeKeys))
+	for _, key := range purgeKeys {
+		ids = append(ids, strings.SplitN(key, "/", 2)[1])
+	}
+	return ids, nil
+}
+
+// DeletePurgeEntries deletes the purge index entries whose purge time is at or before the given time, once their
+// credentials have been purged. Entries left behind by credentials which were deleted before their purge time are
+// deleted along with them.
+func (b BoltCredentialStorage) DeletePurgeEntries(now time.Time) error {
+	purgeKeys, err := b.readDuePurgeKeys(now)
+	if err != nil {
+		return util.LoggingErrorMsg(err, "could not read purge index from storage")
+	}
+	if len(purgeKeys) == 0 {
+		return nil
+	}
+	if err := b.db.DeleteMany(purgeNamespace, purgeKeys); err != nil {
+		errMsg := fmt.Sprintf("could not delete %d purge index entries", len(purgeKeys))
+		return util.LoggingErrorMsg(err, errMsg)
+	}
+	return nil
+}
+
+// readDuePurgeKeys reads the purge index keys at or before the given time, which are exactly those sorting before
+// the keys of the next instant
+func (b BoltCredentialStorage) readDuePurgeKeys(now time.Time) ([]string, error) {
+	return b.db.ReadKeysBefore(purgeNamespace, now.Add(time.Nanosecond).UTC().Format(purgeTimeFormat))
+}
+
+// GetIssuanceFreeze gets the current issuance freeze. When none has been stored, issuance is not frozen.
+func (b BoltCredentialStorage) GetIssuanceFreeze() (*IssuanceFreeze, error) {
+	freezeBytes, err := b.db.Read(freezeNamespace, freezeKey)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "could not get issuance freeze from storage")
+	}
+	var freeze IssuanceFreeze
+	if len(freezeBytes) == 0 {
+		return &freeze, nil
+	}
+	if err := json.Unmarshal(freezeBytes, &freeze); err != nil {
+		return nil, util.LoggingErrorMsg(err, "could not unmarshal stored issuance freeze")
+	}
+	return &freeze, nil
+}
+
+func (b BoltCredentialStorage) StoreIssuanceFreeze(freeze IssuanceFreeze) error {
+	freezeBytes, err := json.Marshal(freeze)
+	if err != nil {
+		return util.LoggingErrorMsg(err, "could not store issuance freeze")
+	}
+	return b.db.Write(freezeNamespace, freezeKey, freezeBytes)
+}
+
+// GetSubjectAlias gets the alias with the given name, or nil if there is none
+func (b BoltCredentialStorage) GetSubjectAlias(alias string) (*SubjectAlias, error) {
+	aliasBytes, err := b.db.Read(aliasNamespace, alias)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get subject alias from storage: %s", alias)
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+	if len(aliasBytes) == 0 {
+		return nil, nil
+	}
+	var stored SubjectAlias
+	if err := json.Unmarshal(aliasBytes, &stored); err != nil {
+		errMsg := fmt.Sprintf("could not unmarshal stored subject alias: %s", alias)
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+	return &stored, nil
+}
+
+// GetSubjectAliases gets every alias, ordered by name. Aliases which cannot be read are logged and skipped.
+func (b BoltCredentialStorage) GetSubjectAliases() ([]SubjectAlias, error) {
+	gotAliases, err := b.db.ReadAll(aliasNamespace)
+	if err != nil {
+		return nil, util.LoggingErrorMsg(err, "could not read all subject aliases from storage")
+	}
+	aliases := make([]SubjectAlias, 0, len(gotAliases))
+	for key, aliasBytes := range gotAliases {
+		var stored SubjectAlias
+		if err := json.Unmarshal(aliasBytes, &stored); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal subject alias with key: %s", key)
+			continue
+		}
+		aliases = append(aliases, stored)
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Alias < aliases[j].Alias })
+	return aliases, nil
+}
+
+func (b BoltCredentialStorage) StoreSubjectAlias(alias SubjectAlias) error {
+	aliasBytes, err := json.Marshal(alias)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not store subject alias: %s", alias.Alias)
+		return util.LoggingErrorMsg(err, errMsg)
+	}
+	return b.db.Write(aliasNamespace, alias.Alias, aliasBytes)
+}
+
+func (b BoltCredentialStorage) DeleteSubjectAlias(alias string) error {
+	if err := b.db.Delete(aliasNamespace, alias); err != nil {
+		errMsg := fmt.Sprintf("could not delete subject alias: %s", alias)
+		return util.LoggingErrorMsg(err, errMsg)
+	}
+	return nil
+}
+
 // unique key for a credential
 func createPrefixKey(id, issuer, subject, schema string) string {
 	return strings.Join([]string{id, "is:" + issuer, "su:" + subject, "sc:" + schema}, "-")
 }
+
+// the portion of a credential's prefix key which is determined by its ID alone
+func createIDPrefix(id string) string {
+	return id + "-is:"
+}
+
+// key for a credential in the purge index, ordered by its purge time
+func createPurgeKey(purgeAt, id string) (string, error) {
+	purgeTime, err := time.Parse(time.RFC3339Nano, purgeAt)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid purge time: %s", purgeAt)
+	}
+	return purgeTime.UTC().Format(purgeTimeFormat) + "/" + id, nil
+}
@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+)
+
+// EncryptedCredentialStorage wraps another credential storage implementation, encrypting each stored credential
+// before it is written and decrypting it on read. The indexed values (issuer, subject, schema) are replaced by
+// their HMACs, keyed by the encryption key, so that they remain searchable without being stored in the clear.
+type EncryptedCredentialStorage struct {
+	storage          Storage
+	keys             map[string][]byte
+	indexKeys        map[string][]byte
+	activeKeyVersion string
+}
+
+// indexKeyInfo separates the key used to index values from the encryption key it is derived from
+const indexKeyInfo = "ssi-service credential index"
+
+// NewEncryptedCredentialStorage creates an encrypting credential storage wrapper. Keys are base58 encoded 32 byte
+// XChaCha20-Poly1305 keys, identified by a version. New records are always encrypted with the active key version,
+// while records encrypted with any other known version can still be read, which allows for key rotation.
+func NewEncryptedCredentialStorage(s Storage, keys map[string]string, activeKeyVersion string) (*EncryptedCredentialStorage, error) {
+	if s == nil {
+		return nil, errors.New("credential storage reference is nil")
+	}
+	if _, ok := keys[activeKeyVersion]; !ok {
+		return nil, fmt.Errorf("no encryption key configured for active key version: %s", activeKeyVersion)
+	}
+	decodedKeys := make(map[string][]byte, len(keys))
+	indexKeys := make(map[string][]byte, len(keys))
+	for version, encodedKey := range keys {
+		key, err := base58.Decode(encodedKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode encryption key version: %s", version)
+		}
+		if len(key) != chacha20poly1305.KeySize {
+			return nil, fmt.Errorf("encryption key version<%s> must be %d bytes", version, chacha20poly1305.KeySize)
+		}
+		decodedKeys[version] = key
+		indexKeys[version] = hmacSHA256(key, []byte(indexKeyInfo))
+	}
+	return &EncryptedCredentialStorage{
+		storage:          s,
+		keys:             decodedKeys,
+		indexKeys:        indexKeys,
+		activeKeyVersion: activeKeyVersion,
+	}, nil
+}
+
+func (e EncryptedCredentialStorage) StoreCredential(cred StoredCredential) error {
+	sealed, err := e.sealCredential(cred)
+	if err != nil {
+		return err
+	}
+	return e.storage.StoreCredential(*sealed)
+}
+
+func (e EncryptedCredentialStorage) StoreCredentials(creds []StoredCredential) error {
+	sealedCreds := make([]StoredCredential, 0, len(creds))
+	for _, cred := range creds {
+		sealed, err := e.sealCredential(cred)
+		if err != nil {
+			return err
+		}
+		sealedCreds = append(sealedCreds, *sealed)
+	}
+	return e.storage.StoreCredentials(sealedCreds)
+}
+
+// sealCredential encrypts a credential with the active key. Only the credential's ID and purge time are kept in the
+// clear, since they are needed to look up the record and to purge it.
+func (e EncryptedCredentialStorage) sealCredential(cred StoredCredential) (*StoredCredential, error) {
+	id := cred.Credential.ID
+	credBytes, err := json.Marshal(cred)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not marshal credential for encryption: %s", id)
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+	encrypted, err := util.XChaCha20Poly1305Encrypt(e.keys[e.activeKeyVersion], credBytes)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not encrypt credential: %s", id)
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+
+	indexKey := e.indexKeys[e.activeKeyVersion]
+	return &StoredCredential{
+		ID:                  cred.ID,
+		Credential:          credential.VerifiableCredential{ID: id},
+		Issuer:              hashIndexValue(indexKey, cred.Issuer),
+		Subject:             hashIndexValue(indexKey, cred.Subject),
+		Schema:              hashIndexValue(indexKey, cred.Schema),
+		IssuanceDate:        cred.IssuanceDate,
+		PurgeAt:             cred.PurgeAt,
+		EncryptedCredential: encrypted,
+		KeyVersion:          e.activeKeyVersion,
+	}, nil
+}
+
+// GetIssuanceFreeze is not encrypted, since it holds no credential data
+func (e EncryptedCredentialStorage) GetIssuanceFreeze() (*IssuanceFreeze, error) {
+	return e.storage.GetIssuanceFreeze()
+}
+
+func (e EncryptedCredentialStorage) StoreIssuanceFreeze(freeze IssuanceFreeze) error {
+	return e.storage.StoreIssuanceFreeze(freeze)
+}
+
+// Subject aliases are not encrypted, since they are looked up by name and hold no credential data
+func (e EncryptedCredentialStorage) GetSubjectAlias(alias string) (*SubjectAlias, error) {
+	return e.storage.GetSubjectAlias(alias)
+}
+
+func (e EncryptedCredentialStorage) GetSubjectAliases() ([]SubjectAlias, error) {
+	return e.storage.GetSubjectAliases()
+}
+
+func (e EncryptedCredentialStorage) StoreSubjectAlias(alias SubjectAlias) error {
+	return e.storage.StoreSubjectAlias(alias)
+}
+
+func (e EncryptedCredentialStorage) DeleteSubjectAlias(alias string) error {
+	return e.storage.DeleteSubjectAlias(alias)
+}
+
+func (e EncryptedCredentialStorage) GetCredential(id string) (*StoredCredential, error) {
+	gotCred, err := e.storage.GetCredential(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.decryptCredential(*gotCred)
+}
+
+func (e EncryptedCredentialStorage) GetCredentials(ids []string) ([]StoredCredential, error) {
+	gotCreds, err := e.storage.GetCredentials(ids)
+	if err != nil {
+		return nil, err
+	}
+	return e.decryptCredentials(gotCreds), nil
+}
+
+func (e EncryptedCredentialStorage) GetCredentialsByIssuer(issuer string) ([]StoredCredential, error) {
+	return e.getCredentialsByIndex(issuer, e.storage.GetCredentialsByIssuer)
+}
+
+func (e EncryptedCredentialStorage) GetCredentialsBySubject(subject string) ([]StoredCredential, error) {
+	return e.getCredentialsByIndex(subject, e.storage.GetCredentialsBySubject)
+}
+
+func (e EncryptedCredentialStorage) GetCredentialsBySchema(schema string) ([]StoredCredential, error) {
+	return e.getCredentialsByIndex(schema, e.storage.GetCredentialsBySchema)
+}
+
+func (e EncryptedCredentialStorage) GetAllCredentials() ([]StoredCredential, error) {
+	gotCreds, err := e.storage.GetAllCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return e.decryptCredentials(gotCreds), nil
+}
+
+// GetUnreadableCredentials gets the IDs of the records which cannot be decrypted with the configured keys. Such
+// records cannot be found by their indexed values either, since those are hashed with keys derived from the same.
+func (e EncryptedCredentialStorage) GetUnreadableCredentials() ([]string, error) {
+	gotCreds, err := e.storage.GetAllCredentials()
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, cred := range gotCreds {
+		if _, err := e.decryptCredential(cred); err != nil {
+			ids = append(ids, cred.Credential.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// GetCredentialsDueForPurge needs no decryption, since purge times are kept in the clear
+func (e EncryptedCredentialStorage) GetCredentialsDueForPurge(now time.Time) ([]string, error) {
+	return e.storage.GetCredentialsDueForPurge(now)
+}
+
+func (e EncryptedCredentialStorage) DeletePurgeEntries(now time.Time) error {
+	return e.storage.DeletePurgeEntries(now)
+}
+
+func (e EncryptedCredentialStorage) DeleteCredential(id string) error {
+	return e.storage.DeleteCredential(id)
+}
+
+func (e EncryptedCredentialStorage) DeleteCredentials(ids []string) error {
+	return e.storage.DeleteCredentials(ids)
+}
+
+// getCredentialsByIndex looks up credentials by an indexed value. Records are indexed by the value's HMAC under the
+// key version they were encrypted with, or by the value itself if they were written before encryption was enabled,
+// so each form is looked up.
+func (e EncryptedCredentialStorage) getCredentialsByIndex(value string, lookup func(string) ([]StoredCredential, error)) ([]StoredCredential, error) {
+	lookupValues := []string{value}
+	for _, indexKey := range e.indexKeys {
+		lookupValues = append(lookupValues, hashIndexValue(indexKey, value))
+	}
+
+	seen := make(map[string]bool)
+	var gotCreds []StoredCredential
+	for _, lookupValue := range lookupValues {
+		creds, err := lookup(lookupValue)
+		if err != nil {
+			return nil, err
+		}
+		for _, cred := range creds {
+			if !seen[cred.ID] {
+				seen[cred.ID] = true
+				gotCreds = append(gotCreds, cred)
+			}
+		}
+	}
+	decrypted := e.decryptCredentials(gotCreds)
+	SortCredentials(decrypted)
+	return decrypted, nil
+}
+
+// decryptCredentials opens each of a list of credential records. A record which cannot be decrypted, such as one
+// encrypted with a key which is no longer configured, is logged and left out rather than failing the whole list.
+func (e EncryptedCredentialStorage) decryptCredentials(creds []StoredCredential) []StoredCredential {
+	var decrypted []StoredCredential
+	for _, cred := range creds {
+		decryptedCred, err := e.decryptCredential(cred)
+		if err != nil {
+			logrus.WithError(err).Errorf("skipping credential which could not be decrypted: %s", cred.ID)
+			continue
+		}
+		decrypted = append(decrypted, *decryptedCred)
+	}
+	return decrypted
+}
+
+// decryptCredential opens an encrypted credential record. Records without a key version were written before
+// encryption was enabled and are returned as-is.
+func (e EncryptedCredentialStorage) decryptCredential(cred StoredCredential) (*StoredCredential, error) {
+	if cred.KeyVersion == "" {
+		return &cred, nil
+	}
+	key, ok := e.keys[cred.KeyVersion]
+	if !ok {
+		err := fmt.Errorf("no encryption key configured for key version: %s", cred.KeyVersion)
+		return nil, util.LoggingErrorMsg(err, "could not decrypt credential")
+	}
+	credBytes, err := util.XChaCha20Poly1305Decrypt(key, cred.EncryptedCredential)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not decrypt credential: %s", cred.Credential.ID)
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+	var decrypted StoredCredential
+	if err := json.Unmarshal(credBytes, &decrypted); err != nil {
+		errMsg := fmt.Sprintf("could not unmarshal decrypted credential: %s", cred.Credential.ID)
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+	decrypted.ID = cred.ID
+	return &decrypted, nil
+}
+
+// hashIndexValue produces the searchable form of an indexed value, an HMAC under the given index key
+func hashIndexValue(indexKey []byte, value string) string {
+	return hex.EncodeToString(hmacSHA256(indexKey, []byte(value)))
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -2,6 +2,8 @@ package storage
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/TBD54566975/ssi-sdk/credential"
 
@@ -16,15 +18,71 @@ type StoredCredential struct {
 	Subject      string                          `json:"subject"`
 	Schema       string                          `json:"schema"`
 	IssuanceDate string                          `json:"issuanceDate"`
+	// Set when the credential was requested for a subject by alias, the alias the subject was resolved from
+	SubjectAlias string `json:"subjectAlias,omitempty"`
+	// When set, the time after which the credential is purged from storage, independent of its expiration date
+	PurgeAt string `json:"purgeAt,omitempty"`
+
+	// Set when the credential is encrypted at rest, in which case the fields above hold only the credential's ID
+	// and hashes of the indexed values
+	EncryptedCredential []byte `json:"encryptedCredential,omitempty"`
+	KeyVersion          string `json:"keyVersion,omitempty"`
+}
+
+// CredentialNotFoundError is returned when no credential is stored with an ID
+type CredentialNotFoundError struct {
+	ID string
+}
+
+func (e CredentialNotFoundError) Error() string {
+	return fmt.Sprintf("%s with id: %s", credentialNotFoundErrMsg, e.ID)
+}
+
+// IssuanceFreeze records whether issuance is halted, either for all issuers or for specific issuers
+type IssuanceFreeze struct {
+	Global  bool     `json:"global"`
+	Issuers []string `json:"issuers,omitempty"`
+}
+
+// SubjectAlias maps a human-friendly name for a credential subject to the subject's DID
+type SubjectAlias struct {
+	Alias string `json:"alias"`
+	DID   string `json:"did"`
 }
 
 type Storage interface {
 	StoreCredential(credential StoredCredential) error
+	// StoreCredentials stores all the given credentials, or none of them if any cannot be stored
+	StoreCredentials(credentials []StoredCredential) error
 	GetCredential(id string) (*StoredCredential, error)
+	// GetCredentials gets each of the credentials with the given IDs which exist
+	GetCredentials(ids []string) ([]StoredCredential, error)
+	// GetCredentialsByIssuer, GetCredentialsBySubject and GetCredentialsBySchema return credentials in their
+	// default order, see SortCredentials
 	GetCredentialsByIssuer(issuer string) ([]StoredCredential, error)
 	GetCredentialsBySubject(subject string) ([]StoredCredential, error)
 	GetCredentialsBySchema(schema string) ([]StoredCredential, error)
+	// GetAllCredentials gets every stored credential, in no particular order
+	GetAllCredentials() ([]StoredCredential, error)
+	// GetUnreadableCredentials gets the IDs of stored credentials which cannot be read, such as those encrypted with
+	// a key which is no longer configured. Every other lookup leaves them out.
+	GetUnreadableCredentials() ([]string, error)
+	// GetCredentialsDueForPurge gets the IDs of credentials whose purge time is at or before the given time, from an
+	// index of purge times rather than by reading every credential
+	GetCredentialsDueForPurge(now time.Time) ([]string, error)
+	// DeletePurgeEntries removes the purge times at or before the given time from the index
+	DeletePurgeEntries(now time.Time) error
 	DeleteCredential(id string) error
+	// DeleteCredentials deletes all the credentials with the given IDs, or none of them if any cannot be deleted
+	DeleteCredentials(ids []string) error
+	GetIssuanceFreeze() (*IssuanceFreeze, error)
+	StoreIssuanceFreeze(freeze IssuanceFreeze) error
+	// GetSubjectAlias gets the alias with the given name, or nil if there is none
+	GetSubjectAlias(alias string) (*SubjectAlias, error)
+	// GetSubjectAliases gets every alias, ordered by name
+	GetSubjectAliases() ([]SubjectAlias, error)
+	StoreSubjectAlias(alias SubjectAlias) error
+	DeleteSubjectAlias(alias string) error
 }
 
 func NewCredentialStorage(s storage.ServiceStorage) (Storage, error) {
@@ -45,3 +103,36 @@ func NewCredentialStorage(s storage.ServiceStorage) (Storage, error) {
 		return nil, util.LoggingError(errMsg)
 	}
 }
+
+// SortCredentials sorts credentials into their default order: most recently issued first, then by ID
+func SortCredentials(creds []StoredCredential) {
+	sort.SliceStable(creds, func(i, j int) bool {
+		if c := CompareIssuanceDates(creds[i], creds[j]); c != 0 {
+			return c > 0
+		}
+		return creds[i].Credential.ID < creds[j].Credential.ID
+	})
+}
+
+// CompareIssuanceDates returns a positive number if a was issued after b, a negative number if before, and zero if
+// they were issued at the same time. Dates which cannot be parsed as RFC3339 are compared as strings.
+func CompareIssuanceDates(a, b StoredCredential) int {
+	aTime, aErr := time.Parse(time.RFC3339, a.IssuanceDate)
+	bTime, bErr := time.Parse(time.RFC3339, b.IssuanceDate)
+	switch {
+	case aErr != nil || bErr != nil:
+		if a.IssuanceDate == b.IssuanceDate {
+			return 0
+		}
+		if a.IssuanceDate > b.IssuanceDate {
+			return 1
+		}
+		return -1
+	case aTime.After(bTime):
+		return 1
+	case aTime.Before(bTime):
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,38 @@
+package credential
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// PrecisionSeconds formats credential timestamps without fractional seconds
+	PrecisionSeconds string = "seconds"
+	// PrecisionMilliseconds formats credential timestamps with exactly three fractional digits
+	PrecisionMilliseconds string = "milliseconds"
+
+	rfc3339Milli = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// timestampLayoutForPrecision gets the RFC 3339 layout for the configured timestamp precision, where empty means
+// seconds
+func timestampLayoutForPrecision(precision string) (string, error) {
+	switch precision {
+	case "", PrecisionSeconds:
+		return time.RFC3339, nil
+	case PrecisionMilliseconds:
+		return rfc3339Milli, nil
+	default:
+		return "", fmt.Errorf("invalid timestamp precision<%s>, must be one of: %s, %s", precision, PrecisionSeconds, PrecisionMilliseconds)
+	}
+}
+
+// formatTimestamp reformats a requested RFC 3339 timestamp to the service's precision, keeping its offset. Greater
+// precision is truncated. Anything else is returned as is, to be rejected when set on the credential.
+func (s Service) formatTimestamp(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.Format(s.timestampLayout)
+}
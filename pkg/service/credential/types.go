@@ -0,0 +1,38 @@
+package credential
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+)
+
+// DisallowedTypeError is returned when a credential is requested with types not in the service's allowlist
+type DisallowedTypeError struct {
+	Types []string
+}
+
+func (d DisallowedTypeError) Error() string {
+	return fmt.Sprintf("credential type(s) not allowed: %s", strings.Join(d.Types, ", "))
+}
+
+// disallowedTypes returns each of the requested types which is not allowed. An empty allowlist allows any type, and
+// the base VerifiableCredential type is always allowed.
+func disallowedTypes(allowed []string, requested []string) []string {
+	if len(allowed) == 0 {
+		return nil
+	}
+	allowedSet := make(map[string]bool, len(allowed)+1)
+	allowedSet[credential.VerifiableCredentialType] = true
+	for _, t := range allowed {
+		allowedSet[t] = true
+	}
+
+	var disallowed []string
+	for _, t := range requested {
+		if !allowedSet[t] {
+			disallowed = append(disallowed, t)
+		}
+	}
+	return disallowed
+}
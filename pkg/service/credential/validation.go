@@ -0,0 +1,273 @@
+package credential
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/credential/schema"
+	"github.com/goccy/go-json"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+	schemastorage "github.com/tbd54566975/ssi-service/pkg/service/schema/storage"
+)
+
+// ValidateCredential checks credential data against a schema without building, signing, or storing a credential.
+// Data that does not conform to the schema is not an error; it results in a response listing each field error.
+func (s Service) ValidateCredential(request ValidateCredentialRequest) (*ValidateCredentialResponse, error) {
+
+	s.log.Debugf("validating credential data against schema: %s", util.SanitizeLog(request.SchemaID))
+
+	hasReference, hasInline := request.SchemaID != "", len(request.Schema) > 0
+	if hasReference == hasInline {
+		return nil, s.log.LoggingNewError("exactly one of a schema reference or an inline schema must be provided")
+	}
+
+	jsonSchema, strict := request.Schema, s.config.StrictValidation
+	if hasReference {
+		gotSchema, gotStrict, err := s.resolveSchema(request.SchemaID)
+		if err != nil {
+			return nil, err
+		}
+		jsonSchema, strict = gotSchema, gotStrict
+	}
+
+	fieldErrors, err := s.validateAgainstSchema(jsonSchema, request.Data, strict)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidateCredentialResponse{Valid: len(fieldErrors) == 0, Errors: fieldErrors}, nil
+}
+
+// InvalidCredentialDataError is returned when the data of a credential to be issued does not conform to its schema
+type InvalidCredentialDataError struct {
+	Schema string
+	Errors []FieldError
+}
+
+func (e InvalidCredentialDataError) Error() string {
+	fields := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		fields = append(fields, fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Message))
+	}
+	return fmt.Sprintf("credential data does not conform to schema<%s>: %s", e.Schema, strings.Join(fields, "; "))
+}
+
+const (
+	// SchemaNotFoundFail rejects a credential referencing a schema the schema service does not know
+	SchemaNotFoundFail string = "fail"
+	// SchemaNotFoundWarn issues a credential referencing a schema the schema service does not know, logging a warning
+	SchemaNotFoundWarn string = "warn"
+	// SchemaNotFoundInfo issues a credential referencing a schema the schema service does not know, logging at the
+	// info level
+	SchemaNotFoundInfo string = "info"
+)
+
+// UnknownSchemaError is returned when a credential to be issued references a schema the schema service does not
+// know, and the service is configured to reject such credentials
+type UnknownSchemaError struct {
+	Schema string
+}
+
+func (e UnknownSchemaError) Error() string {
+	return fmt.Sprintf("schema<%s> is not known to the schema service", e.Schema)
+}
+
+// schemaNotFoundPolicyFor checks the configured schema not found policy, where empty means fail
+func schemaNotFoundPolicyFor(policy string) (string, error) {
+	switch policy {
+	case "":
+		return SchemaNotFoundFail, nil
+	case SchemaNotFoundFail, SchemaNotFoundWarn, SchemaNotFoundInfo:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid schema not found policy<%s>, must be one of: %s, %s, %s", policy, SchemaNotFoundFail, SchemaNotFoundWarn, SchemaNotFoundInfo)
+	}
+}
+
+// validateCredentialData validates the data of a credential to be issued against the schema it references, strictly
+// if the schema or the service requires. Only schemas known to the schema service can be resolved. A credential
+// referencing any other schema, such as by an external URL, is rejected or issued without its data being validated,
+// following the service's schema not found policy.
+func (s Service) validateCredentialData(schemaID string, data map[string]interface{}) error {
+	if schemaID == "" {
+		return nil
+	}
+	jsonSchema, strict, err := s.resolveSchema(schemaID)
+	if err != nil {
+		if !errors.As(err, &schemastorage.SchemaNotFoundError{}) {
+			return err
+		}
+		msg := fmt.Sprintf("schema<%s> is not known to the schema service, credential data is not validated against it", util.SanitizeLog(schemaID))
+		switch s.schemaNotFoundPolicy {
+		case SchemaNotFoundWarn:
+			s.log.Warn(msg)
+			return nil
+		case SchemaNotFoundInfo:
+			s.log.Info(msg)
+			return nil
+		default:
+			return s.log.LoggingError(UnknownSchemaError{Schema: schemaID})
+		}
+	}
+	fieldErrors, err := s.validateAgainstSchema(jsonSchema, data, strict)
+	if err != nil {
+		return err
+	}
+	if len(fieldErrors) > 0 {
+		return s.log.LoggingError(InvalidCredentialDataError{Schema: schemaID, Errors: fieldErrors})
+	}
+	return nil
+}
+
+// resolveSchema looks up the JSON Schema for a schema known to the schema service, and whether data is validated
+// against it strictly
+func (s Service) resolveSchema(id string) (schema.JSONSchema, bool, error) {
+	gotSchema, err := s.schemaStorage.GetSchema(id)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not resolve schema: %s", id)
+		// whether a schema which is not found is an error is for the caller to decide
+		if errors.As(err, &schemastorage.SchemaNotFoundError{}) {
+			return nil, false, fmt.Errorf("%s: %w", errMsg, err)
+		}
+		return nil, false, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	strict := s.config.StrictValidation
+	if gotSchema.StrictValidation != nil {
+		strict = *gotSchema.StrictValidation
+	}
+	return gotSchema.Schema.Schema, strict, nil
+}
+
+// validateAgainstSchema validates data against a JSON Schema, returning an entry for each field that does not
+// conform. An error is returned only if validation could not be performed, such as for an invalid schema.
+// Strict validation also reports each property the schema does not define, naming the property.
+func (s Service) validateAgainstSchema(jsonSchema schema.JSONSchema, data map[string]interface{}, strict bool) ([]FieldError, error) {
+	if strict {
+		jsonSchema = disallowAdditionalProperties(jsonSchema)
+	}
+	schemaBytes, err := json.Marshal(jsonSchema)
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not marshal schema")
+	}
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not marshal credential data")
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaBytes), gojsonschema.NewBytesLoader(dataBytes))
+	if err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "could not validate credential data against schema")
+	}
+
+	var fieldErrors []FieldError
+	for _, resultErr := range result.Errors() {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   resultErr.Field(),
+			Type:    resultErr.Type(),
+			Message: resultErr.Description(),
+		})
+	}
+	return fieldErrors, nil
+}
+
+// disallowAdditionalProperties copies a schema, disallowing properties it does not define for every object schema
+// which defines its properties, including nested schemas. Properties defined by the branches of a composition
+// (allOf, anyOf, oneOf, if, then, else) apply to the same object, so they are allowed alongside the schema's own,
+// and only the schema holding the composition is tightened. An additionalProperties sub-schema is left as it is.
+func disallowAdditionalProperties(jsonSchema map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(jsonSchema)+1)
+	for keyword, value := range jsonSchema {
+		switch keyword {
+		case "properties", "patternProperties", "definitions", "$defs":
+			// maps of property or definition names to schemas
+			if schemas, ok := value.(map[string]interface{}); ok {
+				copiedSchemas := make(map[string]interface{}, len(schemas))
+				for name, subSchema := range schemas {
+					copiedSchemas[name] = disallowInSubSchema(subSchema)
+				}
+				value = copiedSchemas
+			}
+		case "items", "additionalItems":
+			value = disallowInSubSchema(value)
+		}
+		copied[keyword] = value
+	}
+
+	if _, ok := jsonSchema["additionalProperties"].(map[string]interface{}); ok {
+		return copied
+	}
+	properties, patterns := make(map[string]bool), make(map[string]bool)
+	collectProperties(jsonSchema, properties, patterns)
+	if len(properties) == 0 && len(patterns) == 0 {
+		if _, ok := jsonSchema["properties"]; !ok {
+			return copied
+		}
+	}
+	copied["properties"] = allowNames(copied["properties"], properties)
+	if len(patterns) > 0 {
+		copied["patternProperties"] = allowNames(copied["patternProperties"], patterns)
+	}
+	copied["additionalProperties"] = false
+	return copied
+}
+
+// collectProperties gathers the names and patterns of the properties a schema defines, including those defined by
+// the branches of its compositions
+func collectProperties(jsonSchema map[string]interface{}, properties, patterns map[string]bool) {
+	for name := range asSchemaMap(jsonSchema["properties"]) {
+		properties[name] = true
+	}
+	for pattern := range asSchemaMap(jsonSchema["patternProperties"]) {
+		patterns[pattern] = true
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf", "if", "then", "else"} {
+		switch v := jsonSchema[keyword].(type) {
+		case map[string]interface{}:
+			collectProperties(v, properties, patterns)
+		case []interface{}:
+			for _, branch := range v {
+				if branchSchema, ok := branch.(map[string]interface{}); ok {
+					collectProperties(branchSchema, properties, patterns)
+				}
+			}
+		}
+	}
+}
+
+// allowNames adds an unconstrained schema for each name not already in a map of names to schemas
+func allowNames(value interface{}, names map[string]bool) map[string]interface{} {
+	schemas := asSchemaMap(value)
+	allowed := make(map[string]interface{}, len(names))
+	for name, subSchema := range schemas {
+		allowed[name] = subSchema
+	}
+	for name := range names {
+		if _, ok := allowed[name]; !ok {
+			allowed[name] = map[string]interface{}{}
+		}
+	}
+	return allowed
+}
+
+// asSchemaMap gets a map of names to schemas, which is empty if the value is not one
+func asSchemaMap(value interface{}) map[string]interface{} {
+	schemas, _ := value.(map[string]interface{})
+	return schemas
+}
+
+// disallowInSubSchema applies disallowAdditionalProperties to a schema, or a list of schemas
+func disallowInSubSchema(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return disallowAdditionalProperties(v)
+	case []interface{}:
+		copied := make([]interface{}, 0, len(v))
+		for _, subSchema := range v {
+			copied = append(copied, disallowInSubSchema(subSchema))
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -2,6 +2,8 @@ package did
 
 import (
 	"fmt"
+
+	"github.com/TBD54566975/ssi-sdk/did"
 	"github.com/pkg/errors"
 	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/internal/util"
@@ -21,6 +23,8 @@ type Service struct {
 	handlers map[Method]MethodHandler
 	storage  didstorage.Storage
 	config   config.DIDServiceConfig
+	// logs on behalf of the service, see WithLogger
+	log util.Logger
 }
 
 func (s Service) Type() framework.Type {
@@ -42,6 +46,14 @@ func (s Service) Config() config.DIDServiceConfig {
 	return s.config
 }
 
+func (s Service) Describe() map[string]interface{} {
+	capabilities := map[string]interface{}{"methods": s.GetSupportedMethods().Methods}
+	if _, ok := s.handlers[KeyMethod]; ok {
+		capabilities["keyTypes"] = did.GetSupportedDIDKeyTypes()
+	}
+	return capabilities
+}
+
 func (s Service) GetSupportedMethods() GetSupportedMethodsResponse {
 	var methods []Method
 	for method := range s.handlers {
@@ -54,7 +66,7 @@ func (s Service) CreateDIDByMethod(request CreateDIDRequest) (*CreateDIDResponse
 	handler, err := s.getHandler(request.Method)
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get handler for method<%s>", request.Method)
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 	return handler.CreateDID(request)
 }
@@ -63,7 +75,7 @@ func (s Service) GetDIDByMethod(request GetDIDRequest) (*GetDIDResponse, error)
 	handler, err := s.getHandler(request.Method)
 	if err != nil {
 		errMsg := fmt.Sprintf("could not get handler for method<%s>", request.Method)
-		return nil, util.LoggingErrorMsg(err, errMsg)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
 	}
 	return handler.GetDID(request)
 }
@@ -72,7 +84,7 @@ func (s Service) getHandler(method Method) (MethodHandler, error) {
 	handler, ok := s.handlers[method]
 	if !ok {
 		err := fmt.Errorf("could not get handler for DID method: %s", method)
-		return nil, util.LoggingError(err)
+		return nil, s.log.LoggingError(err)
 	}
 	return handler, nil
 }
@@ -91,6 +103,7 @@ func NewDIDService(config config.DIDServiceConfig, s storage.ServiceStorage) (*S
 	svc := Service{
 		storage:  didStorage,
 		handlers: make(map[Method]MethodHandler),
+		log:      util.NewLogger(),
 	}
 
 	// instantiate all handlers for DID methods
@@ -102,18 +115,25 @@ func NewDIDService(config config.DIDServiceConfig, s storage.ServiceStorage) (*S
 	return &svc, nil
 }
 
+// WithLogger gets a copy of the service which logs through the given logger, such as one carrying the ID of the
+// request the service is called for
+func (s Service) WithLogger(log util.Logger) *Service {
+	s.log = log
+	return &s
+}
+
 func (s *Service) instantiateHandlerForMethod(method Method) error {
 	switch method {
 	case KeyMethod:
 		handler, err := newKeyDIDHandler(s.storage)
 		if err != nil {
 			err := fmt.Errorf("could not instnatiate did:%s handler", KeyMethod)
-			return util.LoggingError(err)
+			return s.log.LoggingError(err)
 		}
 		s.handlers[method] = handler
 	default:
 		err := fmt.Errorf("unsupported DID method: %s", method)
-		return util.LoggingError(err)
+		return s.log.LoggingError(err)
 	}
 	return nil
 }
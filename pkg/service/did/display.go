@@ -0,0 +1,123 @@
+package did
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+	didstorage "github.com/tbd54566975/ssi-service/pkg/service/did/storage"
+)
+
+const (
+	// MaxInlineLogoBytes is the largest image which may be inlined as a logo; larger logos should be linked by URL
+	MaxInlineLogoBytes = 32 * 1024
+)
+
+var (
+	// image types wallets can be expected to render
+	supportedLogoMediaTypes = map[string]bool{
+		"image/png":     true,
+		"image/jpeg":    true,
+		"image/svg+xml": true,
+		"image/webp":    true,
+	}
+
+	hexColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// SetDIDDisplay attaches display metadata to a DID stored by the service, replacing any it already had
+func (s Service) SetDIDDisplay(request SetDIDDisplayRequest) (*GetDIDDisplayResponse, error) {
+
+	s.log.Debugf("setting display for DID: %s", util.SanitizeLog(request.ID))
+
+	if _, err := s.getHandler(request.Method); err != nil {
+		errMsg := fmt.Sprintf("could not get handler for method<%s>", request.Method)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	if err := validateDisplay(request.Display); err != nil {
+		return nil, s.log.LoggingErrorMsg(err, "invalid DID display")
+	}
+
+	gotDID, err := s.storage.GetDID(request.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get DID: %s", request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	display := didstorage.Display(request.Display)
+	gotDID.Display = &display
+	if err := s.storage.StoreDID(*gotDID); err != nil {
+		errMsg := fmt.Sprintf("could not store display for DID: %s", request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	return &GetDIDDisplayResponse{Display: request.Display}, nil
+}
+
+// GetDIDDisplay gets the display metadata attached to a DID, which is empty if none has been set
+func (s Service) GetDIDDisplay(request GetDIDDisplayRequest) (*GetDIDDisplayResponse, error) {
+
+	s.log.Debugf("getting display for DID: %s", util.SanitizeLog(request.ID))
+
+	if _, err := s.getHandler(request.Method); err != nil {
+		errMsg := fmt.Sprintf("could not get handler for method<%s>", request.Method)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	gotDID, err := s.storage.GetDID(request.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get DID: %s", request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+
+	var display Display
+	if gotDID.Display != nil {
+		display = Display(*gotDID.Display)
+	}
+	return &GetDIDDisplayResponse{Display: display}, nil
+}
+
+func validateDisplay(display Display) error {
+	if display.BackgroundColor != "" && !hexColorRegex.MatchString(display.BackgroundColor) {
+		return fmt.Errorf("background color<%s> must be of the form #RRGGBB", display.BackgroundColor)
+	}
+	if display.TextColor != "" && !hexColorRegex.MatchString(display.TextColor) {
+		return fmt.Errorf("text color<%s> must be of the form #RRGGBB", display.TextColor)
+	}
+	if display.Logo != "" {
+		return validateLogo(display.Logo)
+	}
+	return nil
+}
+
+// validateLogo checks a logo is either an http(s) URL, or a base64 data URI of a supported image type which is
+// no larger than MaxInlineLogoBytes
+func validateLogo(logo string) error {
+	if !strings.HasPrefix(logo, "data:") {
+		logoURL, err := url.Parse(logo)
+		if err != nil || (logoURL.Scheme != "https" && logoURL.Scheme != "http") || logoURL.Host == "" {
+			return fmt.Errorf("logo must be an http(s) URL or a data URI")
+		}
+		return nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(logo, "data:"), ",", 2)
+	if len(parts) != 2 || !strings.HasSuffix(parts[0], ";base64") {
+		return fmt.Errorf("inline logo must be a base64 encoded data URI")
+	}
+	mediaType := strings.TrimSuffix(parts[0], ";base64")
+	if !supportedLogoMediaTypes[mediaType] {
+		return fmt.Errorf("unsupported logo media type<%s>", mediaType)
+	}
+	image, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("could not decode inline logo: %w", err)
+	}
+	if len(image) > MaxInlineLogoBytes {
+		return fmt.Errorf("inline logo is %d bytes, larger than the maximum of %d", len(image), MaxInlineLogoBytes)
+	}
+	return nil
+}
@@ -31,3 +31,29 @@ type GetDIDRequest struct {
 type GetDIDResponse struct {
 	DID didsdk.DIDDocument `json:"did"`
 }
+
+// Display is the JSON-serializable display metadata attached to a DID
+type Display struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Either an http(s) URL of the logo, or a small image inline as a base64 data URI
+	Logo string `json:"logo,omitempty"`
+	// Colors in the hex form #RRGGBB
+	BackgroundColor string `json:"backgroundColor,omitempty"`
+	TextColor       string `json:"textColor,omitempty"`
+}
+
+type SetDIDDisplayRequest struct {
+	Method  Method  `json:"method" validate:"required"`
+	ID      string  `json:"id" validate:"required"`
+	Display Display `json:"display"`
+}
+
+type GetDIDDisplayRequest struct {
+	Method Method `json:"method" validate:"required"`
+	ID     string `json:"id" validate:"required"`
+}
+
+type GetDIDDisplayResponse struct {
+	Display Display `json:"display"`
+}
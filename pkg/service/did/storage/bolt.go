@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tbd54566975/ssi-service/internal/util"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
+	"sort"
 )
 
 const (
@@ -87,6 +88,9 @@ func (b BoltDIDStorage) GetDIDs(method string) ([]StoredDID, error) {
 			stored = append(stored, nextDID)
 		}
 	}
+	sort.Slice(stored, func(i, j int) bool {
+		return stored[i].DID.ID < stored[j].DID.ID
+	})
 	return stored, nil
 }
 
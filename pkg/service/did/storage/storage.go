@@ -13,11 +13,23 @@ type StoredDID struct {
 	DID did.DIDDocument `json:"did"`
 	// TODO(gabe) split out key storage into a key store service encrypted with a service encryption key
 	PrivateKeyBase58 string `json:"privateKeyBase58"`
+	// Display metadata wallets use to render the DID's controller as an issuer
+	Display *Display `json:"display,omitempty"`
+}
+
+type Display struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Either an http(s) URL of the logo, or a small image inline as a base64 data URI
+	Logo            string `json:"logo,omitempty"`
+	BackgroundColor string `json:"backgroundColor,omitempty"`
+	TextColor       string `json:"textColor,omitempty"`
 }
 
 type Storage interface {
 	StoreDID(did StoredDID) error
 	GetDID(id string) (*StoredDID, error)
+	// GetDIDs returns DIDs ordered by ID, since no creation time is stored for them
 	GetDIDs(method string) ([]StoredDID, error)
 	DeleteDID(id string) error
 }
@@ -0,0 +1,19 @@
+package framework
+
+import "fmt"
+
+// AlreadyExistsError is returned when a resource cannot be created with a caller-supplied ID because another
+// resource of the same kind already has it
+type AlreadyExistsError struct {
+	Resource string
+	ID       string
+}
+
+func (e AlreadyExistsError) Error() string {
+	return fmt.Sprintf("%s already exists with id: %s", e.Resource, e.ID)
+}
+
+// ExistingID is the ID of the resource which already exists
+func (e AlreadyExistsError) ExistingID() string {
+	return e.ID
+}
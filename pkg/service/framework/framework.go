@@ -32,3 +32,9 @@ type Service interface {
 	Type() Type
 	Status() Status
 }
+
+// Describer is implemented by services which can report the capabilities they have been configured with, such as
+// the DID methods or key types they support
+type Describer interface {
+	Describe() map[string]interface{}
+}
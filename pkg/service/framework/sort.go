@@ -0,0 +1,14 @@
+package framework
+
+// Sort orders accepted by list endpoints. Each resource has its own default order; the created orders sort by the
+// time each item was created, which for credentials is their issuance date and for schemas their authored date.
+const (
+	SortDefault     = ""
+	SortCreatedAsc  = "created asc"
+	SortCreatedDesc = "created desc"
+)
+
+// IsValidSort determines whether the sort is one list endpoints accept
+func IsValidSort(sort string) bool {
+	return sort == SortDefault || sort == SortCreatedAsc || sort == SortCreatedDesc
+}
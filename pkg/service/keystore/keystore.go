@@ -7,7 +7,6 @@ import (
 	"github.com/TBD54566975/ssi-sdk/crypto"
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/chacha20poly1305"
 
 	"github.com/tbd54566975/ssi-service/config"
@@ -20,6 +19,8 @@ import (
 type Service struct {
 	storage keystorestorage.Storage
 	config  config.KeyStoreServiceConfig
+	// logs on behalf of the service, see WithLogger
+	log util.Logger
 }
 
 func (s Service) Type() framework.Type {
@@ -40,6 +41,10 @@ func (s Service) Config() config.KeyStoreServiceConfig {
 	return s.config
 }
 
+func (s Service) Describe() map[string]interface{} {
+	return map[string]interface{}{"keyTypes": crypto.GetSupportedKeyTypes()}
+}
+
 func NewKeyStoreService(config config.KeyStoreServiceConfig, s storage.ServiceStorage) (*Service, error) {
 	// First, generate a service key
 	serviceKey, serviceKeySalt, err := GenerateServiceKey(config.ServiceKeyPassword)
@@ -57,18 +62,26 @@ func NewKeyStoreService(config config.KeyStoreServiceConfig, s storage.ServiceSt
 	return &Service{
 		storage: keyStoreStorage,
 		config:  config,
+		log:     util.NewLogger(),
 	}, nil
 }
 
+// WithLogger gets a copy of the service which logs through the given logger, such as one carrying the ID of the
+// request the service is called for
+func (s Service) WithLogger(log util.Logger) *Service {
+	s.log = log
+	return &s
+}
+
 func (s Service) StoreKey(request StoreKeyRequest) error {
 
-	logrus.Debugf("storing key: %+v", request)
+	s.log.Debugf("storing key<%s> of type: %s", request.ID, request.Type)
 
 	// check if the provided key type is supported. support entails being able to serialize/deserialize, in addition
 	// to facilitating signing/verification and encryption/decryption support.
 	if !crypto.IsSupportedKeyType(request.Type) {
 		errMsg := fmt.Sprintf("unsupported key type: %s", request.Type)
-		return util.LoggingNewError(errMsg)
+		return s.log.LoggingNewError(errMsg)
 	}
 
 	key := keystorestorage.StoredKey{
@@ -79,25 +92,28 @@ func (s Service) StoreKey(request StoreKeyRequest) error {
 		CreatedAt:  time.Now().Format(time.RFC3339),
 	}
 	if err := s.storage.StoreKey(key); err != nil {
+		if errors.As(err, &keystorestorage.KeyExistsError{}) {
+			return s.log.LoggingError(framework.AlreadyExistsError{Resource: "key", ID: request.ID})
+		}
 		err := errors.Wrapf(err, "could not store key: %s", request.ID)
-		return util.LoggingError(err)
+		return s.log.LoggingError(err)
 	}
 	return nil
 }
 
 func (s Service) GetKeyDetails(request GetKeyDetailsRequest) (*GetKeyDetailsResponse, error) {
 
-	logrus.Debugf("getting key: %+v", request)
+	s.log.Debugf("getting key: %+v", request)
 
 	id := request.ID
 	gotKeyDetails, err := s.storage.GetKeyDetails(id)
 	if err != nil {
 		err := errors.Wrapf(err, "could not get key details for key: %s", id)
-		return nil, util.LoggingError(err)
+		return nil, s.log.LoggingError(err)
 	}
 	if gotKeyDetails == nil {
 		err := errors.Wrapf(err, "key with id<%s> could not be found", id)
-		return nil, util.LoggingError(err)
+		return nil, s.log.LoggingError(err)
 	}
 	return &GetKeyDetailsResponse{
 		ID:         gotKeyDetails.ID,
@@ -107,6 +123,28 @@ func (s Service) GetKeyDetails(request GetKeyDetailsRequest) (*GetKeyDetailsResp
 	}, nil
 }
 
+func (s Service) GetKeyDetailsByController(request GetKeyDetailsByControllerRequest) (*GetKeyDetailsByControllerResponse, error) {
+
+	s.log.Debugf("getting keys for controller: %s", util.SanitizeLog(request.Controller))
+
+	gotKeyDetails, err := s.storage.GetKeyDetailsByController(request.Controller)
+	if err != nil {
+		err := errors.Wrapf(err, "could not get key details for controller: %s", request.Controller)
+		return nil, s.log.LoggingError(err)
+	}
+
+	keys := make([]GetKeyDetailsResponse, 0, len(gotKeyDetails))
+	for _, keyDetails := range gotKeyDetails {
+		keys = append(keys, GetKeyDetailsResponse{
+			ID:         keyDetails.ID,
+			Type:       keyDetails.KeyType,
+			Controller: keyDetails.Controller,
+			CreatedAt:  keyDetails.CreatedAt,
+		})
+	}
+	return &GetKeyDetailsByControllerResponse{Keys: keys}, nil
+}
+
 // GenerateServiceKey using argon2 for key derivation generate a service key and corresponding salt,
 // base58 encoding both values.
 func GenerateServiceKey(skPassword string) (key, salt string, err error) {
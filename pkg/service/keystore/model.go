@@ -21,3 +21,11 @@ type GetKeyDetailsResponse struct {
 	Controller string
 	CreatedAt  string
 }
+
+type GetKeyDetailsByControllerRequest struct {
+	Controller string
+}
+
+type GetKeyDetailsByControllerResponse struct {
+	Keys []GetKeyDetailsResponse
+}
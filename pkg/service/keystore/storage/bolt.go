@@ -5,6 +5,7 @@ import (
 
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/tbd54566975/ssi-service/internal/util"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
@@ -69,7 +70,16 @@ func (b BoltKeyStoreStorage) StoreKey(key StoredKey) error {
 		errMsg := fmt.Sprintf("could not store key: %s", id)
 		return util.LoggingErrorMsg(err, errMsg)
 	}
-	return b.db.Write(namespace, id, keyBytes)
+	// keys are never overwritten, which also keeps the service key from being replaced
+	written, err := b.db.WriteIfAbsent(namespace, id, keyBytes)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not store key: %s", id)
+		return util.LoggingErrorMsg(err, errMsg)
+	}
+	if !written {
+		return util.LoggingError(KeyExistsError{ID: id})
+	}
+	return nil
 }
 
 func (b BoltKeyStoreStorage) GetKeyDetails(id string) (*KeyDetails, error) {
@@ -94,3 +104,34 @@ func (b BoltKeyStoreStorage) GetKeyDetails(id string) (*KeyDetails, error) {
 		CreatedAt:  stored.CreatedAt,
 	}, nil
 }
+
+// GetKeyDetailsByController gets the details of all keys with the given controller
+func (b BoltKeyStoreStorage) GetKeyDetailsByController(controller string) ([]KeyDetails, error) {
+	storedKeys, err := b.db.ReadAll(namespace)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get keys for controller: %s", controller)
+		return nil, util.LoggingErrorMsg(err, errMsg)
+	}
+
+	var keyDetails []KeyDetails
+	for id, storedKeyBytes := range storedKeys {
+		if id == skKey {
+			continue
+		}
+		var stored StoredKey
+		if err := json.Unmarshal(storedKeyBytes, &stored); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal stored key: %s", id)
+			continue
+		}
+		if stored.Controller != controller {
+			continue
+		}
+		keyDetails = append(keyDetails, KeyDetails{
+			ID:         stored.ID,
+			Controller: stored.Controller,
+			KeyType:    stored.KeyType,
+			CreatedAt:  stored.CreatedAt,
+		})
+	}
+	return keyDetails, nil
+}
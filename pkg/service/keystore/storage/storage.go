@@ -26,6 +26,15 @@ type KeyDetails struct {
 	CreatedAt  string         `json:"createdAt"`
 }
 
+// KeyExistsError is returned when storing a key with an ID which is already in use
+type KeyExistsError struct {
+	ID string
+}
+
+func (e KeyExistsError) Error() string {
+	return fmt.Sprintf("key already exists with id: %s", e.ID)
+}
+
 type ServiceKey struct {
 	Key  string
 	Salt string
@@ -34,6 +43,7 @@ type ServiceKey struct {
 type Storage interface {
 	StoreKey(key StoredKey) error
 	GetKeyDetails(id string) (*KeyDetails, error)
+	GetKeyDetailsByController(controller string) ([]KeyDetails, error)
 }
 
 func NewKeyStoreStorage(s storage.ServiceStorage, serviceKey, serviceKeySalt string) (Storage, error) {
@@ -0,0 +1,208 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+)
+
+// GetSchemaForm describes the fields of a form for the data of a credential against a schema. Nested objects are
+// flattened into their fields, each located by a JSON Pointer, with $refs to the schema's own definitions and allOf
+// compositions resolved. Properties are listed in name order, since JSON Schema does not order them.
+func (s Service) GetSchemaForm(request GetSchemaFormRequest) (*GetSchemaFormResponse, error) {
+
+	s.log.Debugf("getting form for schema: %s", util.SanitizeLog(request.ID))
+
+	gotSchema, err := s.GetSchemaByID(GetSchemaByIDRequest{ID: request.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	root := map[string]interface{}(gotSchema.Schema.Schema)
+	builder := formBuilder{root: root}
+	if err := builder.addFields(root, "", "", true, nil); err != nil {
+		errMsg := fmt.Sprintf("could not build form for schema: %s", request.ID)
+		return nil, s.log.LoggingErrorMsg(err, errMsg)
+	}
+	return &GetSchemaFormResponse{Fields: builder.fields}, nil
+}
+
+type formBuilder struct {
+	root   map[string]interface{}
+	fields []FormField
+}
+
+// addFields adds a field for the schema of the named property at the given pointer or, for an object, a field for
+// each of its properties. refs holds the $refs being resolved along the current path, to detect cycles.
+func (f *formBuilder) addFields(schema map[string]interface{}, name, pointer string, required bool, refs []string) error {
+	resolved, refs, err := f.resolve(schema, refs)
+	if err != nil {
+		return err
+	}
+
+	properties, _ := resolved["properties"].(map[string]interface{})
+	if schemaType(resolved) != "object" || len(properties) == 0 {
+		if pointer == "" {
+			return fmt.Errorf("schema does not describe an object with properties")
+		}
+		enum, _ := resolved["enum"].([]interface{})
+		description, _ := resolved["description"].(string)
+		format, _ := resolved["format"].(string)
+		f.fields = append(f.fields, FormField{
+			Name:        name,
+			Pointer:     pointer,
+			Type:        schemaType(resolved),
+			Required:    required,
+			Enum:        enum,
+			Description: description,
+			Format:      format,
+		})
+		return nil
+	}
+
+	requiredProperties := make(map[string]bool)
+	if requiredList, ok := resolved["required"].([]interface{}); ok {
+		for _, r := range requiredList {
+			if name, ok := r.(string); ok {
+				requiredProperties[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, propertyName := range names {
+		property, ok := properties[propertyName].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("property<%s> at %s is not a schema", propertyName, pointer)
+		}
+		// a property is only required when its parent object is
+		propertyPointer := pointer + "/" + escapePointerToken(propertyName)
+		if err := f.addFields(property, propertyName, propertyPointer, required && requiredProperties[propertyName], refs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve follows a schema's $ref and merges its allOf subschemas, returning the schema they describe
+func (f *formBuilder) resolve(schema map[string]interface{}, refs []string) (map[string]interface{}, []string, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		for _, seen := range refs {
+			if seen == ref {
+				return nil, nil, fmt.Errorf("circular $ref: %s", ref)
+			}
+		}
+		target, err := f.lookupRef(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f.resolve(target, append(refs, ref))
+	}
+
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok {
+		return schema, refs, nil
+	}
+
+	merged := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		if k != "allOf" {
+			merged[k] = v
+		}
+	}
+	for _, sub := range allOf {
+		subSchema, ok := sub.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("allOf contains a value which is not a schema")
+		}
+		resolvedSub, _, err := f.resolve(subSchema, refs)
+		if err != nil {
+			return nil, nil, err
+		}
+		mergeSchemas(merged, resolvedSub)
+	}
+	return merged, refs, nil
+}
+
+// lookupRef finds the schema a $ref points to. Only references within the schema itself are supported.
+func (f *formBuilder) lookupRef(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref<%s>, only references within the schema are supported", ref)
+	}
+	var current interface{} = f.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("could not resolve $ref: %s", ref)
+		}
+		current = object[unescapePointerToken(token)]
+	}
+	target, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("could not resolve $ref: %s", ref)
+	}
+	return target, nil
+}
+
+// mergeSchemas merges an allOf subschema into a schema: properties and required properties are combined, and any
+// other keyword is taken from the subschema if the schema does not set it
+func mergeSchemas(schema, sub map[string]interface{}) {
+	for k, v := range sub {
+		switch k {
+		case "properties":
+			properties, _ := schema["properties"].(map[string]interface{})
+			combined := make(map[string]interface{}, len(properties))
+			for name, property := range properties {
+				combined[name] = property
+			}
+			if subProperties, ok := v.(map[string]interface{}); ok {
+				for name, property := range subProperties {
+					combined[name] = property
+				}
+			}
+			schema["properties"] = combined
+		case "required":
+			required, _ := schema["required"].([]interface{})
+			if subRequired, ok := v.([]interface{}); ok {
+				schema["required"] = append(append([]interface{}{}, required...), subRequired...)
+			}
+		default:
+			if _, ok := schema[k]; !ok {
+				schema[k] = v
+			}
+		}
+	}
+}
+
+// schemaType gets a schema's type. Where a schema allows several types, the first which is not null is used.
+// Schemas with properties but no type are objects.
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, option := range t {
+			if s, ok := option.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
@@ -6,19 +6,26 @@ const (
 	Version1 string = "1.0.0"
 )
 
+type GetSchemasRequest struct {
+	// Optionally, one of the framework sort orders. By default, schemas are listed by name.
+	Sort string
+}
+
 type GetSchemasResponse struct {
 	Schemas []schema.VCJSONSchema `json:"schemas,omitempty"`
 }
 
 type CreateSchemaRequest struct {
-	Author string            `json:"author" validate:"required"`
-	Name   string            `json:"name" validate:"required"`
-	Schema schema.JSONSchema `json:"schema" validate:"required"`
+	Author           string            `json:"author" validate:"required"`
+	Name             string            `json:"name" validate:"required"`
+	Schema           schema.JSONSchema `json:"schema" validate:"required"`
+	StrictValidation *bool             `json:"strictValidation,omitempty"`
 }
 
 type CreateSchemaResponse struct {
-	ID     string              `json:"id"`
-	Schema schema.VCJSONSchema `json:"schema"`
+	ID               string              `json:"id"`
+	Schema           schema.VCJSONSchema `json:"schema"`
+	StrictValidation *bool               `json:"strictValidation,omitempty"`
 }
 
 type GetSchemaByIDRequest struct {
@@ -28,3 +35,25 @@ type GetSchemaByIDRequest struct {
 type GetSchemaByIDResponse struct {
 	Schema schema.VCJSONSchema `json:"schema"`
 }
+
+// FormField describes a single input of a form for the data of a credential against a schema
+type FormField struct {
+	Name string `json:"name"`
+	// JSON Pointer (RFC 6901) to the field's location in the credential's data
+	Pointer  string `json:"pointer"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	// The values the field is restricted to, if any
+	Enum        []interface{} `json:"enum,omitempty"`
+	Description string        `json:"description,omitempty"`
+	// A hint of the kind of value expected, such as date or email
+	Format string `json:"format,omitempty"`
+}
+
+type GetSchemaFormRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type GetSchemaFormResponse struct {
+	Fields []FormField `json:"fields"`
+}
@@ -12,12 +12,15 @@ import (
 	"github.com/tbd54566975/ssi-service/pkg/service/framework"
 	schemastorage "github.com/tbd54566975/ssi-service/pkg/service/schema/storage"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
+	"sort"
 	"time"
 )
 
 type Service struct {
 	storage schemastorage.Storage
 	config  config.SchemaServiceConfig
+	// logs on behalf of the service, see WithLogger
+	log util.Logger
 }
 
 func (s Service) Type() framework.Type {
@@ -47,9 +50,17 @@ func NewSchemaService(config config.SchemaServiceConfig, s storage.ServiceStorag
 	return &Service{
 		storage: schemaStorage,
 		config:  config,
+		log:     util.NewLogger(),
 	}, nil
 }
 
+// WithLogger gets a copy of the service which logs through the given logger, such as one carrying the ID of the
+// request the service is called for
+func (s Service) WithLogger(log util.Logger) *Service {
+	s.log = log
+	return &s
+}
+
 // CreateSchema houses the main service logic for schema creation. It validates the input, and
 // produces a schema value that conforms with the VC JSON JSONSchema specification.
 // TODO(gabe) support proof generation on schemas, versioning, and more
@@ -59,7 +70,7 @@ func (s Service) CreateSchema(request CreateSchemaRequest) (*CreateSchemaRespons
 		return nil, errors.Wrap(err, "could not marshal schema in request")
 	}
 	if err := schemalib.IsValidJSONSchema(string(schemaBytes)); err != nil {
-		return nil, util.LoggingErrorMsg(err, "provided value is not a valid JSON schema")
+		return nil, s.log.LoggingErrorMsg(err, "provided value is not a valid JSON schema")
 	}
 
 	schemaID := uuid.NewString()
@@ -73,18 +84,30 @@ func (s Service) CreateSchema(request CreateSchemaRequest) (*CreateSchemaRespons
 		Schema:   request.Schema,
 	}
 
-	storedSchema := schemastorage.StoredSchema{Schema: schemaValue}
+	storedSchema := schemastorage.StoredSchema{Schema: schemaValue, StrictValidation: request.StrictValidation}
 	if err := s.storage.StoreSchema(storedSchema); err != nil {
-		return nil, util.LoggingErrorMsg(err, "could not store schema")
+		return nil, s.log.LoggingErrorMsg(err, "could not store schema")
 	}
 
-	return &CreateSchemaResponse{ID: schemaID, Schema: schemaValue}, nil
+	return &CreateSchemaResponse{ID: schemaID, Schema: schemaValue, StrictValidation: request.StrictValidation}, nil
 }
 
-func (s Service) GetSchemas() (*GetSchemasResponse, error) {
+func (s Service) GetSchemas(request GetSchemasRequest) (*GetSchemasResponse, error) {
 	storedSchemas, err := s.storage.GetSchemas()
 	if err != nil {
-		return nil, util.LoggingErrorMsg(err, "error getting schemas")
+		return nil, s.log.LoggingErrorMsg(err, "error getting schemas")
+	}
+
+	// storage lists schemas by name
+	if request.Sort == framework.SortCreatedAsc || request.Sort == framework.SortCreatedDesc {
+		descending := request.Sort == framework.SortCreatedDesc
+		sort.SliceStable(storedSchemas, func(i, j int) bool {
+			a, b := storedSchemas[i].Schema, storedSchemas[j].Schema
+			if a.Authored == b.Authored {
+				return a.ID < b.ID
+			}
+			return (a.Authored < b.Authored) != descending
+		})
 	}
 	var schemas []schema.VCJSONSchema
 	for _, stored := range storedSchemas {
@@ -99,11 +122,11 @@ func (s Service) GetSchemaByID(request GetSchemaByIDRequest) (*GetSchemaByIDResp
 	gotSchema, err := s.storage.GetSchema(request.ID)
 	if err != nil {
 		err := errors.Wrapf(err, "error getting schema: %s", request.ID)
-		return nil, util.LoggingError(err)
+		return nil, s.log.LoggingError(err)
 	}
 	if gotSchema == nil {
 		err := fmt.Errorf("schema with id<%s> could not be found", request.ID)
-		return nil, util.LoggingError(err)
+		return nil, s.log.LoggingError(err)
 	}
 	return &GetSchemaByIDResponse{Schema: gotSchema.Schema}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
+	"sort"
 )
 
 const (
@@ -47,7 +48,7 @@ func (b BoltSchemaStorage) GetSchema(id string) (*StoredSchema, error) {
 		return nil, errors.Wrapf(err, errMsg)
 	}
 	if len(schemaBytes) == 0 {
-		err := fmt.Errorf("schema not found with id: %s", id)
+		err := SchemaNotFoundError{ID: id}
 		logrus.WithError(err).Error("could not get schema from storage")
 		return nil, err
 	}
@@ -79,6 +80,12 @@ func (b BoltSchemaStorage) GetSchemas() ([]StoredSchema, error) {
 			stored = append(stored, nextSchema)
 		}
 	}
+	sort.Slice(stored, func(i, j int) bool {
+		if stored[i].Schema.Name != stored[j].Schema.Name {
+			return stored[i].Schema.Name < stored[j].Schema.Name
+		}
+		return stored[i].Schema.ID < stored[j].Schema.ID
+	})
 	return stored, nil
 }
 
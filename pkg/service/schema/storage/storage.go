@@ -11,12 +11,25 @@ import (
 
 type StoredSchema struct {
 	Schema schema.VCJSONSchema `json:"schema"`
+	// Whether credential data must not contain properties the schema does not define. When unset, the credential
+	// service's default applies.
+	StrictValidation *bool `json:"strictValidation,omitempty"`
+}
+
+// SchemaNotFoundError is returned when no schema is stored with an ID
+type SchemaNotFoundError struct {
+	ID string
+}
+
+func (e SchemaNotFoundError) Error() string {
+	return fmt.Sprintf("schema not found with id: %s", e.ID)
 }
 
 type Storage interface {
 	StoreSchema(schema StoredSchema) error
 	GetSchema(id string) (*StoredSchema, error)
 	// TODO(gabe) consider get schemas by DID, or more advanced querying
+	// GetSchemas returns schemas in their default order: by name, then by ID
 	GetSchemas() ([]StoredSchema, error)
 	DeleteSchema(id string) error
 }
@@ -1,13 +1,17 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/internal/util"
 	"github.com/tbd54566975/ssi-service/pkg/service/credential"
 	"github.com/tbd54566975/ssi-service/pkg/service/did"
 	"github.com/tbd54566975/ssi-service/pkg/service/framework"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
 	"github.com/tbd54566975/ssi-service/pkg/service/schema"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
@@ -51,7 +55,8 @@ func (ssi *SSIService) GetServices() []framework.Service {
 	return ssi.services
 }
 
-// instantiateServices begins all instantiates and their dependencies
+// instantiateServices begins all instantiates and their dependencies. Without storage no service can run, but a
+// service which otherwise fails to initialize is replaced by an UnavailableService, so the rest can still be served.
 func instantiateServices(config config.ServicesConfig) ([]framework.Service, error) {
 	storageProvider, err := storage.NewStorage(storage.Storage(config.StorageProvider))
 	if err != nil {
@@ -59,23 +64,32 @@ func instantiateServices(config config.ServicesConfig) ([]framework.Service, err
 		return nil, util.LoggingErrorMsg(err, errMsg)
 	}
 
+	var services []framework.Service
 	didService, err := did.NewDIDService(config.DIDConfig, storageProvider)
-	if err != nil {
-		errMsg := "could not instantiate the DID service"
-		return nil, util.LoggingErrorMsg(err, errMsg)
-	}
+	services = append(services, serviceOrUnavailable(framework.DID, didService, err))
 
 	schemaService, err := schema.NewSchemaService(config.SchemaConfig, storageProvider)
-	if err != nil {
-		errMsg := "could not instantiate the schema service"
-		return nil, util.LoggingErrorMsg(err, errMsg)
-	}
+	services = append(services, serviceOrUnavailable(framework.Schema, schemaService, err))
 
-	credentialService, err := credential.NewCredentialService(config.CredentialConfig, storageProvider)
-	if err != nil {
-		errMsg := "could not instantiate the credential service"
-		return nil, util.LoggingErrorMsg(err, errMsg)
+	keyStoreService, err := keystore.NewKeyStoreService(config.KeyStoreConfig, storageProvider)
+	services = append(services, serviceOrUnavailable(framework.KeyStore, keyStoreService, err))
+
+	// the credential service cannot run without a key store
+	credentialErr := errors.New("key store is unavailable")
+	var credentialService *credential.Service
+	if keyStoreService != nil {
+		credentialService, credentialErr = credential.NewCredentialService(config.CredentialConfig, storageProvider, keyStoreService)
 	}
+	services = append(services, serviceOrUnavailable(framework.Credential, credentialService, credentialErr))
 
-	return []framework.Service{didService, schemaService, credentialService}, nil
+	return services, nil
+}
+
+// serviceOrUnavailable gives the service if it was instantiated, or else an UnavailableService reporting the error
+func serviceOrUnavailable(serviceType framework.Type, service framework.Service, err error) framework.Service {
+	if err == nil {
+		return service
+	}
+	logrus.WithError(err).Errorf("could not instantiate the %s service, it will be unavailable", serviceType)
+	return UnavailableService{ServiceType: serviceType, Err: err}
 }
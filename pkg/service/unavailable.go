@@ -0,0 +1,30 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/tbd54566975/ssi-service/pkg/service/framework"
+)
+
+// UnavailableService stands in for a service which failed to initialize, so that the other services can still be
+// served while it reports why it is unavailable
+type UnavailableService struct {
+	ServiceType framework.Type
+	Err         error
+}
+
+func (u UnavailableService) Type() framework.Type {
+	return u.ServiceType
+}
+
+func (u UnavailableService) Status() framework.Status {
+	return framework.Status{
+		Status:  framework.StatusNotReady,
+		Message: u.Reason(),
+	}
+}
+
+// Reason describes why the service is unavailable
+func (u UnavailableService) Reason() string {
+	return fmt.Sprintf("%s failed to initialize: %s", u.ServiceType, u.Err.Error())
+}
@@ -53,6 +53,47 @@ func (b *BoltDB) Write(namespace string, key string, value []byte) error {
 	})
 }
 
+// WriteIfAbsent writes the value only if nothing is stored at the key, reporting whether it was written. The check
+// and write happen in a single transaction, so concurrent writers cannot both succeed.
+func (b *BoltDB) WriteIfAbsent(namespace string, key string, value []byte) (bool, error) {
+	written := false
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(namespace))
+		if err != nil {
+			return err
+		}
+		if bucket.Get([]byte(key)) != nil {
+			return nil
+		}
+		if err = bucket.Put([]byte(key), value); err != nil {
+			return err
+		}
+		written = true
+		return nil
+	})
+	return written, err
+}
+
+// WriteMany writes each key and value pair to the namespace in a single transaction, so either all of the values
+// are written or, on any failure, none are
+func (b *BoltDB) WriteMany(namespace string, keys []string, values [][]byte) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("mismatched number of keys<%d> and values<%d>", len(keys), len(values))
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(namespace))
+		if err != nil {
+			return err
+		}
+		for i := range keys {
+			if err = bucket.Put([]byte(keys[i]), values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (b *BoltDB) Read(namespace, key string) ([]byte, error) {
 	var result []byte
 	err := b.db.View(func(tx *bolt.Tx) error {
@@ -87,6 +128,31 @@ func (b *BoltDB) ReadPrefix(namespace, prefix string) (map[string][]byte, error)
 	return result, err
 }
 
+// ReadPrefixes does a prefix query for each of the given prefixes within a namespace, in a single transaction.
+// The result maps each prefix to its matching values; prefixes with no matches are omitted.
+func (b *BoltDB) ReadPrefixes(namespace string, prefixes []string) (map[string]map[string][]byte, error) {
+	result := make(map[string]map[string][]byte)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			logrus.Infof("namespace<%s> does not exist", namespace)
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for _, p := range prefixes {
+			prefix := []byte(p)
+			for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+				if _, ok := result[p]; !ok {
+					result[p] = make(map[string][]byte)
+				}
+				result[p][string(k)] = v
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
 func (b *BoltDB) ReadAll(namespace string) (map[string][]byte, error) {
 	result := make(map[string][]byte)
 	err := b.db.View(func(tx *bolt.Tx) error {
@@ -105,14 +171,15 @@ func (b *BoltDB) ReadAll(namespace string) (map[string][]byte, error) {
 	return result, err
 }
 
+// ReadAllKeys reads every key in a namespace. Like ReadAll, a namespace which does not exist yet, such as before
+// anything has been written to it, has no keys.
 func (b *BoltDB) ReadAllKeys(namespace string) ([]string, error) {
 	var result []string
 	err := b.db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(namespace))
 		if bucket == nil {
-			errMsg := fmt.Sprintf("namespace<%s> does not exist", namespace)
-			logrus.Error(errMsg)
-			return errors.New(errMsg)
+			logrus.Infof("namespace<%s> does not exist", namespace)
+			return nil
 		}
 		cursor := bucket.Cursor()
 		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
@@ -123,6 +190,26 @@ func (b *BoltDB) ReadAllKeys(namespace string) ([]string, error) {
 	return result, err
 }
 
+// ReadKeysBefore reads the keys in a namespace which sort before the given key, in order, without reading any
+// others. Like ReadAllKeys, a namespace which does not exist yet has no keys.
+func (b *BoltDB) ReadKeysBefore(namespace, end string) ([]string, error) {
+	var result []string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			logrus.Infof("namespace<%s> does not exist", namespace)
+			return nil
+		}
+		cursor := bucket.Cursor()
+		end := []byte(end)
+		for k, _ := cursor.First(); k != nil && bytes.Compare(k, end) < 0; k, _ = cursor.Next() {
+			result = append(result, string(k))
+		}
+		return nil
+	})
+	return result, err
+}
+
 func (b *BoltDB) Delete(namespace, key string) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(namespace))
@@ -133,6 +220,23 @@ func (b *BoltDB) Delete(namespace, key string) error {
 	})
 }
 
+// DeleteMany deletes each of the keys from the namespace in a single transaction, so either all of the keys are
+// deleted or, on any failure, none are
+func (b *BoltDB) DeleteMany(namespace string, keys []string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return fmt.Errorf("namespace<%s> does not exist", namespace)
+		}
+		for _, key := range keys {
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (b *BoltDB) DeleteNamespace(namespace string) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
 		if err := tx.DeleteBucket([]byte(namespace)); err != nil {
@@ -67,6 +67,34 @@ func TestBoltDB(t *testing.T) {
 	_, gotMcLaren := gotAll[team2]
 	assert.True(t, gotMcLaren)
 
+	// get values by multiple prefixes
+	gotPrefixes, err := db.ReadPrefixes(namespace, []string{"Red", "Mc", "Ferrari"})
+	assert.NoError(t, err)
+	assert.Len(t, gotPrefixes, 2)
+	assert.Contains(t, gotPrefixes["Red"], team1)
+	assert.Contains(t, gotPrefixes["Mc"], team2)
+
+	// get values by prefixes from a namespace that doesn't exist
+	gotPrefixes, err = db.ReadPrefixes("bad", []string{"Red"})
+	assert.NoError(t, err)
+	assert.Empty(t, gotPrefixes)
+
+	// write many values in a single transaction
+	err = db.WriteMany(namespace, []string{"Mercedes", "Alpine"}, [][]byte{[]byte("Hamilton"), []byte("Alonso")})
+	assert.NoError(t, err)
+
+	gotMercedes, err := db.Read(namespace, "Mercedes")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("Hamilton"), gotMercedes)
+
+	// a failed write leaves nothing behind
+	err = db.WriteMany(namespace, []string{"Williams", ""}, [][]byte{[]byte("Albon"), []byte("Sargeant")})
+	assert.Error(t, err)
+
+	gotWilliams, err := db.Read(namespace, "Williams")
+	assert.NoError(t, err)
+	assert.Empty(t, gotWilliams)
+
 	// delete value in the namespace
 	err = db.Delete(namespace, team2)
 	assert.NoError(t, err)
@@ -75,6 +103,22 @@ func TestBoltDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, gotPlayers2)
 
+	// delete many values in a single transaction
+	err = db.DeleteMany(namespace, []string{"Mercedes", "Alpine"})
+	assert.NoError(t, err)
+
+	gotMercedes, err = db.Read(namespace, "Mercedes")
+	assert.NoError(t, err)
+	assert.Empty(t, gotMercedes)
+
+	gotAlpine, err := db.Read(namespace, "Alpine")
+	assert.NoError(t, err)
+	assert.Empty(t, gotAlpine)
+
+	err = db.DeleteMany("bad", []string{"Mercedes"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace<bad> does not exist")
+
 	// delete value in a namespace that doesn't exist
 	err = db.Delete("bad", team2)
 	assert.Error(t, err)
@@ -139,4 +183,18 @@ func TestBoltDBPrefixAndKeys(t *testing.T) {
 	assert.Len(t, allKeys, 4)
 	assert.Contains(t, allKeys, "bitcoin-mainnet")
 	assert.Contains(t, allKeys, "tezos-mainnet")
+
+	// a namespace which does not exist has no keys
+	allKeys, err = db.ReadAllKeys("bad")
+	assert.NoError(t, err)
+	assert.Empty(t, allKeys)
+
+	// read the keys before a key, in order
+	beforeKeys, err := db.ReadKeysBefore(namespace, "tezos-mainnet")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bitcoin-mainnet", "bitcoin-testnet"}, beforeKeys)
+
+	beforeKeys, err = db.ReadKeysBefore("bad", "tezos-mainnet")
+	assert.NoError(t, err)
+	assert.Empty(t, beforeKeys)
 }
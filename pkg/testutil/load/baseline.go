@@ -0,0 +1,85 @@
+package load
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+// Baseline is the recorded performance of a workload which later runs are held to
+type Baseline struct {
+	P95         time.Duration `json:"p95"`
+	AllocsPerOp uint64        `json:"allocsPerOp"`
+}
+
+// Baselines are recorded per workload, by name
+type Baselines map[string]Baseline
+
+// LoadBaselines reads baselines from a JSON file. A missing file gives no baselines, so a first run can record them.
+func LoadBaselines(path string) (Baselines, error) {
+	baselinesBytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Baselines{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read baselines: %s", path)
+	}
+	var baselines Baselines
+	if err := json.Unmarshal(baselinesBytes, &baselines); err != nil {
+		return nil, errors.Wrapf(err, "could not parse baselines: %s", path)
+	}
+	return baselines, nil
+}
+
+// Record sets each result as the baseline for its workload
+func (b Baselines) Record(results ...Result) {
+	for _, result := range results {
+		b[result.Name] = Baseline{P95: result.P95, AllocsPerOp: result.AllocsPerOp}
+	}
+}
+
+// Save writes the baselines to a JSON file
+func (b Baselines) Save(path string) error {
+	return writeJSON(path, b)
+}
+
+// Check compares a result to its workload's baseline, failing if the p95 latency or allocations per request exceed
+// the baseline by more than the threshold, a fraction such as 0.2 for 20%. Workloads without a baseline pass.
+func (b Baselines) Check(result Result, threshold float64) error {
+	baseline, ok := b[result.Name]
+	if !ok {
+		return nil
+	}
+
+	var exceeded []string
+	if maxP95 := time.Duration(float64(baseline.P95) * (1 + threshold)); baseline.P95 > 0 && result.P95 > maxP95 {
+		exceeded = append(exceeded, fmt.Sprintf("p95 %s exceeds %s (baseline %s)", result.P95, maxP95, baseline.P95))
+	}
+	if maxAllocs := uint64(float64(baseline.AllocsPerOp) * (1 + threshold)); baseline.AllocsPerOp > 0 && result.AllocsPerOp > maxAllocs {
+		exceeded = append(exceeded, fmt.Sprintf("%d allocs/op exceeds %d (baseline %d)", result.AllocsPerOp, maxAllocs, baseline.AllocsPerOp))
+	}
+	if len(exceeded) > 0 {
+		return fmt.Errorf("workload<%s> regressed: %s", result.Name, strings.Join(exceeded, "; "))
+	}
+	return nil
+}
+
+// WriteResults writes results to a JSON file, for CI to trend over time
+func WriteResults(path string, results []Result) error {
+	return writeJSON(path, results)
+}
+
+func writeJSON(path string, v interface{}) error {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "could not marshal: %s", path)
+	}
+	if err := os.WriteFile(path, append(jsonBytes, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "could not write: %s", path)
+	}
+	return nil
+}
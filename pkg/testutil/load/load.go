@@ -0,0 +1,118 @@
+// Package load drives concurrent workloads against the service and checks their latency and allocations against
+// recorded baselines, so that performance regressions fail a test run rather than going unnoticed.
+package load
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Workload is a single operation run repeatedly by a number of concurrent workers
+type Workload struct {
+	Name string
+	// How many workers run the operation at once
+	Concurrency int
+	// How many times the operation is run in total, across all workers
+	Requests int
+	// The operation, given the number of the request it is running
+	Op func(i int) error
+}
+
+// Result summarizes a run of a workload. Durations are in nanoseconds when marshaled.
+type Result struct {
+	Name        string        `json:"name"`
+	Concurrency int           `json:"concurrency"`
+	Requests    int           `json:"requests"`
+	Errors      int           `json:"errors"`
+	P50         time.Duration `json:"p50"`
+	P95         time.Duration `json:"p95"`
+	Max         time.Duration `json:"max"`
+	// Allocations per request, across the whole process, so including both client and server when run in process
+	AllocsPerOp uint64 `json:"allocsPerOp"`
+	BytesPerOp  uint64 `json:"bytesPerOp"`
+	// Requests completed per second
+	Throughput float64 `json:"throughput"`
+	// The first error the operation returned, if any
+	FirstError string `json:"firstError,omitempty"`
+}
+
+// Run runs a workload to completion, timing each request
+func Run(workload Workload) (*Result, error) {
+	if workload.Concurrency < 1 || workload.Requests < 1 {
+		return nil, fmt.Errorf("workload<%s> needs at least one worker and one request", workload.Name)
+	}
+	if workload.Op == nil {
+		return nil, fmt.Errorf("workload<%s> has no operation", workload.Name)
+	}
+
+	latencies := make([]time.Duration, workload.Requests)
+	errs := make([]error, workload.Requests)
+	requests := make(chan int)
+	var wg sync.WaitGroup
+
+	// settle the heap so allocations left over from setup are not counted
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	for w := 0; w < workload.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range requests {
+				opStart := time.Now()
+				errs[i] = workload.Op(i)
+				latencies[i] = time.Since(opStart)
+			}
+		}()
+	}
+	for i := 0; i < workload.Requests; i++ {
+		requests <- i
+	}
+	close(requests)
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	result := Result{
+		Name:        workload.Name,
+		Concurrency: workload.Concurrency,
+		Requests:    workload.Requests,
+		AllocsPerOp: (after.Mallocs - before.Mallocs) / uint64(workload.Requests),
+		BytesPerOp:  (after.TotalAlloc - before.TotalAlloc) / uint64(workload.Requests),
+		Throughput:  float64(workload.Requests) / elapsed.Seconds(),
+	}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if result.Errors == 0 {
+			result.FirstError = err.Error()
+		}
+		result.Errors++
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.P50 = Percentile(latencies, 50)
+	result.P95 = Percentile(latencies, 95)
+	result.Max = latencies[len(latencies)-1]
+	return &result, nil
+}
+
+// Percentile gives the latency at or below which the given percent of the sorted latencies fall, using the
+// nearest-rank method
+func Percentile(sorted []time.Duration, percent int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (percent*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,77 @@
+package load
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("Bad Workload", func(tt *testing.T) {
+		_, err := Run(Workload{Name: "empty", Concurrency: 1, Requests: 1})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "workload<empty> has no operation")
+
+		_, err = Run(Workload{Name: "idle", Requests: 1, Op: func(int) error { return nil }})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "workload<idle> needs at least one worker and one request")
+	})
+
+	t.Run("Counts Requests And Errors", func(tt *testing.T) {
+		result, err := Run(Workload{
+			Name:        "odd failures",
+			Concurrency: 4,
+			Requests:    10,
+			Op: func(i int) error {
+				if i%2 == 1 {
+					return errors.New("odd request")
+				}
+				return nil
+			},
+		})
+		require.NoError(tt, err)
+		assert.Equal(tt, 10, result.Requests)
+		assert.Equal(tt, 5, result.Errors)
+		assert.Equal(tt, "odd request", result.FirstError)
+		assert.LessOrEqual(tt, result.P50, result.P95)
+		assert.LessOrEqual(tt, result.P95, result.Max)
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 20; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 10*time.Millisecond, Percentile(sorted, 50))
+	assert.Equal(t, 19*time.Millisecond, Percentile(sorted, 95))
+	assert.Equal(t, 20*time.Millisecond, Percentile(sorted, 100))
+	assert.Equal(t, time.Millisecond, Percentile(sorted, 0))
+	assert.Zero(t, Percentile(nil, 95))
+}
+
+func TestBaselines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baselines.json")
+
+	// no file yet, so nothing to check against
+	baselines, err := LoadBaselines(path)
+	require.NoError(t, err)
+	assert.NoError(t, baselines.Check(Result{Name: "issue", P95: time.Hour}, 0.2))
+
+	baselines.Record(Result{Name: "issue", P95: 10 * time.Millisecond, AllocsPerOp: 100})
+	require.NoError(t, baselines.Save(path))
+	baselines, err = LoadBaselines(path)
+	require.NoError(t, err)
+
+	// within the threshold
+	assert.NoError(t, baselines.Check(Result{Name: "issue", P95: 12 * time.Millisecond, AllocsPerOp: 120}, 0.2))
+
+	// beyond it
+	err = baselines.Check(Result{Name: "issue", P95: 13 * time.Millisecond, AllocsPerOp: 121}, 0.2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "workload<issue> regressed: p95 13ms exceeds 12ms (baseline 10ms); 121 allocs/op exceeds 120 (baseline 100)")
+}